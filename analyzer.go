@@ -0,0 +1,102 @@
+package errorfinder
+
+import (
+	"go/token"
+	"reflect"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// Analyzer finds the error sentinels and structured error types declared at
+// the top level of each package. It records the defs of each package as a
+// PackageFact, and its result, an *AnalyzerResult, reports both the defs of
+// the package under analysis and those of its dependencies, so that analyzers
+// requiring it need not load the syntax of dependencies themselves. Only with
+// its -report flag does it also report each def as a diagnostic, which would
+// otherwise fail go vet for any package declaring an error.
+var Analyzer = &analysis.Analyzer{
+	Name:       "errorfinder",
+	Doc:        "find error sentinels and structured error types declared at the top level of packages",
+	URL:        "https://pkg.go.dev/github.com/matttproud/errorfinder",
+	Run:        runAnalyzer,
+	ResultType: reflect.TypeOf((*AnalyzerResult)(nil)),
+	FactTypes:  []analysis.Fact{new(PackageFact)},
+}
+
+// reportDefs is the value of Analyzer's -report flag.
+var reportDefs bool
+
+func init() {
+	Analyzer.Flags.BoolVar(&reportDefs, "report", false, "report each def as a diagnostic")
+}
+
+// A PackageFact records the defs of a package that declares any.
+type PackageFact struct {
+	Defs []Def
+}
+
+func (*PackageFact) AFact() {}
+
+func (f *PackageFact) String() string {
+	names := make([]string, len(f.Defs))
+	for i, d := range f.Defs {
+		names[i] = d.Name
+	}
+	return "errors(" + strings.Join(names, ", ") + ")"
+}
+
+// An AnalyzerResult is the result of Analyzer for a package.
+type AnalyzerResult struct {
+	Defs []Def // The package's defs in discovery order.
+
+	// Deps maps the import path of each direct or transitive dependency of
+	// the package that declares defs to them.
+	Deps map[string][]Def
+}
+
+func runAnalyzer(pass *analysis.Pass) (any, error) {
+	src := &source{
+		PkgPath:   pass.Pkg.Path(),
+		Name:      pass.Pkg.Name(),
+		Fset:      pass.Fset,
+		Syntax:    pass.Files,
+		TypesInfo: pass.TypesInfo,
+	}
+	if pass.Module != nil {
+		src.Module = moduleVersion(pass.Module.Path, pass.Module.Version)
+	}
+	var defs []Def
+	for def := range extractDefs([]*source{src}, false) {
+		defs = append(defs, def)
+		if !reportDefs {
+			continue
+		}
+		kind := def.ErrorType.Name()
+		pass.Report(analysis.Diagnostic{
+			Pos:      filePos(pass, def.Position),
+			Category: kind,
+			Message:  kind + " error " + def.Name + " of type " + def.BackingTypeName,
+		})
+	}
+	if len(defs) > 0 {
+		pass.ExportPackageFact(&PackageFact{Defs: defs})
+	}
+	res := &AnalyzerResult{Defs: defs, Deps: make(map[string][]Def)}
+	for _, f := range pass.AllPackageFacts() {
+		if f.Package != pass.Pkg {
+			res.Deps[f.Package.Path()] = f.Fact.(*PackageFact).Defs
+		}
+	}
+	return res, nil
+}
+
+// filePos converts a position in one of the pass's files back to a token.Pos.
+func filePos(pass *analysis.Pass, p token.Position) token.Pos {
+	for _, f := range pass.Files {
+		if tf := pass.Fset.File(f.Pos()); tf != nil && tf.Name() == p.Filename {
+			return tf.Pos(p.Offset)
+		}
+	}
+	return token.NoPos
+}
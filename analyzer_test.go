@@ -0,0 +1,52 @@
+package errorfinder
+
+import (
+	"strconv"
+	"testing"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestAnalyzer(t *testing.T) {
+	setReport(t, true)
+	analysistest.Run(t, analysistest.TestData(), Analyzer, "declared")
+}
+
+func TestAnalyzerQuiet(t *testing.T) {
+	setReport(t, false)
+	analysistest.Run(t, analysistest.TestData(), Analyzer, "quiet")
+}
+
+// setReport sets Analyzer's -report flag for the duration of the test.
+func setReport(t *testing.T, report bool) {
+	old := Analyzer.Flags.Lookup("report").Value.String()
+	if err := Analyzer.Flags.Set("report", strconv.FormatBool(report)); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { Analyzer.Flags.Set("report", old) })
+}
+
+// importsAnalyzer reports the number of defs in each import of a package as
+// reported by Analyzer.
+var importsAnalyzer = &analysis.Analyzer{
+	Name:     "imports",
+	Doc:      "report the errors declared by imports",
+	Requires: []*analysis.Analyzer{Analyzer},
+	Run: func(pass *analysis.Pass) (any, error) {
+		res := pass.ResultOf[Analyzer].(*AnalyzerResult)
+		for _, file := range pass.Files {
+			for _, spec := range file.Imports {
+				path := pass.TypesInfo.PkgNameOf(spec).Imported().Path()
+				if defs, ok := res.Deps[path]; ok {
+					pass.Reportf(spec.Pos(), "%v declares %d errors", path, len(defs))
+				}
+			}
+		}
+		return nil, nil
+	},
+}
+
+func TestPackageFact(t *testing.T) {
+	analysistest.Run(t, analysistest.TestData(), importsAnalyzer, "dependent")
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"iter"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/matttproud/errorfinder"
+)
+
+// failOnConditions are the changes from a baseline that -fail-on can name.
+var failOnConditions = []string{"new-exported", "removed-exported"}
+
+// readBaseline reads the exported defs, named by import path and name,
+// recorded in the baseline file at path, which was written with
+// -format=json.
+func readBaseline(path string) (map[string]bool, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var doc struct {
+		Defs []struct {
+			ExportType string `json:"exportType"`
+			ImportPath string `json:"importPath"`
+			Name       string `json:"name"`
+		} `json:"defs"`
+	}
+	if err := json.Unmarshal(b, &doc); err != nil {
+		return nil, fmt.Errorf("reading baseline %v: %v", path, err)
+	}
+	exported := make(map[string]bool)
+	for _, d := range doc.Defs {
+		if d.ExportType == errorfinder.ExportTypeExported.String() {
+			exported[d.ImportPath+"."+d.Name] = true
+		}
+	}
+	return exported, nil
+}
+
+// exportedDefs yields the elements of defs, recording the exported ones in
+// seen by import path and name.
+func exportedDefs(defs iter.Seq2[errorfinder.Def, error], seen map[string]bool) iter.Seq2[errorfinder.Def, error] {
+	return func(yield func(errorfinder.Def, error) bool) {
+		for d, err := range defs {
+			if err == nil && d.ExportType == errorfinder.ExportTypeExported {
+				seen[defKey(d)] = true
+			}
+			if !yield(d, err) {
+				return
+			}
+		}
+	}
+}
+
+// checkBaseline writes to w each change of the exported defs from baseline
+// to seen that opts.FailOn names, and fails if there are any.
+func checkBaseline(w io.Writer, opts options, baseline, seen map[string]bool) error {
+	if w == nil {
+		w = io.Discard
+	}
+	var n int
+	report := func(condition, format string, defs, others map[string]bool) {
+		if !slices.Contains(opts.FailOn, condition) {
+			return
+		}
+		for _, key := range slices.Sorted(maps.Keys(defs)) {
+			if !others[key] {
+				n++
+				fmt.Fprintf(w, format+"\n", key)
+			}
+		}
+	}
+	report("new-exported", "errorfinder: new exported error %v is not in the baseline", seen, baseline)
+	report("removed-exported", "errorfinder: exported error %v in the baseline was removed", baseline, seen)
+	if n > 0 {
+		return &violationsError{N: n, What: "changes from the baseline " + opts.Baseline}
+	}
+	return nil
+}
+
+// parseFailOn parses the value of -fail-on, a comma-separated list of
+// failOnConditions.
+func parseFailOn(list string) ([]string, error) {
+	var conditions []string
+	for _, c := range strings.Split(list, ",") {
+		if !slices.Contains(failOnConditions, c) {
+			return nil, fmt.Errorf("unknown -fail-on condition %q", c)
+		}
+		conditions = append(conditions, c)
+	}
+	return conditions, nil
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/gob"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/matttproud/errorfinder"
+)
+
+// cacheVersion identifies the layout of cacheFile. Bump it whenever a change
+// to errorfinder.Def would cause older cache files to decode incorrectly.
+const cacheVersion = 4
+
+// cacheFile is the gob-encoded payload of the cache format, which is intended
+// for lossless interchange between errorfinder invocations rather than for
+// consumption by other tools.
+type cacheFile struct {
+	Version int
+	Defs    []errorfinder.Def
+}
+
+// readCache decodes the defs from a file written in the cache format.
+func readCache(r io.Reader) ([]errorfinder.Def, error) {
+	var f cacheFile
+	if err := gob.NewDecoder(r).Decode(&f); err != nil {
+		return nil, fmt.Errorf("decoding cache: %v", err)
+	}
+	if f.Version != cacheVersion {
+		return nil, fmt.Errorf("unsupported cache version %d (want %d)", f.Version, cacheVersion)
+	}
+	return f.Defs, nil
+}
+
+// readCacheFile decodes the defs from the cache file at path, which may have
+// been written with -compress=gzip.
+func readCacheFile(path string) ([]errorfinder.Def, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	br := bufio.NewReader(f)
+	var r io.Reader = br
+	if magic, _ := br.Peek(2); string(magic) == "\x1f\x8b" {
+		zr, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, fmt.Errorf("reading %v: %v", path, err)
+		}
+		defer zr.Close()
+		r = zr
+	}
+	defs, err := readCache(r)
+	if err != nil {
+		return nil, fmt.Errorf("reading %v: %v", path, err)
+	}
+	return defs, nil
+}
+
+type cacheEncoder struct {
+	w    io.Writer
+	file cacheFile
+}
+
+func newCacheEncoder(w io.Writer, _ options) (encoder, error) {
+	return &cacheEncoder{w: w, file: cacheFile{Version: cacheVersion}}, nil
+}
+
+func (e *cacheEncoder) Encode(d errorfinder.Def) error {
+	e.file.Defs = append(e.file.Defs, d)
+	return nil
+}
+
+func (e *cacheEncoder) Close() error {
+	return gob.NewEncoder(e.w).Encode(&e.file)
+}
@@ -0,0 +1,298 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/matttproud/errorfinder"
+)
+
+// A command is a subcommand of errorfinder, such as scan.
+type command struct {
+	name    string
+	args    string // Synopsis of the positional arguments.
+	summary string
+	flags   func(*flag.FlagSet, *options) // Registers the command's own flags.
+	run     func(ctx context.Context, opts options, args []string, stdout io.Writer) error
+}
+
+// commands lists the subcommands in the order they are documented. The first
+// is run when the command line names none.
+var commands = []command{
+	{name: "scan", args: "packages", summary: "write the defs found in packages", flags: scanFlags, run: runScan},
+	{name: "diff", args: "old new", summary: "report defs added, removed, or changed between two cache files, directories, or module versions", flags: noFlags, run: runDiff},
+	{name: "compat", args: "old new", summary: "report the changes to exported errors between two versions that may break callers", flags: noFlags, run: runCompat},
+	{name: "lint", args: "packages", summary: "check the defs found in packages against naming and documentation conventions", flags: noFlags, run: runLint},
+	{name: "stdlib", args: "packages", summary: "report the standard library errors that packages return or compare against", flags: stdlibFlags, run: runStdlib},
+	{name: "gen", args: "packages", summary: "generate a Markdown catalog of the defs found in packages", flags: genFlags, run: runGen},
+	{name: "serve", args: "packages", summary: "serve reports of the defs found in packages over HTTP", flags: serveFlags, run: runServe},
+}
+
+func lookupCommand(name string) (command, bool) {
+	for _, cmd := range commands {
+		if cmd.name == name {
+			return cmd, true
+		}
+	}
+	return command{}, false
+}
+
+// sharedFlags registers the flags common to every command, which select the
+// packages and defs to report.
+func sharedFlags(fs *flag.FlagSet, opts *options) {
+	fs.DurationVar(&opts.Timeout, "timeout", 0, "abandon the scan if it takes longer than `duration` (default no limit)")
+	fs.IntVar(&opts.Depth, "depth", 0, "also scan dependencies up to `n` imports away from the named packages (negative for all)")
+	fs.Func("kind", "report only defs of the comma-separated `kinds`, such as sentinel or structured (default all)", func(list string) error {
+		opts.Kinds = nil
+		for _, name := range strings.Split(list, ",") {
+			kind, err := errorfinder.ParseErrorType(name)
+			if err != nil {
+				return err
+			}
+			opts.Kinds = append(opts.Kinds, kind)
+		}
+		return nil
+	})
+	fs.BoolVar(&opts.ExportedOnly, "exported", false, "report only exported defs")
+	fs.BoolVar(&opts.ExcludeGenerated, "exclude-generated", false, "omit defs declared in generated files")
+	fs.Func("scope", "report the defs declared at `scope`: package, the top level of packages, or all, also in function bodies (default package)", func(scope string) error {
+		switch scope {
+		case "package", "all":
+			opts.Local = scope == "all"
+			return nil
+		}
+		return fmt.Errorf("unknown scope %q", scope)
+	})
+	fs.BoolVar(&opts.Verbose, "v", false, "log the packages loaded, load errors, and durations to standard error")
+	fs.BoolVar(&opts.Debug, "debug", false, "like -v, but also log each package scanned and the files ignored")
+	fs.BoolVar(&opts.Tests, "include-tests", false, "also scan test files and external test packages")
+	fs.BoolVar(&opts.TestsOnly, "only-tests", false, "report only defs declared in test files; implies -include-tests")
+	fs.BoolVar(&opts.Instantiations, "instantiations", false, "list the instantiations of generic error types observed in the scanned packages")
+	fs.BoolVar(&opts.Panics, "panics", false, "list the functions of the scanned packages that panic with each error")
+	fs.BoolVar(&opts.Dead, "dead", false, "flag the exported errors that the scanned packages never return, wrap, or compare")
+	fs.BoolVar(&opts.Reach, "reach", false, "classify each error as reaching callers outside its package (external) or not (internal)")
+	fs.BoolVar(&opts.Propagation, "propagation", false, "list the exported functions of the scanned packages to which each error may propagate")
+	fs.BoolVar(&opts.RefCount, "refcount", false, "count the identifiers in the scanned packages referring to each error")
+	fs.BoolVar(&opts.Consumers, "consumers", false, "list the other scanned packages that refer to each exported error")
+	fs.BoolVar(&opts.Returns, "returns", false, "list the exported functions of the scanned packages that return each error directly")
+	fs.BoolVar(&opts.DocCoverage, "doc-coverage", false, "list the exported functions of the scanned packages that return each error without mentioning it in their doc comments")
+	fs.BoolVar(&opts.ReturnStats, "return-stats", false, "count how often the scanned packages return each error bare and how often they wrap it")
+	fs.BoolVar(&opts.Untested, "untested", false, "flag the exported errors that no test file of the scanned packages refers to; implies -include-tests")
+	fs.BoolVar(&opts.InterfaceMethods, "interfaces", false, "list the methods of the scanned packages' interfaces that may return each error")
+	fs.BoolVar(&opts.Handlers, "handlers", false, "list the HTTP handlers of the scanned packages that may write each error to their responses")
+	fs.BoolVar(&opts.Satisfies, "satisfies", false, "record which well-known interfaces each structured error satisfies")
+	fs.Func("known-interface", "a well-known interface `type` for -satisfies, such as net.Error or \"interface{ Timeout() bool }\"; may be repeated (default net.Error and the interfaces of the Temporary, Timeout, and Unwrap methods)", func(name string) error {
+		opts.KnownInterfaces = append(opts.KnownInterfaces, name)
+		return nil
+	})
+	fs.Func("uses", "comma-separated `list` of usage analyses finding where the scanned packages use each error: returns, is, as, compares, asserts, logs, discards, or keys", func(list string) error {
+		opts.Uses = nil
+		for _, name := range strings.Split(list, ",") {
+			kind := errorfinder.UseKind(name)
+			if !slices.Contains(errorfinder.UseKinds, kind) {
+				return fmt.Errorf("unknown usage analysis %q", name)
+			}
+			opts.Uses = append(opts.Uses, kind)
+		}
+		return nil
+	})
+	fs.Func("code-fields", "comma-separated `list` of the names of the fields holding the codes of structured errors (default Code,ErrCode,Status)", func(list string) error {
+		opts.CodeFields = strings.FieldsFunc(list, func(r rune) bool { return r == ',' || r == ' ' })
+		if opts.CodeFields == nil {
+			opts.CodeFields = []string{} // Detect no code fields.
+		}
+		return nil
+	})
+	fs.Func("loggers", "comma-separated `list` of the full names of the functions and methods that -uses=logs treats as loggers, such as log.Printf or (*log/slog.Logger).Error (default the printing functions and methods of log and log/slog)", func(list string) error {
+		opts.Loggers = strings.FieldsFunc(list, func(r rune) bool { return r == ',' || r == ' ' })
+		if opts.Loggers == nil {
+			opts.Loggers = []string{} // Treat no functions as loggers.
+		}
+		return nil
+	})
+	fs.Func("tags", "comma-separated `list` of build tags to satisfy when selecting files, as for go build -tags", func(list string) error {
+		opts.Tags = strings.FieldsFunc(list, func(r rune) bool { return r == ',' || r == ' ' })
+		return nil
+	})
+	fs.Func("overlay", "scan the file contents named by the JSON overlay `file`, as for go build -overlay, in place of those on disk", func(path string) (err error) {
+		opts.Overlay, err = readOverlay(path)
+		return err
+	})
+	fs.BoolVar(&opts.Strict, "strict", false, "fail if any package or dependency has load, parse, or type errors")
+	fs.StringVar(&opts.TargetsFrom, "targets-from", "", "also read arguments one per line from the file at `path` (- for standard input)")
+}
+
+func noFlags(*flag.FlagSet, *options) {}
+
+func scanFlags(fs *flag.FlagSet, opts *options) {
+	fs.StringVar(&opts.Format, "format", "csv", "output `format`: cache, csv, dot, entrypoints, handlers, hierarchy, html, interfaces, json, jsonl, parquet, proto, prototext, returns, sarif, table, template, tsv, undocumented, uses, xlsx, or yaml")
+	fs.StringVar(&opts.Template, "template", "", "`path` to a text/template executed over the defs for -format=template")
+	fs.BoolVar(&opts.Header, "header", false, "emit a header row for -format=csv, -format=entrypoints, -format=handlers, -format=hierarchy, -format=interfaces, -format=returns, -format=tsv, -format=undocumented, and -format=uses")
+	fs.StringVar(&opts.Columns, "columns", "", "comma-separated `list` of columns for the csv, parquet, table, tsv, and xlsx formats (default kind,export,path,package,name,type,pos,doc,message,deprecated,deprecation,test,initializer,wrapping,const,func,unwrap,wraps,is,as,receiver,instantiations,methods,generated,vendored,module,embedding,call,shadows,wraptypes,codefield,codetype,codes,grpc,chains,panics,instance,reassigned,helper,uses,dead,reach,entrypoints,refcount,consumers,returnedby,undocumented,bare,wrapped,untested,interfaces,handlers,satisfies,retryable,stack,lateinit,fields)")
+	fs.StringVar(&opts.Delimiter, "delimiter", "", "field delimiter `character` for -format=csv (default \",\")")
+	fs.StringVar(&opts.Output, "o", "", "write output atomically to the file at `path` instead of standard output (a directory with -split-by-package)")
+	fs.StringVar(&opts.Color, "color", "auto", "colorize -format=table: `auto`, always, or never")
+	fs.StringVar(&opts.Compress, "compress", "none", "compress the output with `algorithm`: none or gzip")
+	fs.Func("schema", "emit only the fields of schema `version` v1 through v"+strconv.Itoa(schemaVersion)+" (default latest)", func(s string) (err error) {
+		opts.Schema, err = parseSchema(s)
+		return err
+	})
+	fs.BoolVar(&opts.SplitByPackage, "split-by-package", false, "write one file per import path into the -o directory")
+	fs.StringVar(&opts.Baseline, "baseline", "", "compare the exported defs with those of the baseline file at `path`, written with -format=json")
+	fs.Func("fail-on", "comma-separated `list` of changes from -baseline that fail the scan: new-exported or removed-exported (default both)", func(list string) (err error) {
+		opts.FailOn, err = parseFailOn(list)
+		return err
+	})
+}
+
+func genFlags(fs *flag.FlagSet, opts *options) {
+	fs.StringVar(&opts.Output, "o", "", "write the catalog atomically to the file at `path` instead of standard output")
+}
+
+func stdlibFlags(fs *flag.FlagSet, opts *options) {
+	fs.BoolVar(&opts.Header, "header", false, "emit a header row")
+}
+
+func serveFlags(fs *flag.FlagSet, opts *options) {
+	fs.StringVar(&opts.Addr, "addr", "localhost:8080", "listen on the TCP network `address`")
+}
+
+// parseArgs parses a command line, which names a command and its arguments,
+// optionally preceded by shared flags. A command line naming no command is
+// parsed as the arguments of scan, as it was before there were subcommands.
+// Usage and parse errors are written to output.
+func parseArgs(args []string, output io.Writer) (command, options, []string, error) {
+	var opts options
+	// Every flag set binds the same options, so all of them are defined
+	// before any is parsed lest defining one reset values parsed by another.
+	top := flag.NewFlagSet("errorfinder", flag.ContinueOnError)
+	top.SetOutput(output)
+	sharedFlags(top, &opts)
+	scanFlags(top, &opts)
+	top.Usage = func() { usage(top) }
+	sets := make(map[string]*flag.FlagSet, len(commands))
+	for _, cmd := range commands {
+		fs := flag.NewFlagSet("errorfinder "+cmd.name, flag.ContinueOnError)
+		fs.SetOutput(output)
+		sharedFlags(fs, &opts)
+		cmd.flags(fs, &opts)
+		fs.Usage = func() {
+			fmt.Fprintf(fs.Output(), "usage: errorfinder [shared flags] %v [flags] %v\n\n%v.\n\nflags:\n", cmd.name, cmd.args, cmd.summary)
+			fs.PrintDefaults()
+		}
+		sets[cmd.name] = fs
+	}
+	if err := top.Parse(args); err != nil {
+		return command{}, options{}, nil, err
+	}
+	args = top.Args()
+	if len(args) == 0 {
+		return commands[0], opts, args, nil
+	}
+	cmd, ok := lookupCommand(args[0])
+	if !ok {
+		return commands[0], opts, args, nil
+	}
+	fs := sets[cmd.name]
+	if err := fs.Parse(args[1:]); err != nil {
+		return command{}, options{}, nil, err
+	}
+	return cmd, opts, fs.Args(), nil
+}
+
+// readOverlay reads the overlay file at path, a JSON object whose Replace
+// field maps the paths of files to the paths of files with their contents, as
+// accepted by go build -overlay. Relative paths are resolved against the
+// working directory.
+func readOverlay(path string) (map[string][]byte, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var overlay struct{ Replace map[string]string }
+	if err := json.Unmarshal(b, &overlay); err != nil {
+		return nil, fmt.Errorf("parsing overlay %v: %v", path, err)
+	}
+	contents := make(map[string][]byte, len(overlay.Replace))
+	for file, replacement := range overlay.Replace {
+		if replacement == "" {
+			return nil, fmt.Errorf("overlay %v: deleting %v is not supported", path, file)
+		}
+		abs, err := filepath.Abs(file)
+		if err != nil {
+			return nil, err
+		}
+		if contents[abs], err = os.ReadFile(replacement); err != nil {
+			return nil, fmt.Errorf("overlay %v: %v", path, err)
+		}
+	}
+	return contents, nil
+}
+
+// expandTargets returns args with those of the form @path replaced by the
+// lines of the file at path, followed by the lines of the file at from if it
+// is not empty. A from of "-" reads stdin instead. Blank lines and lines
+// beginning with # are ignored.
+func expandTargets(args []string, from string, stdin io.Reader) ([]string, error) {
+	var expanded []string
+	read := func(path string) error {
+		r := stdin
+		if path != "-" {
+			f, err := os.Open(path)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			r = f
+		}
+		sc := bufio.NewScanner(r)
+		for sc.Scan() {
+			line := strings.TrimSpace(sc.Text())
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			expanded = append(expanded, line)
+		}
+		if err := sc.Err(); err != nil {
+			return fmt.Errorf("reading targets from %v: %v", path, err)
+		}
+		return nil
+	}
+	for _, arg := range args {
+		if path, ok := strings.CutPrefix(arg, "@"); ok && path != "" {
+			if err := read(path); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		expanded = append(expanded, arg)
+	}
+	if from != "" {
+		if err := read(from); err != nil {
+			return nil, err
+		}
+	}
+	return expanded, nil
+}
+
+func usage(top *flag.FlagSet) {
+	w := top.Output()
+	fmt.Fprintf(w, "usage: errorfinder [shared flags] [command] [flags] arguments\n\ncommands:\n")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-6v %v\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintf(w, "\nWithout a command, errorfinder runs scan. Run errorfinder command -h for\nthe flags of a command. An argument of the form @path is replaced by the\narguments listed one per line in the file at path.\n\nshared flags:\n")
+	shared := flag.NewFlagSet("", flag.ContinueOnError)
+	shared.SetOutput(w)
+	sharedFlags(shared, new(options))
+	shared.PrintDefaults()
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/matttproud/errorfinder"
+)
+
+// The severities of compatibility issues, from the most to the least severe.
+const (
+	severityBreaking = "breaking" // Breaks callers that use the error as before.
+	severityWarning  = "warning"  // May break callers that depend on details.
+	severityInfo     = "info"     // Breaks only unusual uses.
+)
+
+// A compatIssue describes a change to an exported def between two versions
+// that may break its callers.
+type compatIssue struct {
+	Severity string
+	Key      string // The def's import path and name.
+	What     string
+}
+
+// String describes the issue on a single line, such as
+// "breaking: example.com/p.ErrX: removed exported sentinel".
+func (i compatIssue) String() string {
+	return fmt.Sprintf("%v: %v: %v", i.Severity, i.Key, i.What)
+}
+
+// compatIssues reports the changes to the exported defs of old in new that
+// may break their callers, ordered by import path and name.
+func compatIssues(old, new []errorfinder.Def) []compatIssue {
+	byKey := make(map[string]errorfinder.Def, len(new))
+	for _, d := range new {
+		byKey[defKey(d)] = d
+	}
+	var issues []compatIssue
+	for _, o := range old {
+		if o.ExportType != errorfinder.ExportTypeExported {
+			continue
+		}
+		key := defKey(o)
+		add := func(severity, format string, args ...any) {
+			issues = append(issues, compatIssue{severity, key, fmt.Sprintf(format, args...)})
+		}
+		n, ok := byKey[key]
+		switch {
+		case !ok:
+			add(severityBreaking, "removed exported %v", o.ErrorType.Name())
+			continue
+		case n.ExportType != errorfinder.ExportTypeExported:
+			add(severityBreaking, "unexported %v", o.ErrorType.Name())
+			continue
+		case n.ErrorType != o.ErrorType:
+			add(severityBreaking, "changed from %v to %v", o.ErrorType.Name(), n.ErrorType.Name())
+			continue
+		}
+		if o.ErrorType == errorfinder.ErrorTypeSentinel && n.BackingTypeName != o.BackingTypeName {
+			add(severityBreaking, "changed type from %v to %v", o.BackingTypeName, n.BackingTypeName)
+		}
+		if o.ReceiverKind != "" && n.ReceiverKind != "" && n.ReceiverKind != o.ReceiverKind {
+			add(severityBreaking, "changed receiver kind from %v to %v, changing the errors.As targets that match", o.ReceiverKind, n.ReceiverKind)
+		}
+		oldFields, newFields := fieldTypes(o.Fields), fieldTypes(n.Fields)
+		for _, f := range o.Fields {
+			name, _, _ := strings.Cut(f, " ")
+			switch t, ok := newFields[name]; {
+			case !ok:
+				add(severityBreaking, "removed exported field %v", name)
+			case t != oldFields[name]:
+				add(severityBreaking, "changed type of field %v from %v to %v", name, oldFields[name], t)
+			}
+		}
+		for _, f := range n.Fields {
+			if name, _, _ := strings.Cut(f, " "); oldFields[name] == "" {
+				add(severityInfo, "added exported field %v, breaking unkeyed composite literals", name)
+			}
+		}
+		if n.Message != o.Message {
+			add(severityWarning, "changed Error message from %q to %q", o.Message, n.Message)
+		}
+	}
+	slices.SortStableFunc(issues, func(a, b compatIssue) int { return cmp.Compare(a.Key, b.Key) })
+	return issues
+}
+
+// fieldTypes maps the names of fields, each described as for
+// errorfinder.Def.Fields, to their types.
+func fieldTypes(fields []string) map[string]string {
+	types := make(map[string]string, len(fields))
+	for _, f := range fields {
+		name, t, _ := strings.Cut(f, " ")
+		types[name] = t
+	}
+	return types
+}
+
+// runCompat implements the compat command, which reports the changes to the
+// exported errors of the old version that may break their callers in the new
+// one, each version being a file written with -format=cache, a directory, or
+// a module version. It fails if any change is breaking.
+func runCompat(ctx context.Context, opts options, args []string, stdout io.Writer) error {
+	if len(args) != 2 {
+		return errors.New("compat requires two cache files, directories, or module versions")
+	}
+	old, err := loadDefs(ctx, opts, args[0])
+	if err != nil {
+		return err
+	}
+	new, err := loadDefs(ctx, opts, args[1])
+	if err != nil {
+		return err
+	}
+	var n int
+	for _, issue := range compatIssues(old, new) {
+		if issue.Severity == severityBreaking {
+			n++
+		}
+		if _, err := fmt.Fprintln(stdout, issue); err != nil {
+			return err
+		}
+	}
+	if n > 0 {
+		return &violationsError{N: n, What: "breaking changes"}
+	}
+	return nil
+}
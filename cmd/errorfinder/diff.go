@@ -0,0 +1,211 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+
+	"github.com/matttproud/errorfinder"
+)
+
+// A defChange describes how a def differs between two sets of defs. Old is
+// the zero Def for an added def and New is for a removed one.
+type defChange struct {
+	Old, New errorfinder.Def
+	Added    bool
+	Removed  bool
+	Fields   []string // Names of the columns whose values changed.
+}
+
+// defKey identifies a def across scans independently of its position. Defs
+// declared in function bodies are qualified by the function's name, such as
+// "example.com/p.(*T).M.errLocal".
+func defKey(d errorfinder.Def) string {
+	if d.Func != "" {
+		return d.ImportPath + "." + d.Func + "." + d.Name
+	}
+	return d.ImportPath + "." + d.Name
+}
+
+// diffFields lists the columns compared between versions of a def. Position
+// and documentation are excluded, as they change without affecting callers.
+var diffFields = []string{"kind", "export", "type", "message", "deprecated"}
+
+// diffDefs reports the defs added to, removed from, or changed between old
+// and new, ordered by import path and name.
+func diffDefs(old, new []errorfinder.Def) []defChange {
+	byKey := make(map[string]errorfinder.Def, len(old))
+	for _, d := range old {
+		byKey[defKey(d)] = d
+	}
+	var changes []defChange
+	for _, n := range new {
+		o, ok := byKey[defKey(n)]
+		if !ok {
+			changes = append(changes, defChange{New: n, Added: true})
+			continue
+		}
+		delete(byKey, defKey(n))
+		var fields []string
+		for _, col := range errorfinder.Columns {
+			if slices.Contains(diffFields, col.Name) && col.Value(o) != col.Value(n) {
+				fields = append(fields, col.Name)
+			}
+		}
+		if len(fields) > 0 {
+			changes = append(changes, defChange{Old: o, New: n, Fields: fields})
+		}
+	}
+	for _, o := range byKey {
+		changes = append(changes, defChange{Old: o, Removed: true})
+	}
+	slices.SortFunc(changes, func(a, b defChange) int {
+		return strings.Compare(defKey(a.def()), defKey(b.def()))
+	})
+	return changes
+}
+
+// def returns the most recent version of the changed def.
+func (c defChange) def() errorfinder.Def {
+	if c.Removed {
+		return c.Old
+	}
+	return c.New
+}
+
+// String describes the change on a single line prefixed by +, -, or ~ for an
+// added, removed, or changed def, respectively.
+func (c defChange) String() string {
+	d := c.def()
+	switch {
+	case c.Added:
+		return fmt.Sprintf("+ %v %v", defKey(d), d.ErrorType.Name())
+	case c.Removed:
+		return fmt.Sprintf("- %v %v", defKey(d), d.ErrorType.Name())
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "~ %v", defKey(d))
+	for _, col := range errorfinder.Columns {
+		if slices.Contains(c.Fields, col.Name) {
+			fmt.Fprintf(&b, " %v: %q -> %q", col.Name, col.Value(c.Old), col.Value(c.New))
+		}
+	}
+	return b.String()
+}
+
+// loadDefs returns the defs of an operand of the diff command: a file written
+// with -format=cache, a directory whose packages are scanned, or a module
+// version, such as "example.com/m@v1.2.0", which is downloaded and scanned.
+func loadDefs(ctx context.Context, opts options, arg string) ([]errorfinder.Def, error) {
+	if fi, err := os.Stat(arg); err == nil {
+		if !fi.IsDir() {
+			return readCacheFile(arg)
+		}
+		res, err := scanDir(ctx, opts, arg)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkResult(opts, []string{arg}, res); err != nil {
+			return nil, err
+		}
+		return res.Defs, nil
+	}
+	if path, version, ok := strings.Cut(arg, "@"); ok && path != "" && version != "" {
+		return scanModule(ctx, opts, arg)
+	}
+	return nil, fmt.Errorf("%v is not a cache file, a directory, or a module version", arg)
+}
+
+// scanDir scans the packages in and below dir.
+func scanDir(ctx context.Context, opts options, dir string) (*errorfinder.Result, error) {
+	opts.Dir = dir
+	finder, stop := newFinder(opts, []string{"./..."})
+	defer stop()
+	return finder.Find(ctx)
+}
+
+// scanModule returns the defs found in the packages of the module version
+// mod, such as "example.com/m@v1.2.0", after downloading it. As its files in
+// the module cache are read-only, the build tool may not add missing go.sum
+// entries or download unlisted dependencies, and any package error fails the
+// scan rather than silently dropping defs.
+func scanModule(ctx context.Context, opts options, mod string) ([]errorfinder.Def, error) {
+	opts.Env = moduleEnv(opts.Env)
+	dir, err := downloadModule(ctx, opts, mod)
+	if err != nil {
+		return nil, err
+	}
+	res, err := scanDir(ctx, opts, dir)
+	if err != nil {
+		return nil, fmt.Errorf("scanning %v in %v: %w", mod, dir, err)
+	}
+	if n := len(res.PackageErrors); n > 0 {
+		return nil, fmt.Errorf("scanning %v in %v: %d package errors, the first: %v", mod, dir, n, res.PackageErrors[0])
+	}
+	return res.Defs, nil
+}
+
+// moduleEnv returns env, or the current environment if it is nil, adjusted
+// to load a module in the module cache on its own: outside any workspace and
+// without updating its go.mod or go.sum files.
+func moduleEnv(env []string) []string {
+	if env == nil {
+		env = os.Environ()
+	}
+	var flags []string
+	for _, kv := range env {
+		if v, ok := strings.CutPrefix(kv, "GOFLAGS="); ok {
+			flags = slices.DeleteFunc(strings.Fields(v), func(f string) bool { return strings.HasPrefix(f, "-mod=") })
+		}
+	}
+	flags = append(flags, "-mod=readonly")
+	return append(slices.Clip(env), "GOWORK=off", "GOFLAGS="+strings.Join(flags, " "))
+}
+
+// downloadModule downloads the module version mod, such as
+// "example.com/m@v1.2.0", into the module cache with go mod download and
+// returns the directory holding its files.
+func downloadModule(ctx context.Context, opts options, mod string) (string, error) {
+	cmd := exec.CommandContext(ctx, "go", "mod", "download", "-json", mod)
+	cmd.Env = opts.Env
+	out, err := cmd.Output()
+	var info struct{ Dir, Error string }
+	if jsonErr := json.Unmarshal(out, &info); jsonErr != nil && err == nil {
+		err = jsonErr
+	}
+	switch {
+	case info.Error != "":
+		return "", fmt.Errorf("downloading %v: %v", mod, info.Error)
+	case err != nil:
+		return "", fmt.Errorf("downloading %v: %v", mod, err)
+	}
+	return info.Dir, nil
+}
+
+// runDiff implements the diff command, which compares the defs of two scans,
+// each a file written with -format=cache, a directory, or a module version.
+func runDiff(ctx context.Context, opts options, args []string, stdout io.Writer) error {
+	if len(args) != 2 {
+		return errors.New("diff requires two cache files, directories, or module versions")
+	}
+	old, err := loadDefs(ctx, opts, args[0])
+	if err != nil {
+		return err
+	}
+	new, err := loadDefs(ctx, opts, args[1])
+	if err != nil {
+		return err
+	}
+	for _, c := range diffDefs(old, new) {
+		if _, err := fmt.Fprintln(stdout, c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/matttproud/errorfinder"
+)
+
+// dotEncoder renders a GraphViz graph of the defs, clustered by package, with
+// edges for their relations, from sentinels to the named types they are
+// instances of, and from aliases to the types they alias.
+type dotEncoder struct {
+	w      *bufio.Writer
+	report report
+}
+
+func newDOTEncoder(w io.Writer, _ options) (encoder, error) {
+	return &dotEncoder{w: bufio.NewWriter(w)}, nil
+}
+
+func (e *dotEncoder) Encode(d errorfinder.Def) error {
+	e.report.add(d)
+	return nil
+}
+
+func (e *dotEncoder) Close() error {
+	q := strconv.Quote
+	fmt.Fprintln(e.w, "digraph errors {")
+	fmt.Fprintln(e.w, "\trankdir=LR;")
+	fmt.Fprintln(e.w, "\tnode [fontname=\"monospace\"];")
+	for i, pkg := range e.report.Packages {
+		fmt.Fprintf(e.w, "\tsubgraph cluster_%d {\n", i)
+		fmt.Fprintf(e.w, "\t\tlabel=%v;\n", q(pkg.ImportPath))
+		for _, d := range pkg.Defs {
+			shape := "box"
+			if d.ErrorType == errorfinder.ErrorTypeSentinel {
+				shape = "ellipse"
+			}
+			fmt.Fprintf(e.w, "\t\t%v [label=%v, shape=%v, tooltip=%v];\n", q(d.ImportPath+"."+d.Name), q(d.Name), shape, q(d.Position.String()))
+		}
+		fmt.Fprintln(e.w, "\t}")
+	}
+	for _, pkg := range e.report.Packages {
+		for _, d := range pkg.Defs {
+			from := q(d.ImportPath + "." + d.Name)
+			if d.InstanceOf != "" {
+				fmt.Fprintf(e.w, "\t%v -> %v [label=\"instance of\", style=dashed];\n", from, q(d.InstanceOf))
+			}
+			for _, rel := range relations(d) {
+				fmt.Fprintf(e.w, "\t%v -> %v [label=%v];\n", from, q(rel.Parent), q(rel.Kind))
+			}
+			if d.AliasOf != "" {
+				fmt.Fprintf(e.w, "\t%v -> %v [label=\"alias of\", style=dotted];\n", from, q(d.AliasOf))
+			}
+		}
+	}
+	fmt.Fprintln(e.w, "}")
+	return e.w.Flush()
+}
@@ -0,0 +1,290 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/matttproud/errorfinder"
+)
+
+// An encoder serializes defs to an output stream in a particular format.
+// Close must be called once all defs have been encoded.
+type encoder interface {
+	Encode(errorfinder.Def) error
+	Close() error
+}
+
+// schemaVersion identifies the set of fields emitted by the structured output
+// formats. Increment it once for each release that adds, removes, or changes
+// the meaning of fields; the changes between releases share a version.
+const schemaVersion = 2
+
+// schemaFields records the def fields added since version 1 of the schema,
+// named as a column and as a key of the structured formats, along with the
+// version that added each. Keys without a column belong to the defs or, like
+// packageErrors, to the document as a whole.
+var schemaFields = []struct {
+	column, key string
+	since       int
+}{
+	{"pos", "position", 2},
+	{"doc", "doc", 2},
+	{"message", "message", 2},
+	{"deprecated", "deprecated", 2},
+	{"deprecation", "deprecation", 2},
+	{"", "errorTypeName", 2},
+	{"", "packageErrors", 2},
+	{"test", "test", 2},
+	{"initializer", "initializer", 2},
+	{"wrapping", "wrapping", 2},
+	{"const", "const", 2},
+	{"func", "func", 2},
+	{"unwrap", "unwrap", 2},
+	{"wraps", "wraps", 2},
+	{"is", "is", 2},
+	{"as", "as", 2},
+	{"receiver", "receiverKind", 2},
+	{"instantiations", "instantiations", 2},
+	{"methods", "methods", 2},
+	{"generated", "generated", 2},
+	{"vendored", "vendored", 2},
+	{"module", "vendorModule", 2},
+	{"embedding", "embedding", 2},
+	{"call", "call", 2},
+	{"shadows", "shadowsStdlib", 2},
+	{"wraptypes", "wrapTypes", 2},
+	{"codefield", "codeField", 2},
+	{"codetype", "codeType", 2},
+	{"codes", "codes", 2},
+	{"grpc", "grpcCode", 2},
+	{"chains", "chains", 2},
+	{"panics", "panics", 2},
+	{"instance", "instanceOf", 2},
+	{"reassigned", "reassigned", 2},
+	{"helper", "helper", 2},
+	{"uses", "uses", 2},
+	{"dead", "dead", 2},
+	{"reach", "reach", 2},
+	{"entrypoints", "entryPoints", 2},
+	{"refcount", "refCount", 2},
+	{"consumers", "consumers", 2},
+	{"returnedby", "returnedBy", 2},
+	{"undocumented", "undocumented", 2},
+	{"bare", "returnedBare", 2},
+	{"wrapped", "wrapped", 2},
+	{"untested", "untested", 2},
+	{"interfaces", "interfaceMethods", 2},
+	{"handlers", "handlers", 2},
+	{"satisfies", "satisfies", 2},
+	{"retryable", "retryable", 2},
+	{"stack", "stack", 2},
+	{"lateinit", "lateInit", 2},
+	{"fields", "fields", 2},
+}
+
+// parseSchema parses the value of -schema: "latest" or a version such as
+// "v1".
+func parseSchema(s string) (int, error) {
+	if s == "latest" {
+		return schemaVersion, nil
+	}
+	if v, err := strconv.Atoi(strings.TrimPrefix(s, "v")); err == nil && strings.HasPrefix(s, "v") && v >= 1 && v <= schemaVersion {
+		return v, nil
+	}
+	return 0, fmt.Errorf("unknown schema %q (want v1 through v%d or latest)", s, schemaVersion)
+}
+
+// schema returns the schema version whose fields are emitted.
+func (o options) schema() int {
+	if o.Schema == 0 {
+		return schemaVersion
+	}
+	return o.Schema
+}
+
+// has reports whether the field with the given column or key name belongs to
+// the schema version whose fields are emitted.
+func (o options) has(name string) bool {
+	for _, f := range schemaFields {
+		if name != "" && (f.column == name || f.key == name) {
+			return f.since <= o.schema()
+		}
+	}
+	return true
+}
+
+// buildVersion reports the module version of the running errorfinder binary.
+func buildVersion() string {
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" {
+		return info.Main.Version
+	}
+	return "(devel)"
+}
+
+type format struct {
+	newEncoder func(io.Writer, options) (encoder, error)
+	// streaming formats receive defs in discovery order as soon as they are
+	// extracted instead of sorted once extraction completes.
+	streaming bool
+	ext       string // File name extension, used when splitting output.
+}
+
+var formats = map[string]format{
+	"cache":        {newEncoder: newCacheEncoder, ext: "cache"},
+	"csv":          {newEncoder: newCSVEncoder, ext: "csv"},
+	"dot":          {newEncoder: newDOTEncoder, ext: "dot"},
+	"entrypoints":  {newEncoder: newEntryPointsEncoder, ext: "csv"},
+	"handlers":     {newEncoder: newHandlersEncoder, ext: "csv"},
+	"hierarchy":    {newEncoder: newHierarchyEncoder, streaming: true, ext: "csv"},
+	"html":         {newEncoder: newHTMLEncoder, ext: "html"},
+	"interfaces":   {newEncoder: newInterfacesEncoder, ext: "csv"},
+	"json":         {newEncoder: newJSONEncoder, ext: "json"},
+	"jsonl":        {newEncoder: newJSONLEncoder, streaming: true, ext: "jsonl"},
+	"parquet":      {newEncoder: newParquetEncoder, ext: "parquet"},
+	"proto":        {newEncoder: newProtoEncoder, ext: "pb"},
+	"prototext":    {newEncoder: newPrototextEncoder, ext: "textproto"},
+	"returns":      {newEncoder: newReturnsEncoder, ext: "csv"},
+	"sarif":        {newEncoder: newSARIFEncoder, ext: "sarif"},
+	"table":        {newEncoder: newTableEncoder, ext: "txt"},
+	"template":     {newEncoder: newTemplateEncoder, ext: "txt"},
+	"tsv":          {newEncoder: newTSVEncoder, ext: "tsv"},
+	"undocumented": {newEncoder: newUndocumentedEncoder, ext: "csv"},
+	"uses":         {newEncoder: newUsesEncoder, streaming: true, ext: "csv"},
+	"xlsx":         {newEncoder: newXLSXEncoder, ext: "xlsx"},
+	"yaml":         {newEncoder: newYAMLEncoder, ext: "yaml"},
+}
+
+type csvEncoder struct {
+	w    *csv.Writer
+	cols []errorfinder.Column
+}
+
+func newCSVEncoder(w io.Writer, opts options) (encoder, error) {
+	comma := ','
+	if opts.Delimiter != "" {
+		r, size := utf8.DecodeRuneInString(opts.Delimiter)
+		if size != len(opts.Delimiter) || r == utf8.RuneError || r == '"' || r == '\r' || r == '\n' {
+			return nil, fmt.Errorf("invalid delimiter %q", opts.Delimiter)
+		}
+		comma = r
+	}
+	return newDelimitedEncoder(w, opts, comma)
+}
+
+func newTSVEncoder(w io.Writer, opts options) (encoder, error) {
+	return newDelimitedEncoder(w, opts, '\t')
+}
+
+func newDelimitedEncoder(w io.Writer, opts options, comma rune) (encoder, error) {
+	cols, err := parseColumns(opts.Columns, opts)
+	if err != nil {
+		return nil, err
+	}
+	e := &csvEncoder{w: csv.NewWriter(w), cols: cols}
+	e.w.Comma = comma
+	if opts.Header {
+		header := make([]string, len(cols))
+		for i, col := range cols {
+			header[i] = col.Name
+		}
+		if err := e.w.Write(header); err != nil {
+			return nil, err
+		}
+	}
+	return e, nil
+}
+
+func (e *csvEncoder) Encode(d errorfinder.Def) error { return writeRow(e.w, d, e.cols) }
+
+func (e *csvEncoder) Close() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+// jsonEncoder buffers defs so that they are emitted as a single JSON object
+// alongside the schema and build versions.
+type jsonEncoder struct {
+	w    io.Writer
+	opts options
+	defs []json.RawMessage
+}
+
+func newJSONEncoder(w io.Writer, opts options) (encoder, error) {
+	return &jsonEncoder{w: w, opts: opts, defs: []json.RawMessage{}}, nil
+}
+
+func (e *jsonEncoder) Encode(d errorfinder.Def) error {
+	b, err := marshalDef(d, e.opts)
+	if err != nil {
+		return err
+	}
+	e.defs = append(e.defs, b)
+	return nil
+}
+
+func (e *jsonEncoder) Close() error {
+	enc := json.NewEncoder(e.w)
+	enc.SetIndent("", "\t")
+	doc := struct {
+		SchemaVersion int               `json:"schemaVersion"`
+		Version       string            `json:"version"`
+		Defs          []json.RawMessage `json:"defs"`
+		PackageErrors []packageError    `json:"packageErrors,omitempty"`
+	}{SchemaVersion: e.opts.schema(), Version: buildVersion(), Defs: e.defs}
+	if e.opts.has("packageErrors") {
+		doc.PackageErrors = e.opts.PackageErrors
+	}
+	return enc.Encode(doc)
+}
+
+// marshalDef encodes d as a JSON object with only the fields of the schema
+// version selected by opts.
+func marshalDef(d errorfinder.Def, opts options) ([]byte, error) {
+	b, err := json.Marshal(d)
+	if err != nil || opts.schema() == schemaVersion {
+		return b, err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return nil, err
+	}
+	for key := range fields {
+		if !opts.has(key) {
+			delete(fields, key)
+		}
+	}
+	return json.Marshal(fields)
+}
+
+// jsonlEncoder emits each def as a JSON object on its own line as soon as it
+// is encoded.
+type jsonlEncoder struct {
+	w    io.Writer
+	opts options
+}
+
+func newJSONLEncoder(w io.Writer, opts options) (encoder, error) {
+	return &jsonlEncoder{w: w, opts: opts}, nil
+}
+
+func (e *jsonlEncoder) Encode(d errorfinder.Def) error {
+	b, err := marshalDef(d, e.opts)
+	if err != nil {
+		return err
+	}
+	if _, err := e.w.Write(append(b, '\n')); err != nil {
+		return err
+	}
+	if f, ok := e.w.(interface{ Flush() error }); ok {
+		return f.Flush()
+	}
+	return nil
+}
+
+func (e *jsonlEncoder) Close() error { return nil }
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"cmp"
+	"encoding/csv"
+	"io"
+	"slices"
+
+	"github.com/matttproud/errorfinder"
+)
+
+// funcErrorsEncoder writes the errors related to each function as CSV
+// records of the function and the error, sorted by function.
+type funcErrorsEncoder struct {
+	w      *csv.Writer
+	header []string // Nil unless -header is set.
+	funcs  func(errorfinder.Def) []string
+	rows   [][2]string
+}
+
+// newEntryPointsEncoder writes the exported functions to which each error may
+// propagate.
+func newEntryPointsEncoder(w io.Writer, opts options) (encoder, error) {
+	e := &funcErrorsEncoder{w: csv.NewWriter(w), funcs: func(d errorfinder.Def) []string { return d.EntryPoints }}
+	if opts.Header {
+		e.header = []string{"entrypoint", "error"}
+	}
+	return e, nil
+}
+
+// newReturnsEncoder writes the exported functions that return each error
+// directly.
+func newReturnsEncoder(w io.Writer, opts options) (encoder, error) {
+	e := &funcErrorsEncoder{w: csv.NewWriter(w), funcs: func(d errorfinder.Def) []string { return d.ReturnedBy }}
+	if opts.Header {
+		e.header = []string{"function", "error"}
+	}
+	return e, nil
+}
+
+// newUndocumentedEncoder writes the exported functions that return each error
+// without mentioning it in their doc comments.
+func newUndocumentedEncoder(w io.Writer, opts options) (encoder, error) {
+	e := &funcErrorsEncoder{w: csv.NewWriter(w), funcs: func(d errorfinder.Def) []string { return d.Undocumented }}
+	if opts.Header {
+		e.header = []string{"function", "error"}
+	}
+	return e, nil
+}
+
+// newInterfacesEncoder writes the interface methods that may return each
+// error.
+func newInterfacesEncoder(w io.Writer, opts options) (encoder, error) {
+	e := &funcErrorsEncoder{w: csv.NewWriter(w), funcs: func(d errorfinder.Def) []string { return d.InterfaceMethods }}
+	if opts.Header {
+		e.header = []string{"method", "error"}
+	}
+	return e, nil
+}
+
+// newHandlersEncoder writes the HTTP handlers that may write each error to their
+// responses.
+func newHandlersEncoder(w io.Writer, opts options) (encoder, error) {
+	e := &funcErrorsEncoder{w: csv.NewWriter(w), funcs: func(d errorfinder.Def) []string { return d.Handlers }}
+	if opts.Header {
+		e.header = []string{"handler", "error"}
+	}
+	return e, nil
+}
+
+func (e *funcErrorsEncoder) Encode(d errorfinder.Def) error {
+	for _, fn := range e.funcs(d) {
+		e.rows = append(e.rows, [2]string{fn, d.ImportPath + "." + d.Name})
+	}
+	return nil
+}
+
+func (e *funcErrorsEncoder) Close() error {
+	slices.SortFunc(e.rows, func(a, b [2]string) int {
+		return cmp.Or(cmp.Compare(a[0], b[0]), cmp.Compare(a[1], b[1]))
+	})
+	if e.header != nil {
+		if err := e.w.Write(e.header); err != nil {
+			return err
+		}
+	}
+	for _, row := range e.rows {
+		if err := e.w.Write(row[:]); err != nil {
+			return err
+		}
+	}
+	e.w.Flush()
+	return e.w.Error()
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/matttproud/errorfinder"
+)
+
+// markdownCell escapes s for use in a cell of a Markdown table.
+func markdownCell(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	return strings.ReplaceAll(s, "|", `\|`)
+}
+
+// writeCatalog renders defs as a Markdown document with a table of the defs of
+// each package.
+func writeCatalog(w io.Writer, defs []errorfinder.Def) error {
+	var r report
+	for _, d := range defs {
+		r.add(d)
+	}
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "# Errors\n\n%d sentinels, %d structured error types, and %d constructors in %d packages.\n", r.Sentinels, r.Structured, r.Constructors, len(r.Packages))
+	for _, pkg := range r.Packages {
+		fmt.Fprintf(bw, "\n## %v\n\n", pkg.ImportPath)
+		fmt.Fprintf(bw, "| Name | Kind | Type | Message | Description |\n| --- | --- | --- | --- | --- |\n")
+		for _, d := range pkg.Defs {
+			name := "`" + d.Name + "`"
+			if d.Deprecated {
+				name = "~~" + name + "~~"
+			}
+			desc := d.Doc
+			if d.Deprecated && d.Deprecation != "" {
+				desc = "Deprecated: " + d.Deprecation
+			}
+			fmt.Fprintf(bw, "| %v | %v | `%v` | %v | %v |\n", name, d.ErrorType.Name(), d.BackingTypeName, markdownCell(d.Message), markdownCell(desc))
+		}
+	}
+	return bw.Flush()
+}
+
+// runGen implements the gen command, which writes a Markdown catalog of the
+// defs found in the packages matching args.
+func runGen(ctx context.Context, opts options, args []string, stdout io.Writer) error {
+	finder, stop := newFinder(opts, args)
+	res, err := finder.Find(ctx)
+	stop()
+	if err != nil {
+		return err
+	}
+	if err := checkResult(opts, args, res); err != nil {
+		return err
+	}
+	write := func(w io.Writer) error { return writeCatalog(w, res.Defs) }
+	if opts.Output != "" {
+		return writeFile(opts.Output, write)
+	}
+	return write(stdout)
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"encoding/csv"
+	"io"
+
+	"github.com/matttproud/errorfinder"
+)
+
+// A relation is an edge of the error hierarchy from a def to an error it
+// builds on, named by package path and name.
+type relation struct {
+	Kind   string // How the def builds on the error: embeds, unwraps, or wraps.
+	Parent string
+}
+
+// relations reports the errors that d builds on: those a structured error
+// embeds and unwraps to, and those a sentinel wraps with %w or joins.
+func relations(d errorfinder.Def) []relation {
+	var rels []relation
+	for _, to := range d.Embeds {
+		rels = append(rels, relation{"embeds", to})
+	}
+	kind := "unwraps"
+	if d.ErrorType == errorfinder.ErrorTypeSentinel {
+		kind = "wraps"
+	}
+	for _, to := range d.Wraps {
+		rels = append(rels, relation{kind, to})
+	}
+	return rels
+}
+
+// hierarchyEncoder writes the error hierarchy as CSV records of child,
+// relation, and parent, one for each of the def's relations.
+type hierarchyEncoder struct {
+	w *csv.Writer
+}
+
+func newHierarchyEncoder(w io.Writer, opts options) (encoder, error) {
+	e := &hierarchyEncoder{w: csv.NewWriter(w)}
+	if opts.Header {
+		if err := e.w.Write([]string{"child", "relation", "parent"}); err != nil {
+			return nil, err
+		}
+	}
+	return e, nil
+}
+
+func (e *hierarchyEncoder) Encode(d errorfinder.Def) error {
+	for _, rel := range relations(d) {
+		if err := e.w.Write([]string{d.ImportPath + "." + d.Name, rel.Kind, rel.Parent}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *hierarchyEncoder) Close() error {
+	e.w.Flush()
+	return e.w.Error()
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	_ "embed"
+	"html/template"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/matttproud/errorfinder"
+)
+
+//go:embed report.html
+var reportHTML string
+
+var reportTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"kind":   func(d errorfinder.Def) string { return strings.TrimPrefix(d.ErrorType.String(), "ErrorType") },
+	"export": func(d errorfinder.Def) string { return strings.TrimPrefix(d.ExportType.String(), "ExportType") },
+}).Parse(reportHTML))
+
+type reportPackage struct {
+	ImportPath   string
+	PackageName  string
+	Sentinels    int
+	Structured   int
+	Constructors int
+	Defs         []errorfinder.Def
+}
+
+type report struct {
+	Packages     []*reportPackage
+	Sentinels    int
+	Structured   int
+	Constructors int
+}
+
+func (r *report) add(d errorfinder.Def) {
+	i, ok := slices.BinarySearchFunc(r.Packages, d.ImportPath, func(p *reportPackage, path string) int {
+		return strings.Compare(p.ImportPath, path)
+	})
+	if !ok {
+		r.Packages = slices.Insert(r.Packages, i, &reportPackage{ImportPath: d.ImportPath, PackageName: d.PackageName})
+	}
+	pkg := r.Packages[i]
+	pkg.Defs = append(pkg.Defs, d)
+	switch d.ErrorType {
+	case errorfinder.ErrorTypeSentinel:
+		pkg.Sentinels++
+		r.Sentinels++
+	case errorfinder.ErrorTypeStructured:
+		pkg.Structured++
+		r.Structured++
+	case errorfinder.ErrorTypeConstructor:
+		pkg.Constructors++
+		r.Constructors++
+	}
+}
+
+// htmlEncoder renders a self-contained HTML report grouped by import path.
+type htmlEncoder struct {
+	w      io.Writer
+	report report
+}
+
+func newHTMLEncoder(w io.Writer, _ options) (encoder, error) {
+	return &htmlEncoder{w: w}, nil
+}
+
+func (e *htmlEncoder) Encode(d errorfinder.Def) error {
+	e.report.add(d)
+	return nil
+}
+
+func (e *htmlEncoder) Close() error {
+	return reportTemplate.Execute(e.w, &e.report)
+}
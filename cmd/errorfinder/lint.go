@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/matttproud/errorfinder"
+)
+
+// A lintRule checks defs against a convention.
+type lintRule struct {
+	name string
+	// check describes how d violates the convention or returns the empty
+	// string if it does not.
+	check func(d errorfinder.Def) string
+}
+
+var lintRules = []lintRule{
+	{name: "sentinel-name", check: func(d errorfinder.Def) string {
+		prefix := "Err"
+		if d.ExportType == errorfinder.ExportTypeUnexported {
+			prefix = "err"
+		}
+		if d.ErrorType != errorfinder.ErrorTypeSentinel || strings.HasPrefix(d.Name, prefix) {
+			return ""
+		}
+		return fmt.Sprintf("sentinel %v should be named %v...", d.Name, prefix)
+	}},
+	{name: "structured-name", check: func(d errorfinder.Def) string {
+		if d.ErrorType != errorfinder.ErrorTypeStructured || strings.HasSuffix(d.Name, "Error") {
+			return ""
+		}
+		return fmt.Sprintf("structured error type %v should be named ...Error", d.Name)
+	}},
+	{name: "doc", check: func(d errorfinder.Def) string {
+		if d.ExportType != errorfinder.ExportTypeExported || d.Doc != "" {
+			return ""
+		}
+		return fmt.Sprintf("exported %v should have a doc comment", d.Name)
+	}},
+}
+
+// runLint implements the lint command, which reports each violation of
+// lintRules by the defs found in the packages matching args. It fails if
+// there are any.
+func runLint(ctx context.Context, opts options, args []string, stdout io.Writer) error {
+	finder, stop := newFinder(opts, args)
+	res, err := finder.Find(ctx)
+	stop()
+	if err != nil {
+		return err
+	}
+	if err := checkResult(opts, args, res); err != nil {
+		return err
+	}
+	var n int
+	for _, d := range res.Defs {
+		for _, rule := range lintRules {
+			msg := rule.check(d)
+			if msg == "" {
+				continue
+			}
+			n++
+			if _, err := fmt.Fprintf(stdout, "%v: %v: %v\n", d.Position, rule.name, msg); err != nil {
+				return err
+			}
+		}
+	}
+	if n > 0 {
+		return &violationsError{N: n, What: "lint violations"}
+	}
+	return nil
+}
@@ -1,219 +1,310 @@
 // Binary errorfinder extracts error sentinel and structured error value types
 // from source code at the named import paths or directories (absolute
 // file system paths).
+//
+// Usage:
+//
+//	errorfinder [shared flags] [command] [flags] arguments
+//
+// The commands are scan, which writes the defs in one of many formats; diff,
+// which compares two scans, each saved with -format=cache, a directory, or a
+// module version such as example.com/m@v1.2.0; compat, which reports the
+// changes to exported errors between two such versions that may break
+// callers; lint, which checks defs against naming and documentation
+// conventions; stdlib, which reports the standard library errors that
+// packages return or compare against; gen, which writes a Markdown catalog;
+// and serve, which serves reports over HTTP. Without a command, errorfinder
+// runs scan.
+//
+// Errorfinder exits with status 0 on success, 1 on a usage or internal error,
+// 2 if the packages could not be loaded, and 3 if a command that checks
+// policies found violations, such as lint, or compat on breaking changes.
 package main
 
 import (
-	"cmp"
+	"context"
 	"encoding/csv"
+	"errors"
 	"flag"
 	"fmt"
-	"go/ast"
-	"go/types"
 	"io"
 	"iter"
 	"log"
+	"log/slog"
 	"os"
+	"os/signal"
 	"slices"
+	"strings"
 
-	"golang.org/x/tools/go/packages"
+	"github.com/matttproud/errorfinder"
 )
 
-var errorInterface = types.Universe.Lookup("error").Type().Underlying().(*types.Interface)
+// parseColumns resolves a comma-separated list of column names. An empty
+// list selects every column of the schema version selected by opts in their
+// default order.
+func parseColumns(list string, opts options) ([]errorfinder.Column, error) {
+	var cols []errorfinder.Column
+	if list == "" {
+		for _, col := range errorfinder.Columns {
+			if opts.has(col.Name) {
+				cols = append(cols, col)
+			}
+		}
+		return cols, nil
+	}
+	for _, name := range strings.Split(list, ",") {
+		i := slices.IndexFunc(errorfinder.Columns, func(c errorfinder.Column) bool { return c.Name == name })
+		if i < 0 {
+			return nil, fmt.Errorf("unknown column %q", name)
+		}
+		if !opts.has(name) {
+			return nil, fmt.Errorf("column %q is not in schema v%d", name, opts.schema())
+		}
+		cols = append(cols, errorfinder.Columns[i])
+	}
+	return cols, nil
+}
 
-func isErrorType(t types.Type) bool {
-	return types.Implements(t, errorInterface)
+// writeRow writes the selected columns of d as a CSV record.
+func writeRow(enc *csv.Writer, d errorfinder.Def, cols []errorfinder.Column) error {
+	data := make([]string, len(cols))
+	for i, col := range cols {
+		data[i] = col.Value(d)
+	}
+	return enc.Write(data)
 }
 
-//go:generate stringer -type=ErrorType
-type errorType int
+type options struct {
+	errorfinder.Config // Selection of the packages and defs to report.
 
-const (
-	errorTypeUnknown errorType = iota
-	errorTypeSentinel
-	errorTypeStructured
-)
+	Format    string
+	Template  string // Path to the template for the template format.
+	Header    bool   // Emit a header row in the CSV and TSV formats.
+	Columns   string // Comma-separated tabular format columns; empty means all.
+	Delimiter string // Field delimiter for the CSV format; empty means comma.
+	Output    string // Path of the output file; empty means standard output.
+	Color     string // Colorize the table format: auto, always, or never.
+	Compress  string // Compression applied to the output: none or gzip.
+	Schema    int    // Schema version whose fields are emitted; zero means the latest.
+	Addr      string // Listen address of the serve command.
 
-//go:generate stringer -type=ExportType
-type exportType int
+	Progress io.Writer // Terminal showing the progress of the scan; nil means none.
+	Verbose  bool      // Log the progress of the scan.
+	Debug    bool      // Log the progress of the scan in detail.
 
-const (
-	exportTypeUnknown exportType = iota
-	exportTypeExported
-	exportTypeUnexported
-)
+	Strict bool      // Fail if any package has errors.
+	Stderr io.Writer // Receives package errors; nil discards them.
 
-type def struct {
-	errorType
-	exportType
-	ImportPath      string
-	PackageName     string
-	Name            string
-	BackingTypeName string
-}
+	// PackageErrors holds the errors of the loaded packages for the
+	// structured formats to report alongside the defs.
+	PackageErrors []packageError
 
-const escapes = "" // Convenient code formatting with Markdown.
+	// TargetsFrom names a file, or - for standard input, listing further
+	// arguments one per line.
+	TargetsFrom string
 
-func (d def) Write(enc *csv.Writer) error {
-	data := []string{
-		d.errorType.String(),
-		d.exportType.String(),
-		escapes + d.ImportPath + escapes,
-		d.PackageName,
-		escapes + d.Name + escapes,
-		d.BackingTypeName,
-	}
-	return enc.Write(data)
+	// SplitByPackage writes one file per import path beneath the Output
+	// directory.
+	SplitByPackage bool
+
+	// Baseline names a file written with -format=json recording the
+	// exported defs, and FailOn lists the changes from it that fail the
+	// scan, such as "new-exported".
+	Baseline string
+	FailOn   []string
 }
 
-func compareDef(a, b def) int {
-	switch v := cmp.Compare(a.errorType, b.errorType); v {
-	case -1, 1:
-		return v
-	}
-	switch v := cmp.Compare(a.exportType, b.exportType); v {
-	case -1, 1:
-		return v
-	}
-	switch v := cmp.Compare(a.ImportPath, b.ImportPath); v {
-	case -1, 1:
-		return v
-	}
-	switch v := cmp.Compare(a.PackageName, b.PackageName); v {
-	case -1, 1:
-		return v
+// encode writes defs to w in format f, stopping at the first error yielded by
+// defs. Defs are sorted first unless the format streams them.
+func encode(w io.Writer, f format, opts options, defs iter.Seq2[errorfinder.Def, error]) (err error) {
+	cw, closeCompressor, err := compress(w, opts.Compress)
+	if err != nil {
+		return err
 	}
-	switch v := cmp.Compare(a.Name, b.Name); v {
-	case -1, 1:
-		return v
+	defer func() {
+		if cErr := closeCompressor(); cErr != nil && err == nil {
+			err = fmt.Errorf("compressing output: %v", cErr)
+		}
+	}()
+	enc, err := f.newEncoder(cw, opts)
+	if err != nil {
+		return err
 	}
-	return cmp.Compare(a.BackingTypeName, b.BackingTypeName)
-}
-
-type searchTree struct {
-	Decl ast.Decl
-	Info *types.Info
-	Pkg  *packages.Package
-}
-
-func topLevelDecls(pkgs []*packages.Package) iter.Seq[searchTree] {
-	return func(yield func(searchTree) bool) {
-		for _, pkg := range pkgs {
-			for _, file := range pkg.Syntax {
-				for _, decl := range file.Decls {
-					if !yield(searchTree{decl, pkg.TypesInfo, pkg}) {
-						return
-					}
-				}
+	defer func() {
+		if encErr := enc.Close(); encErr != nil && err == nil {
+			err = fmt.Errorf("writing %v: %v", opts.Format, encErr)
+		}
+	}()
+	if !f.streaming {
+		var sorted []errorfinder.Def
+		for def, err := range defs {
+			if err != nil {
+				return err
 			}
+			sorted = append(sorted, def)
 		}
+		slices.SortFunc(sorted, errorfinder.Compare)
+		defs = values(sorted)
 	}
-}
-
-func expType(id *ast.Ident) exportType {
-	if ast.IsExported(id.Name) {
-		return exportTypeExported
-	}
-	return exportTypeUnexported
-}
-
-func extractSentinels(tree searchTree) iter.Seq[def] {
-	return func(yield func(def) bool) {
-		genDecl, ok := tree.Decl.(*ast.GenDecl)
-		if !ok {
-			return
+	for def, err := range defs {
+		if err != nil {
+			return err
 		}
-		for _, s := range genDecl.Specs {
-			valueSpec, ok := s.(*ast.ValueSpec)
-			if !ok {
-				continue
-			}
-			for _, n := range valueSpec.Names {
-				if !isErrorType(tree.Info.TypeOf(n)) {
-					continue
-				}
-				def := def{
-					errorType:       errorTypeSentinel,
-					exportType:      expType(n),
-					ImportPath:      tree.Pkg.PkgPath,
-					PackageName:     tree.Pkg.Name,
-					Name:            n.Name,
-					BackingTypeName: tree.Info.Defs[n].Type().String(),
-				}
-				if !yield(def) {
-					return
-				}
-			}
+		if err := enc.Encode(def); err != nil {
+			return err
 		}
 	}
+	return nil
 }
 
-func extractStructured(tree searchTree) iter.Seq[def] {
-	return func(yield func(def) bool) {
-		genDecl, ok := tree.Decl.(*ast.GenDecl)
-		if !ok {
-			return
-		}
-		for _, s := range genDecl.Specs {
-			typeSpec, ok := s.(*ast.TypeSpec)
-			if !ok {
-				continue
-			}
-			if !isErrorType(tree.Info.TypeOf(typeSpec.Name)) {
-				continue
-			}
-			def := def{
-				errorType:       errorTypeStructured,
-				exportType:      expType(typeSpec.Name),
-				ImportPath:      tree.Pkg.PkgPath,
-				PackageName:     tree.Pkg.Name,
-				Name:            typeSpec.Name.Name,
-				BackingTypeName: tree.Info.Defs[typeSpec.Name].Type().String(),
-			}
-			if !yield(def) {
+// values yields the elements of defs without error.
+func values(defs []errorfinder.Def) iter.Seq2[errorfinder.Def, error] {
+	return func(yield func(errorfinder.Def, error) bool) {
+		for _, def := range defs {
+			if !yield(def, nil) {
 				return
 			}
 		}
 	}
 }
 
-func run(args []string, out io.Writer) (err error) {
-	cfg := &packages.Config{
-		Mode:  packages.NeedName | packages.NeedFiles | packages.NeedImports | packages.NeedDeps | packages.NeedTypes | packages.NeedSyntax | packages.NeedTypesInfo,
-		Tests: false,
+// runScan implements the scan command, which writes the defs found in the
+// packages matching args in the selected format.
+func runScan(ctx context.Context, opts options, args []string, stdout io.Writer) error {
+	f, ok := formats[opts.Format]
+	if !ok {
+		return fmt.Errorf("unknown format %q", opts.Format)
+	}
+	if opts.SplitByPackage && opts.Output == "" {
+		return errors.New("-split-by-package requires -o")
+	}
+	if len(opts.FailOn) > 0 && opts.Baseline == "" {
+		return errors.New("-fail-on requires -baseline")
+	}
+	var baseline map[string]bool
+	if opts.Baseline != "" {
+		var err error
+		if baseline, err = readBaseline(opts.Baseline); err != nil {
+			return err
+		}
+		if opts.FailOn == nil {
+			opts.FailOn = failOnConditions
+		}
+	}
+	// Fail fast on invalid options before the potentially slow load.
+	if err := encode(io.Discard, f, opts, values(nil)); err != nil {
+		return err
 	}
-	pkgs, err := packages.Load(cfg, args...)
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+	finder, stop := newFinder(opts, args)
+	defer stop()
+	pkgs, err := finder.Load(ctx)
 	if err != nil {
-		return fmt.Errorf("loading packages: %v", err)
+		return err
 	}
-	enc := csv.NewWriter(out)
-	defer enc.Flush()
+	opts.PackageErrors = packageErrors(pkgs)
 	defer func() {
-		if encErr := enc.Error(); encErr != nil && err == nil {
-			err = fmt.Errorf("writing CSV: %v", encErr)
-		}
+		stop()
+		writePackageErrors(opts.Stderr, opts.PackageErrors)
 	}()
-	var defs []def
-	for tree := range topLevelDecls(pkgs) {
-		for def := range extractSentinels(tree) {
-			defs = append(defs, def)
-		}
-		for def := range extractStructured(tree) {
-			defs = append(defs, def)
-		}
+	if err := strictError(opts, args, opts.PackageErrors); err != nil {
+		return err
 	}
-	slices.SortFunc(defs, compareDef)
-	for _, def := range defs {
-		if err := def.Write(enc); err != nil {
-			return err
-		}
+	seen := make(map[string]bool)
+	defs := exportedDefs(finder.Extract(ctx, pkgs), seen)
+	switch {
+	case opts.SplitByPackage:
+		err = writeSplit(opts.Output, f, opts, defs)
+	case opts.Output != "":
+		err = writeFile(opts.Output, func(w io.Writer) error {
+			return encode(w, f, opts, defs)
+		})
+	default:
+		err = encode(stdout, f, opts, defs)
 	}
-	return nil
+	if err != nil || opts.Baseline == "" {
+		return err
+	}
+	return checkBaseline(opts.Stderr, opts, baseline, seen)
+}
+
+// Exit codes of errorfinder.
+const (
+	exitOK         = 0
+	exitError      = 1 // A usage or internal error.
+	exitLoad       = 2 // The packages could not be loaded.
+	exitViolations = 3 // Defs violate a policy checked by the command.
+)
+
+// A violationsError reports that a command found N defs violating a policy,
+// such as those of the lint command.
+type violationsError struct {
+	N    int
+	What string // Plural description of the violations.
+}
+
+func (e *violationsError) Error() string {
+	return fmt.Sprintf("found %d %v", e.N, e.What)
+}
+
+// exitCode maps an error returned by a command to the exit code reported for
+// it.
+func exitCode(err error) int {
+	var (
+		loadErr       *errorfinder.LoadError
+		violationsErr *violationsError
+	)
+	switch {
+	case err == nil:
+		return exitOK
+	case errors.As(err, &loadErr):
+		return exitLoad
+	case errors.As(err, &violationsErr):
+		return exitViolations
+	}
+	return exitError
+}
+
+// newLogger returns a logger writing structured records to w at the level
+// selected by opts or nil if logging was not requested.
+func newLogger(opts options, w io.Writer) *slog.Logger {
+	level := slog.LevelInfo
+	switch {
+	case opts.Debug:
+		level = slog.LevelDebug
+	case !opts.Verbose:
+		return nil
+	}
+	return slog.New(slog.NewTextHandler(w, &slog.HandlerOptions{Level: level}))
 }
 
 func main() {
-	flag.Parse()
-	if err := run(flag.Args(), os.Stdout); err != nil {
-		log.Fatalln(err)
+	cmd, opts, args, err := parseArgs(os.Args[1:], os.Stderr)
+	if errors.Is(err, flag.ErrHelp) {
+		os.Exit(exitOK)
+	}
+	if err != nil {
+		os.Exit(exitError)
+	}
+	if args, err = expandTargets(args, opts.TargetsFrom, os.Stdin); err != nil {
+		log.Println(err)
+		os.Exit(exitError)
+	}
+	opts.Stderr = os.Stderr
+	opts.Logger = newLogger(opts, os.Stderr)
+	// Progress would garble logs and output shown on the same terminal.
+	if opts.Logger == nil && isTerminal(os.Stderr) && (opts.Output != "" || !isTerminal(os.Stdout)) {
+		opts.Progress = os.Stderr
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	err = cmd.run(ctx, opts, args, os.Stdout)
+	stop()
+	if err != nil {
+		log.Println(err)
 	}
+	os.Exit(exitCode(err))
 }
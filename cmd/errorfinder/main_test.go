@@ -1,7 +1,1563 @@
 package main
 
-import "testing"
+import (
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"go/token"
+	"io"
+	"io/fs"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
 
-func Test(t *testing.T) {
-	t.Skip("nothing yet")
+	"github.com/matttproud/errorfinder"
+)
+
+const (
+	ubootPath    = "github.com/matttproud/errorfinder/testdata/uboot"
+	taxonomyPath = "github.com/matttproud/errorfinder/testdata/taxonomy"
+)
+
+// ubootFile returns the absolute path of the uboot test package's source file.
+func ubootFile(t *testing.T) string {
+	t.Helper()
+	file, err := filepath.Abs(filepath.Join("..", "..", "testdata", "uboot", "uboot.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
+
+func TestRunCSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := runScan(context.Background(), options{Format: "csv"}, []string{"../../testdata/uboot"}, &buf); err != nil {
+		t.Fatalf("runScan(...) = %v, want nil", err)
+	}
+	file := ubootFile(t)
+	want := strings.Join([]string{
+		"ErrorTypeSentinel,ExportTypeExported," + ubootPath + ",uboat,ErrSentinel,error," + file + ":5:5,,\"days of no horizon, claustrophobia, condition red\",false,,false,errors.New,false,false,,,,false,false,,,,false,false,,,\"errors.New(\"\"days of no horizon, claustrophobia, condition red\"\")\",,,,,,,,,,false,,,false,,,0,,,,0,0,false,,,,unknown,false,false,",
+		"ErrorTypeStructured,ExportTypeExported," + ubootPath + ",uboat,StructuredError," + ubootPath + ".StructuredError," + file + ":9:6,,don't crash,false,,false,,false,false,,,,false,false,value,,,false,false,,,,,,,,,,,,,false,,,false,,,0,,,,0,0,false,,,,unknown,false,false,",
+		"",
+	}, "\n")
+	if got := buf.String(); got != want {
+		t.Errorf("runScan(...) wrote:\n%v\nwant:\n%v", got, want)
+	}
+}
+
+func TestRunCSVColumns(t *testing.T) {
+	var buf bytes.Buffer
+	opts := options{Format: "csv", Header: true, Columns: "name,kind"}
+	if err := runScan(context.Background(), opts, []string{"../../testdata/uboot"}, &buf); err != nil {
+		t.Fatalf("runScan(...) = %v, want nil", err)
+	}
+	want := `name,kind
+ErrSentinel,ErrorTypeSentinel
+StructuredError,ErrorTypeStructured
+`
+	if got := buf.String(); got != want {
+		t.Errorf("runScan(...) wrote:\n%v\nwant:\n%v", got, want)
+	}
+}
+
+func TestRunDelimited(t *testing.T) {
+	for _, test := range []struct {
+		opts options
+		want string
+	}{
+		{
+			opts: options{Format: "tsv", Columns: "name,kind"},
+			want: "ErrSentinel\tErrorTypeSentinel\nStructuredError\tErrorTypeStructured\n",
+		},
+		{
+			opts: options{Format: "csv", Columns: "name,kind", Delimiter: ";"},
+			want: "ErrSentinel;ErrorTypeSentinel\nStructuredError;ErrorTypeStructured\n",
+		},
+	} {
+		var buf bytes.Buffer
+		if err := runScan(context.Background(), test.opts, []string{"../../testdata/uboot"}, &buf); err != nil {
+			t.Fatalf("runScan(%+v, ...) = %v, want nil", test.opts, err)
+		}
+		if got := buf.String(); got != test.want {
+			t.Errorf("runScan(%+v, ...) wrote %q, want %q", test.opts, got, test.want)
+		}
+	}
+}
+
+func TestRunInvalidDelimiter(t *testing.T) {
+	for _, delim := range []string{";;", "\"", "\n"} {
+		if err := runScan(context.Background(), options{Format: "csv", Delimiter: delim}, []string{"../../testdata/uboot"}, new(bytes.Buffer)); err == nil {
+			t.Errorf("runScan(..., Delimiter: %q) = nil, want error", delim)
+		}
+	}
+}
+
+func TestParseColumnsUnknown(t *testing.T) {
+	if _, err := parseColumns("name,bogus", options{}); err == nil {
+		t.Error("parseColumns(\"name,bogus\") = nil, want error")
+	}
+}
+
+func TestRunJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := runScan(context.Background(), options{Format: "json"}, []string{"../../testdata/uboot"}, &buf); err != nil {
+		t.Fatalf("runScan(...) = %v, want nil", err)
+	}
+	var out struct {
+		SchemaVersion int              `json:"schemaVersion"`
+		Version       string           `json:"version"`
+		Defs          []map[string]any `json:"defs"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatalf("json.Unmarshal(...) = %v, want nil", err)
+	}
+	if out.SchemaVersion != schemaVersion || out.Version == "" {
+		t.Errorf("runScan(...) stamped schemaVersion %d, version %q; want %d, non-empty", out.SchemaVersion, out.Version, schemaVersion)
+	}
+	got := out.Defs
+	want := []map[string]string{
+		{
+			"errorType":       "ErrorTypeSentinel",
+			"exportType":      "ExportTypeExported",
+			"importPath":      ubootPath,
+			"packageName":     "uboat",
+			"name":            "ErrSentinel",
+			"backingTypeName": "error",
+			"position":        ubootFile(t) + ":5:5",
+			"message":         "days of no horizon, claustrophobia, condition red",
+		},
+		{
+			"errorType":       "ErrorTypeStructured",
+			"exportType":      "ExportTypeExported",
+			"importPath":      ubootPath,
+			"packageName":     "uboat",
+			"name":            "StructuredError",
+			"backingTypeName": ubootPath + ".StructuredError",
+			"position":        ubootFile(t) + ":9:6",
+		},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("runScan(...) emitted %d defs, want %d", len(got), len(want))
+	}
+	for i := range want {
+		for k, v := range want[i] {
+			if got[i][k] != v {
+				t.Errorf("def %d field %q = %q, want %q", i, k, got[i][k], v)
+			}
+		}
+	}
+}
+
+func TestRunDoc(t *testing.T) {
+	var buf bytes.Buffer
+	if err := runScan(context.Background(), options{Format: "json"}, []string{"../../testdata/taxonomy"}, &buf); err != nil {
+		t.Fatalf("runScan(...) = %v, want nil", err)
+	}
+	var out struct {
+		Defs []struct {
+			Name string `json:"name"`
+			Doc  string `json:"doc"`
+		} `json:"defs"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatalf("json.Unmarshal(...) = %v, want nil", err)
+	}
+	docs := make(map[string]string)
+	for _, d := range out.Defs {
+		docs[d.Name] = d.Doc
+	}
+	for name, want := range map[string]string{
+		"ErrSyntax":   "ErrSyntax indicates malformed input.\n",
+		"ParseError":  "ParseError records where parsing failed.\n",
+		"ConfigError": "",
+	} {
+		if got := docs[name]; got != want {
+			t.Errorf("doc of %v = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestRunMessage(t *testing.T) {
+	var buf bytes.Buffer
+	if err := runScan(context.Background(), options{Format: "csv", Columns: "name,message"}, []string{"../../testdata/uboot", "../../testdata/taxonomy"}, &buf); err != nil {
+		t.Fatalf("runScan(...) = %v, want nil", err)
+	}
+	for _, want := range []string{
+		"ErrSentinel,\"days of no horizon, claustrophobia, condition red\"\n",
+		"ErrSyntax,syntax error\n",
+		"ErrUnsupported,taxonomy: unsupported %w\n",
+		"DefaultConfigError,\n",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("runScan(...) output does not contain %q:\n%v", want, buf.String())
+		}
+	}
+}
+
+func TestRunDeprecation(t *testing.T) {
+	var buf bytes.Buffer
+	if err := runScan(context.Background(), options{Format: "csv", Columns: "name,deprecated,deprecation"}, []string{"../../testdata/taxonomy"}, &buf); err != nil {
+		t.Fatalf("runScan(...) = %v, want nil", err)
+	}
+	for _, want := range []string{
+		"ErrUnsupported,true,Use [errors.ErrUnsupported] instead.\n",
+		"ErrSyntax,false,\n",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("runScan(...) output does not contain %q:\n%v", want, buf.String())
+		}
+	}
+}
+
+func TestRunSchema(t *testing.T) {
+	var buf bytes.Buffer
+	if err := runScan(context.Background(), options{Format: "csv", Header: true, Schema: 1}, []string{"../../testdata/uboot"}, &buf); err != nil {
+		t.Fatalf("runScan(...) = %v, want nil", err)
+	}
+	if got, want := strings.SplitN(buf.String(), "\n", 2)[0], "kind,export,path,package,name,type"; got != want {
+		t.Errorf("runScan(...) wrote header %q, want %q", got, want)
+	}
+
+	buf.Reset()
+	if err := runScan(context.Background(), options{Format: "jsonl", Schema: 1}, []string{"../../testdata/uboot"}, &buf); err != nil {
+		t.Fatalf("runScan(...) = %v, want nil", err)
+	}
+	var def map[string]any
+	if err := json.Unmarshal(bytes.SplitN(buf.Bytes(), []byte("\n"), 2)[0], &def); err != nil {
+		t.Fatalf("json.Unmarshal(...) = %v, want nil", err)
+	}
+	keys := slices.Sorted(maps.Keys(def))
+	if want := []string{"backingTypeName", "errorType", "exportType", "importPath", "name", "packageName"}; !slices.Equal(keys, want) {
+		t.Errorf("runScan(...) emitted keys %v, want %v", keys, want)
+	}
+
+	if err := runScan(context.Background(), options{Format: "csv", Columns: "name,doc", Schema: 1}, []string{"../../testdata/uboot"}, new(bytes.Buffer)); err == nil {
+		t.Error("runScan(...) with a column outside the schema = nil, want error")
+	}
+}
+
+func TestParseSchema(t *testing.T) {
+	for s, want := range map[string]int{"v1": 1, "v2": 2, "latest": schemaVersion} {
+		if got, err := parseSchema(s); got != want || err != nil {
+			t.Errorf("parseSchema(%q) = %v, %v; want %v, nil", s, got, err, want)
+		}
+	}
+	for _, s := range []string{"", "1", "v0", "v" + strconv.Itoa(schemaVersion+1), "vx"} {
+		if _, err := parseSchema(s); err == nil {
+			t.Errorf("parseSchema(%q) = _, nil; want error", s)
+		}
+	}
+}
+
+func TestRunJSONL(t *testing.T) {
+	var buf bytes.Buffer
+	if err := runScan(context.Background(), options{Format: "jsonl"}, []string{"../../testdata/uboot"}, &buf); err != nil {
+		t.Fatalf("runScan(...) = %v, want nil", err)
+	}
+	names := make(map[string]bool)
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var rec map[string]any
+		if err := json.Unmarshal([]byte(line), &rec); err != nil {
+			t.Fatalf("json.Unmarshal(%q) = %v, want nil", line, err)
+		}
+		names[rec["name"].(string)] = true
+	}
+	for _, name := range []string{"ErrSentinel", "StructuredError"} {
+		if !names[name] {
+			t.Errorf("runScan(...) did not emit %v", name)
+		}
+	}
+}
+
+func TestRunHTML(t *testing.T) {
+	var buf bytes.Buffer
+	if err := runScan(context.Background(), options{Format: "html"}, []string{"../../testdata/uboot"}, &buf); err != nil {
+		t.Fatalf("runScan(...) = %v, want nil", err)
+	}
+	for _, want := range []string{
+		"<code>" + ubootPath + "</code>",
+		"1 sentinels, 1 structured error types",
+		"<code>ErrSentinel</code>",
+		"<code>StructuredError</code>",
+		"<code>" + ubootFile(t) + ":9:6</code>",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("runScan(...) output does not contain %q", want)
+		}
+	}
+}
+
+func TestRunProto(t *testing.T) {
+	var buf bytes.Buffer
+	if err := runScan(context.Background(), options{Format: "proto"}, []string{"../../testdata/uboot"}, &buf); err != nil {
+		t.Fatalf("runScan(...) = %v, want nil", err)
+	}
+	// Walk the repeated Inventory.defs field and collect each Def.name.
+	next := func(b []byte) (tag uint64, val []byte, rest []byte) {
+		tag, n := binary.Uvarint(b)
+		b = b[n:]
+		if tag&7 == wireVarint {
+			_, n := binary.Uvarint(b)
+			return tag, nil, b[n:]
+		}
+		l, n := binary.Uvarint(b)
+		b = b[n:]
+		return tag, b[:l], b[l:]
+	}
+	var names []string
+	for b := buf.Bytes(); len(b) > 0; {
+		var tag uint64
+		var msg []byte
+		tag, msg, b = next(b)
+		if tag>>3 != 1 {
+			continue
+		}
+		for len(msg) > 0 {
+			var tag uint64
+			var val []byte
+			tag, val, msg = next(msg)
+			if tag>>3 == 5 {
+				names = append(names, string(val))
+			}
+		}
+	}
+	if want := []string{"ErrSentinel", "StructuredError"}; !slices.Equal(names, want) {
+		t.Errorf("runScan(...) encoded names %v, want %v", names, want)
+	}
+}
+
+func TestRunPrototext(t *testing.T) {
+	var buf bytes.Buffer
+	if err := runScan(context.Background(), options{Format: "prototext"}, []string{"../../testdata/uboot"}, &buf); err != nil {
+		t.Fatalf("runScan(...) = %v, want nil", err)
+	}
+	want := `schema_version: ` + strconv.Itoa(schemaVersion) + `
+version: "(devel)"
+defs {
+  error_type: ERROR_TYPE_SENTINEL
+  export_type: EXPORT_TYPE_EXPORTED
+  import_path: "` + ubootPath + `"
+  package_name: "uboat"
+  name: "ErrSentinel"
+  backing_type_name: "error"
+  position: "` + ubootFile(t) + `:5:5"
+  message: "days of no horizon, claustrophobia, condition red"
+  error_type_name: "sentinel"
+  initializer: "errors.New"
+  call: "errors.New(\"days of no horizon, claustrophobia, condition red\")"
+  retryable: "unknown"
+}
+`
+	if got := buf.String(); !strings.HasPrefix(got, want) {
+		t.Errorf("runScan(...) wrote:\n%v\nwant prefix:\n%v", got, want)
+	}
+}
+
+func TestQuoteProtoText(t *testing.T) {
+	for _, test := range []struct {
+		in, want string
+	}{
+		{"", `""`},
+		{"a.B[T]", `"a.B[T]"`},
+		{`"\`, `"\"\\"`},
+		{"a\nb\x01", `"a\nb\001"`},
+	} {
+		if got := quoteProtoText(test.in); got != test.want {
+			t.Errorf("quoteProtoText(%q) = %v, want %v", test.in, got, test.want)
+		}
+	}
+}
+
+func TestRunYAML(t *testing.T) {
+	var buf bytes.Buffer
+	if err := runScan(context.Background(), options{Format: "yaml"}, []string{"../../testdata/uboot"}, &buf); err != nil {
+		t.Fatalf("runScan(...) = %v, want nil", err)
+	}
+	want := `schemaVersion: ` + strconv.Itoa(schemaVersion) + `
+version: "(devel)"
+packages:
+  "` + ubootPath + `":
+    - errorType: ErrorTypeSentinel
+      exportType: ExportTypeExported
+      packageName: "uboat"
+      name: "ErrSentinel"
+      backingTypeName: "error"
+      position: "` + ubootFile(t) + `:5:5"
+      message: "days of no horizon, claustrophobia, condition red"
+      initializer: "errors.New"
+      call: "errors.New(\"days of no horizon, claustrophobia, condition red\")"
+      retryable: unknown
+    - errorType: ErrorTypeStructured
+      exportType: ExportTypeExported
+      packageName: "uboat"
+      name: "StructuredError"
+      backingTypeName: "` + ubootPath + `.StructuredError"
+      position: "` + ubootFile(t) + `:9:6"
+      message: "don't crash"
+      receiverKind: value
+      retryable: unknown
+`
+	if got := buf.String(); got != want {
+		t.Errorf("runScan(...) wrote:\n%v\nwant:\n%v", got, want)
+	}
+}
+
+func TestRunTemplate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "defs.tmpl")
+	const tmpl = `{{range .}}{{.ErrorType}} {{.ExportType}} {{.PackageName}}.{{.Name}}
+{{end}}`
+	if err := os.WriteFile(path, []byte(tmpl), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := runScan(context.Background(), options{Format: "template", Template: path}, []string{"../../testdata/uboot"}, &buf); err != nil {
+		t.Fatalf("runScan(...) = %v, want nil", err)
+	}
+	want := `ErrorTypeSentinel ExportTypeExported uboat.ErrSentinel
+ErrorTypeStructured ExportTypeExported uboat.StructuredError
+`
+	if got := buf.String(); got != want {
+		t.Errorf("runScan(...) wrote:\n%v\nwant:\n%v", got, want)
+	}
+}
+
+func TestRunTemplateMissing(t *testing.T) {
+	if err := runScan(context.Background(), options{Format: "template"}, []string{"../../testdata/uboot"}, new(bytes.Buffer)); err == nil {
+		t.Error("runScan(...) = nil, want error")
+	}
+}
+
+func TestRunSARIF(t *testing.T) {
+	// Locations are relative to the working directory, so run from the root
+	// of the module, which contains the test packages.
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(filepath.Join("..", "..")); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+	var buf bytes.Buffer
+	if err := runScan(context.Background(), options{Format: "sarif"}, []string{"./testdata/uboot"}, &buf); err != nil {
+		t.Fatalf("runScan(...) = %v, want nil", err)
+	}
+	var log sarifLog
+	if err := json.Unmarshal(buf.Bytes(), &log); err != nil {
+		t.Fatalf("json.Unmarshal(...) = %v, want nil", err)
+	}
+	if len(log.Runs) != 1 {
+		t.Fatalf("runScan(...) emitted %d SARIF runs, want 1", len(log.Runs))
+	}
+	type result struct {
+		RuleID string
+		URI    string
+		Line   int
+	}
+	var got []result
+	for _, r := range log.Runs[0].Results {
+		loc := r.Locations[0].PhysicalLocation
+		got = append(got, result{r.RuleID, loc.ArtifactLocation.URI, loc.Region.StartLine})
+	}
+	want := []result{
+		{"sentinel", "testdata/uboot/uboot.go", 5},
+		{"structured", "testdata/uboot/uboot.go", 9},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("runScan(...) emitted results %v, want %v", got, want)
+	}
+}
+
+func TestRunDOT(t *testing.T) {
+	var buf bytes.Buffer
+	if err := runScan(context.Background(), options{Format: "dot"}, []string{"../../testdata/taxonomy"}, &buf); err != nil {
+		t.Fatalf("runScan(...) = %v, want nil", err)
+	}
+	for _, want := range []string{
+		"label=\"" + taxonomyPath + "\";",
+		"\"" + taxonomyPath + ".ErrSyntax\" [label=\"ErrSyntax\", shape=ellipse, tooltip=",
+		"\"" + taxonomyPath + ".ConfigError\" [label=\"ConfigError\", shape=box, tooltip=",
+		"\"" + taxonomyPath + ".DefaultConfigError\" -> \"" + taxonomyPath + ".ConfigError\" [label=\"instance of\", style=dashed];",
+		"\"" + taxonomyPath + ".ConfigError\" -> \"" + taxonomyPath + ".ParseError\" [label=\"embeds\"];",
+		"\"" + taxonomyPath + ".ConfigError\" -> \"" + taxonomyPath + ".ParseError\" [label=\"unwraps\"];",
+		"\"" + taxonomyPath + ".ParseError\" -> \"error\" [label=\"unwraps\"];",
+		"\"" + taxonomyPath + ".syntaxError\" -> \"" + taxonomyPath + ".ErrSyntax\" [label=\"unwraps\"];",
+		"\"" + taxonomyPath + ".ErrUnsupported\" -> \"errors.ErrUnsupported\" [label=\"wraps\"];",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("runScan(...) output does not contain %v", want)
+		}
+	}
+}
+
+func TestRunHierarchy(t *testing.T) {
+	var buf bytes.Buffer
+	if err := runScan(context.Background(), options{Format: "hierarchy", Header: true}, []string{"../../testdata/taxonomy"}, &buf); err != nil {
+		t.Fatalf("runScan(...) = %v, want nil", err)
+	}
+	want := `child,relation,parent
+` + taxonomyPath + `.ParseError,unwraps,error
+` + taxonomyPath + `.ConfigError,embeds,` + taxonomyPath + `.ParseError
+` + taxonomyPath + `.ConfigError,unwraps,` + taxonomyPath + `.ParseError
+` + taxonomyPath + `.syntaxError,unwraps,` + taxonomyPath + `.ErrSyntax
+` + taxonomyPath + `.ErrUnsupported,wraps,errors.ErrUnsupported
+`
+	if got := buf.String(); got != want {
+		t.Errorf("runScan(...) wrote:\n%v\nwant:\n%v", got, want)
+	}
+}
+
+func TestRunUses(t *testing.T) {
+	var buf bytes.Buffer
+	opts := options{Format: "uses", Header: true}
+	opts.Uses = []errorfinder.UseKind{errorfinder.UseReturns}
+	if err := runScan(context.Background(), opts, []string{"../../testdata/uses"}, &buf); err != nil {
+		t.Fatalf("runScan(...) = %v, want nil", err)
+	}
+	file, err := filepath.Abs(filepath.Join("..", "..", "testdata", "uses", "uses.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	const pkg = "github.com/matttproud/errorfinder/testdata/uses"
+	want := strings.Join([]string{
+		"error,kind,func,position,problem",
+		pkg + ".ErrNotFound,returns," + pkg + ".Find," + file + ":19:14,",
+		pkg + ".ErrNotFound,returns,(*" + pkg + ".Store).Get," + file + ":34:33,",
+		pkg + ".QueryError,returns," + pkg + ".Query," + file + ":26:10,",
+		"",
+	}, "\n")
+	if got := buf.String(); got != want {
+		t.Errorf("runScan(...) wrote:\n%v\nwant:\n%v", got, want)
+	}
+}
+
+func TestRunEntryPoints(t *testing.T) {
+	var buf bytes.Buffer
+	opts := options{Format: "entrypoints", Header: true}
+	opts.Propagation = true
+	if err := runScan(context.Background(), opts, []string{"../../testdata/propagation"}, &buf); err != nil {
+		t.Fatalf("runScan(...) = %v, want nil", err)
+	}
+	const pkg = "github.com/matttproud/errorfinder/testdata/propagation"
+	want := strings.Join([]string{
+		"entrypoint,error",
+		"(*" + pkg + ".Client).Get," + pkg + ".ErrInvalid",
+		"(*" + pkg + ".Client).Get," + pkg + ".ErrNotFound",
+		"(*" + pkg + ".Client).Get," + pkg + ".TimeoutError",
+		pkg + ".Check," + pkg + ".ErrInvalid",
+		pkg + ".Wrap," + pkg + ".ErrNotFound",
+		"",
+	}, "\n")
+	if got := buf.String(); got != want {
+		t.Errorf("runScan(...) wrote:\n%v\nwant:\n%v", got, want)
+	}
+}
+
+func TestRunReturns(t *testing.T) {
+	var buf bytes.Buffer
+	opts := options{Format: "returns", Header: true}
+	opts.Returns = true
+	if err := runScan(context.Background(), opts, []string{"../../testdata/uses"}, &buf); err != nil {
+		t.Fatalf("runScan(...) = %v, want nil", err)
+	}
+	const pkg = "github.com/matttproud/errorfinder/testdata/uses"
+	want := strings.Join([]string{
+		"function,error",
+		pkg + ".Find," + pkg + ".ErrNotFound",
+		pkg + ".Query," + pkg + ".ErrNotFound",
+		pkg + ".Query," + pkg + ".QueryError",
+		"",
+	}, "\n")
+	if got := buf.String(); got != want {
+		t.Errorf("runScan(...) wrote:\n%v\nwant:\n%v", got, want)
+	}
+}
+
+func TestRunUndocumented(t *testing.T) {
+	var buf bytes.Buffer
+	opts := options{Format: "undocumented", Header: true}
+	opts.DocCoverage = true
+	if err := runScan(context.Background(), opts, []string{"../../testdata/doccoverage"}, &buf); err != nil {
+		t.Fatalf("runScan(...) = %v, want nil", err)
+	}
+	const pkg = "github.com/matttproud/errorfinder/testdata/doccoverage"
+	want := strings.Join([]string{
+		"function,error",
+		pkg + ".Delete," + pkg + ".ErrNotFound",
+		pkg + ".Get," + pkg + ".ErrInvalid",
+		pkg + ".Put," + pkg + ".ErrInvalid",
+		"",
+	}, "\n")
+	if got := buf.String(); got != want {
+		t.Errorf("runScan(...) wrote:\n%v\nwant:\n%v", got, want)
+	}
+}
+
+func TestRunInterfaces(t *testing.T) {
+	var buf bytes.Buffer
+	opts := options{Format: "interfaces", Header: true}
+	opts.InterfaceMethods = true
+	if err := runScan(context.Background(), opts, []string{"../../testdata/ifaces"}, &buf); err != nil {
+		t.Fatalf("runScan(...) = %v, want nil", err)
+	}
+	const pkg = "github.com/matttproud/errorfinder/testdata/ifaces"
+	want := strings.Join([]string{
+		"method,error",
+		"(" + pkg + ".Store).Get," + pkg + ".ErrNotFound",
+		"(" + pkg + ".Store).Get," + pkg + ".TimeoutError",
+		"(" + pkg + ".Store).Put," + pkg + ".ErrReadOnly",
+		"",
+	}, "\n")
+	if got := buf.String(); got != want {
+		t.Errorf("runScan(...) wrote:\n%v\nwant:\n%v", got, want)
+	}
+}
+
+func TestRunHandlers(t *testing.T) {
+	var buf bytes.Buffer
+	opts := options{Format: "handlers", Header: true}
+	opts.Handlers = true
+	if err := runScan(context.Background(), opts, []string{"../../testdata/handlers"}, &buf); err != nil {
+		t.Fatalf("runScan(...) = %v, want nil", err)
+	}
+	const pkg = "github.com/matttproud/errorfinder/testdata/handlers"
+	want := strings.Join([]string{
+		"handler,error",
+		"(" + pkg + ".Uploader).ServeHTTP," + pkg + ".ErrInternal",
+		pkg + ".GetItem," + pkg + ".ErrForbidden",
+		pkg + ".GetItem," + pkg + ".ErrNotFound",
+		pkg + ".Quota," + pkg + ".QuotaError",
+		"",
+	}, "\n")
+	if got := buf.String(); got != want {
+		t.Errorf("runScan(...) wrote:\n%v\nwant:\n%v", got, want)
+	}
+}
+
+func TestRunXLSX(t *testing.T) {
+	var buf bytes.Buffer
+	if err := runScan(context.Background(), options{Format: "xlsx", Columns: "name,kind"}, []string{"../../testdata/uboot"}, &buf); err != nil {
+		t.Fatalf("runScan(...) = %v, want nil", err)
+	}
+	z, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("zip.NewReader(...) = %v, want nil", err)
+	}
+	var cells []string
+	for _, f := range z.File {
+		r, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		dec := xml.NewDecoder(r)
+		inText := false
+		for {
+			tok, err := dec.Token()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatalf("decoding %v: %v", f.Name, err)
+			}
+			switch tok := tok.(type) {
+			case xml.StartElement:
+				inText = tok.Name.Local == "t"
+			case xml.EndElement:
+				inText = false
+			case xml.CharData:
+				if inText && f.Name == "xl/worksheets/sheet1.xml" {
+					cells = append(cells, string(tok))
+				}
+			}
+		}
+		r.Close()
+	}
+	want := []string{"name", "kind", "ErrSentinel", "ErrorTypeSentinel", "StructuredError", "ErrorTypeStructured"}
+	if !slices.Equal(cells, want) {
+		t.Errorf("runScan(...) wrote cells %q, want %q", cells, want)
+	}
+}
+
+func TestXLSXColumn(t *testing.T) {
+	for i, want := range map[int]string{0: "A", 25: "Z", 26: "AA", 27: "AB", 701: "ZZ", 702: "AAA"} {
+		if got := xlsxColumn(i); got != want {
+			t.Errorf("xlsxColumn(%d) = %v, want %v", i, got, want)
+		}
+	}
+}
+
+func TestRunParquet(t *testing.T) {
+	var buf bytes.Buffer
+	if err := runScan(context.Background(), options{Format: "parquet", Columns: "name"}, []string{"../../testdata/uboot"}, &buf); err != nil {
+		t.Fatalf("runScan(...) = %v, want nil", err)
+	}
+	b := buf.Bytes()
+	if !bytes.HasPrefix(b, []byte(parquetMagic)) || !bytes.HasSuffix(b, []byte(parquetMagic)) {
+		t.Fatalf("runScan(...) output is not framed by %q", parquetMagic)
+	}
+	metaLen := int(binary.LittleEndian.Uint32(b[len(b)-8:]))
+	if metaLen <= 0 || metaLen > len(b)-12 {
+		t.Fatalf("footer metadata length = %d, want in (0, %d]", metaLen, len(b)-12)
+	}
+	// The lone column chunk holds the PLAIN-encoded names back to back.
+	var page bytes.Buffer
+	for _, name := range []string{"ErrSentinel", "StructuredError"} {
+		page.Write(binary.LittleEndian.AppendUint32(nil, uint32(len(name))))
+		page.WriteString(name)
+	}
+	if !bytes.Contains(b, page.Bytes()) {
+		t.Errorf("runScan(...) output does not contain data page %q", page.Bytes())
+	}
+	if meta := b[len(b)-8-metaLen : len(b)-8]; !bytes.Contains(meta, []byte("name")) {
+		t.Errorf("footer metadata %q does not name column", meta)
+	}
+}
+
+func TestRunCompressGzip(t *testing.T) {
+	for _, format := range []string{"csv", "jsonl"} {
+		var plain, compressed bytes.Buffer
+		if err := runScan(context.Background(), options{Format: format}, []string{"../../testdata/uboot"}, &plain); err != nil {
+			t.Fatalf("runScan(%v, ...) = %v, want nil", format, err)
+		}
+		if err := runScan(context.Background(), options{Format: format, Compress: "gzip"}, []string{"../../testdata/uboot"}, &compressed); err != nil {
+			t.Fatalf("runScan(%v, gzip, ...) = %v, want nil", format, err)
+		}
+		r, err := gzip.NewReader(&compressed)
+		if err != nil {
+			t.Fatalf("gzip.NewReader(...) = %v, want nil", err)
+		}
+		got, err := io.ReadAll(r)
+		if err != nil {
+			t.Fatalf("io.ReadAll(...) = %v, want nil", err)
+		}
+		if !bytes.Equal(got, plain.Bytes()) {
+			t.Errorf("decompressed %v output = %q, want %q", format, got, plain.Bytes())
+		}
+	}
+}
+
+func TestRunUnknownCompression(t *testing.T) {
+	if err := runScan(context.Background(), options{Format: "csv", Compress: "zstd"}, []string{"../../testdata/uboot"}, new(bytes.Buffer)); err == nil {
+		t.Error("runScan(...) = nil, want error")
+	}
+}
+
+func TestRunOutput(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.csv")
+	var stdout bytes.Buffer
+	if err := runScan(context.Background(), options{Format: "csv", Columns: "name", Output: path}, []string{"../../testdata/uboot"}, &stdout); err != nil {
+		t.Fatalf("runScan(...) = %v, want nil", err)
+	}
+	if stdout.Len() != 0 {
+		t.Errorf("runScan(...) wrote %q to stdout, want nothing", stdout.String())
+	}
+	if got, err := os.ReadFile(path); err != nil || string(got) != "ErrSentinel\nStructuredError\n" {
+		t.Errorf("os.ReadFile(%v) = %q, %v; want defs, nil", path, got, err)
+	}
+}
+
+func TestRunSplitByPackage(t *testing.T) {
+	dir := t.TempDir()
+	opts := options{Format: "csv", Columns: "name", Output: dir, SplitByPackage: true}
+	if err := runScan(context.Background(), opts, []string{"../../testdata/uboot", "../../testdata/taxonomy"}, new(bytes.Buffer)); err != nil {
+		t.Fatalf("runScan(...) = %v, want nil", err)
+	}
+	for path, want := range map[string]string{
+		ubootPath:    "ErrSentinel\nStructuredError\n",
+		taxonomyPath: "DefaultConfigError\nErrSyntax\nErrUnsupported\nConfigError\nParseError\nsyntaxError\n",
+	} {
+		file := filepath.Join(dir, filepath.FromSlash(path), "errors.csv")
+		if got, err := os.ReadFile(file); err != nil || string(got) != want {
+			t.Errorf("os.ReadFile(%v) = %q, %v; want %q, nil", file, got, err, want)
+		}
+	}
+}
+
+func TestRunSplitByPackageRequiresOutput(t *testing.T) {
+	if err := runScan(context.Background(), options{Format: "csv", SplitByPackage: true}, []string{"../../testdata/uboot"}, new(bytes.Buffer)); err == nil {
+		t.Error("runScan(...) = nil, want error")
+	}
+}
+
+func TestPackageDir(t *testing.T) {
+	for in, want := range map[string]string{
+		"example.com/a/b":        filepath.Join("example.com", "a", "b"),
+		"p [p.test]":             "p__p.test_",
+		"../escape":              filepath.Join("_", "escape"),
+		"command-line-arguments": "command-line-arguments",
+	} {
+		if got := packageDir(in); got != want {
+			t.Errorf("packageDir(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestWriteFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "out.csv")
+	if err := writeFile(path, func(w io.Writer) error {
+		_, err := io.WriteString(w, "contents")
+		return err
+	}); err != nil {
+		t.Fatalf("writeFile(...) = %v, want nil", err)
+	}
+	if got, err := os.ReadFile(path); err != nil || string(got) != "contents" {
+		t.Errorf("os.ReadFile(%v) = %q, %v; want %q, nil", path, got, err, "contents")
+	}
+
+	errWrite := errors.New("write failed")
+	if err := writeFile(path, func(w io.Writer) error {
+		io.WriteString(w, "partial")
+		return errWrite
+	}); err != errWrite {
+		t.Fatalf("writeFile(...) = %v, want %v", err, errWrite)
+	}
+	if got, err := os.ReadFile(path); err != nil || string(got) != "contents" {
+		t.Errorf("after failed write, os.ReadFile(%v) = %q, %v; want %q, nil", path, got, err, "contents")
+	}
+	if entries, err := os.ReadDir(dir); err != nil || len(entries) != 1 {
+		t.Errorf("os.ReadDir(%v) = %v, %v; want only the output file", dir, entries, err)
+	}
+}
+
+func TestRunTable(t *testing.T) {
+	for _, test := range []struct {
+		color string
+		want  string
+	}{
+		{
+			color: "never",
+			want: `NAME             KIND
+ErrSentinel      ErrorTypeSentinel
+StructuredError  ErrorTypeStructured
+`,
+		},
+		{
+			color: "always",
+			want: "\x1b[1mNAME\x1b[0m             \x1b[1mKIND\x1b[0m\n" +
+				"\x1b[32m\x1b[1mErrSentinel\x1b[0m      \x1b[32mErrorTypeSentinel\x1b[0m\n" +
+				"\x1b[36m\x1b[1mStructuredError\x1b[0m  \x1b[36mErrorTypeStructured\x1b[0m\n",
+		},
+	} {
+		var buf bytes.Buffer
+		opts := options{Format: "table", Columns: "name,kind", Color: test.color}
+		if err := runScan(context.Background(), opts, []string{"../../testdata/uboot"}, &buf); err != nil {
+			t.Fatalf("runScan(%+v, ...) = %v, want nil", opts, err)
+		}
+		if got := buf.String(); got != test.want {
+			t.Errorf("runScan(%+v, ...) wrote:\n%q\nwant:\n%q", opts, got, test.want)
+		}
+	}
+}
+
+func TestRunCache(t *testing.T) {
+	var buf bytes.Buffer
+	if err := runScan(context.Background(), options{Format: "cache"}, []string{"../../testdata/taxonomy"}, &buf); err != nil {
+		t.Fatalf("runScan(...) = %v, want nil", err)
+	}
+	defs, err := readCache(&buf)
+	if err != nil {
+		t.Fatalf("readCache(...) = %v, want nil", err)
+	}
+	if len(defs) != 6 {
+		t.Fatalf("readCache(...) returned %d defs, want 6", len(defs))
+	}
+	for _, d := range defs {
+		if d.Position.Filename == "" || d.Position.Line == 0 {
+			t.Errorf("def %v lost its position: %v", d.Name, d.Position)
+		}
+		if d.Name == "ConfigError" && (!slices.Equal(d.Embeds, []string{taxonomyPath + ".ParseError"}) || !slices.Equal(d.Wraps, []string{taxonomyPath + ".ParseError"})) {
+			t.Errorf("def %v lost its relationships: embeds %v, wraps %v", d.Name, d.Embeds, d.Wraps)
+		}
+	}
+}
+
+func TestCacheRoundTrip(t *testing.T) {
+	d := errorfinder.Def{
+		ErrorType:       errorfinder.ErrorTypeStructured,
+		ExportType:      errorfinder.ExportTypeExported,
+		ImportPath:      "example.com/p",
+		PackageName:     "p",
+		Name:            "E",
+		BackingTypeName: "example.com/p.E",
+		Position:        token.Position{Filename: "/src/p/p.go", Offset: 10, Line: 2, Column: 6},
+		Embeds:          []string{"error"},
+		Wraps:           []string{"example.com/p.ErrX"},
+	}
+	var buf bytes.Buffer
+	enc, err := newCacheEncoder(&buf, options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := enc.Encode(d); err != nil {
+		t.Fatalf("enc.Encode(...) = %v, want nil", err)
+	}
+	if err := enc.Close(); err != nil {
+		t.Fatalf("enc.Close() = %v, want nil", err)
+	}
+	got, err := readCache(&buf)
+	if err != nil {
+		t.Fatalf("readCache(...) = %v, want nil", err)
+	}
+	if want := []errorfinder.Def{d}; !reflect.DeepEqual(got, want) {
+		t.Errorf("readCache(...) = %+v, want %+v", got, want)
+	}
+}
+
+func TestRunConfig(t *testing.T) {
+	opts := options{
+		Config: errorfinder.Config{Kinds: []errorfinder.ErrorType{errorfinder.ErrorTypeSentinel}, ExportedOnly: true},
+		Format: "csv", Columns: "name",
+	}
+	var buf bytes.Buffer
+	if err := runScan(context.Background(), opts, []string{"../../testdata/taxonomy"}, &buf); err != nil {
+		t.Fatalf("runScan(...) = %v, want nil", err)
+	}
+	if got, want := buf.String(), "DefaultConfigError\nErrSyntax\nErrUnsupported\n"; got != want {
+		t.Errorf("runScan(...) wrote:\n%v\nwant:\n%v", got, want)
+	}
+}
+
+func TestRunCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	path := filepath.Join(t.TempDir(), "errors.csv")
+	if err := runScan(ctx, options{Format: "csv", Output: path}, []string{"../../testdata/uboot"}, new(bytes.Buffer)); err == nil || !strings.Contains(err.Error(), context.Canceled.Error()) {
+		t.Errorf("runScan(...) = %v, want %v", err, context.Canceled)
+	}
+	if _, err := os.Stat(path); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("os.Stat(%q) = %v, want %v", path, err, fs.ErrNotExist)
+	}
+}
+
+func TestRunUnknownFormat(t *testing.T) {
+	if err := runScan(context.Background(), options{Format: "bogus"}, []string{"../../testdata/uboot"}, new(bytes.Buffer)); err == nil {
+		t.Error("runScan(...) = nil, want error")
+	}
+}
+
+func TestParseArgs(t *testing.T) {
+	for _, test := range []struct {
+		args     []string
+		cmd      string
+		format   string
+		depth    int
+		exported bool
+		rest     []string
+	}{
+		{args: []string{"./..."}, cmd: "scan", format: "csv", rest: []string{"./..."}},
+		{args: []string{"-format=json", "-depth=1", "./..."}, cmd: "scan", format: "json", depth: 1, rest: []string{"./..."}},
+		{args: []string{"scan", "-format=yaml", "./..."}, cmd: "scan", format: "yaml", rest: []string{"./..."}},
+		{args: []string{"-depth=2", "lint", "-exported", "./a", "./b"}, cmd: "lint", format: "csv", depth: 2, exported: true, rest: []string{"./a", "./b"}},
+		{args: []string{"diff", "old.cache", "new.cache"}, cmd: "diff", format: "csv", rest: []string{"old.cache", "new.cache"}},
+	} {
+		cmd, opts, rest, err := parseArgs(test.args, io.Discard)
+		if err != nil {
+			t.Errorf("parseArgs(%q) = %v, want nil", test.args, err)
+			continue
+		}
+		if cmd.name != test.cmd || opts.Format != test.format || opts.Depth != test.depth || opts.ExportedOnly != test.exported || !slices.Equal(rest, test.rest) {
+			t.Errorf("parseArgs(%q) = %v, %+v, %q; want %v, format %v, depth %v, exported %v, %q", test.args, cmd.name, opts, rest, test.cmd, test.format, test.depth, test.exported, test.rest)
+		}
+	}
+	for _, args := range [][]string{{"-bogus"}, {"lint", "-format=json"}, {"serve", "-addr"}, {"-scope=file"}} {
+		if _, _, _, err := parseArgs(args, io.Discard); err == nil {
+			t.Errorf("parseArgs(%q) = nil, want error", args)
+		}
+	}
+}
+
+func TestDiffDefs(t *testing.T) {
+	a := errorfinder.Def{ErrorType: errorfinder.ErrorTypeSentinel, ImportPath: "example.com/p", Name: "ErrA", BackingTypeName: "error", Message: "a"}
+	b := errorfinder.Def{ErrorType: errorfinder.ErrorTypeSentinel, ImportPath: "example.com/p", Name: "ErrB", BackingTypeName: "error"}
+	c := errorfinder.Def{ErrorType: errorfinder.ErrorTypeStructured, ImportPath: "example.com/p", Name: "CError", BackingTypeName: "example.com/p.CError"}
+	a2 := a
+	a2.Message = "a2"
+	a2.Position.Line = 10
+	b2 := b
+	b2.Position.Line = 20
+	b2.Doc = "ErrB is documented."
+	var got []string
+	for _, c := range diffDefs([]errorfinder.Def{a, b}, []errorfinder.Def{c, a2, b2}) {
+		got = append(got, c.String())
+	}
+	want := []string{
+		`+ example.com/p.CError structured`,
+		`~ example.com/p.ErrA message: "a" -> "a2"`,
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("diffDefs(...) = %q, want %q", got, want)
+	}
+	got = nil
+	for _, c := range diffDefs([]errorfinder.Def{a, c}, []errorfinder.Def{a}) {
+		got = append(got, c.String())
+	}
+	if want := []string{"- example.com/p.CError structured"}; !slices.Equal(got, want) {
+		t.Errorf("diffDefs(...) = %q, want %q", got, want)
+	}
+	local1 := errorfinder.Def{ErrorType: errorfinder.ErrorTypeSentinel, ImportPath: "example.com/p", Name: "errLocal", Func: "F", BackingTypeName: "error"}
+	local2 := local1
+	local2.Func = "(*T).M"
+	if changes := diffDefs([]errorfinder.Def{local1, local2}, []errorfinder.Def{local2, local1}); len(changes) > 0 {
+		t.Errorf("diffDefs(...) of locals of different functions = %v, want none", changes)
+	}
+}
+
+func TestRunDiff(t *testing.T) {
+	dir := t.TempDir()
+	old, new := filepath.Join(dir, "old.cache"), filepath.Join(dir, "new.cache.gz")
+	if err := runScan(context.Background(), options{Format: "cache", Output: old}, []string{"../../testdata/uboot"}, io.Discard); err != nil {
+		t.Fatalf("runScan(...) = %v, want nil", err)
+	}
+	if err := runScan(context.Background(), options{Format: "cache", Compress: "gzip", Output: new}, []string{"../../testdata/uboot", "../../testdata/taxonomy"}, io.Discard); err != nil {
+		t.Fatalf("runScan(...) = %v, want nil", err)
+	}
+	var buf bytes.Buffer
+	if err := runDiff(context.Background(), options{}, []string{old, new}, &buf); err != nil {
+		t.Fatalf("runDiff(...) = %v, want nil", err)
+	}
+	want := []string{
+		"+ " + taxonomyPath + ".ConfigError structured",
+		"+ " + taxonomyPath + ".DefaultConfigError sentinel",
+		"+ " + taxonomyPath + ".ErrSyntax sentinel",
+		"+ " + taxonomyPath + ".ErrUnsupported sentinel",
+		"+ " + taxonomyPath + ".ParseError structured",
+		"+ " + taxonomyPath + ".syntaxError structured",
+	}
+	if got := strings.Split(strings.TrimSpace(buf.String()), "\n"); !slices.Equal(got, want) {
+		t.Errorf("runDiff(...) wrote %q, want %q", got, want)
+	}
+	if err := runDiff(context.Background(), options{}, []string{old}, io.Discard); err == nil {
+		t.Error("runDiff(...) with one file = nil, want error")
+	}
+	buf.Reset()
+	if err := runDiff(context.Background(), options{}, []string{old, "../../testdata/uboot"}, &buf); err != nil {
+		t.Fatalf("runDiff(...) with a directory = %v, want nil", err)
+	}
+	if got := buf.String(); got != "" {
+		t.Errorf("runDiff(...) with a directory wrote %q, want nothing", got)
+	}
+	if err := runDiff(context.Background(), options{}, []string{old, filepath.Join(dir, "missing")}, io.Discard); err == nil {
+		t.Error("runDiff(...) with a missing file = nil, want error")
+	}
+}
+
+func TestCompatIssues(t *testing.T) {
+	sentinel := errorfinder.Def{ErrorType: errorfinder.ErrorTypeSentinel, ExportType: errorfinder.ExportTypeExported, ImportPath: "example.com/p", Name: "ErrA", BackingTypeName: "error", Message: "a"}
+	removed := errorfinder.Def{ErrorType: errorfinder.ErrorTypeSentinel, ExportType: errorfinder.ExportTypeExported, ImportPath: "example.com/p", Name: "ErrB", BackingTypeName: "error"}
+	internal := errorfinder.Def{ErrorType: errorfinder.ErrorTypeSentinel, ExportType: errorfinder.ExportTypeUnexported, ImportPath: "example.com/p", Name: "errC", BackingTypeName: "error"}
+	structured := errorfinder.Def{ErrorType: errorfinder.ErrorTypeStructured, ExportType: errorfinder.ExportTypeExported, ImportPath: "example.com/p", Name: "PathError", BackingTypeName: "example.com/p.PathError", ReceiverKind: "value", Fields: []string{"Op string", "Path string", "Err error"}}
+	sentinel2 := sentinel
+	sentinel2.Message = "a2"
+	structured2 := structured
+	structured2.ReceiverKind = "pointer"
+	structured2.Fields = []string{"Op int", "Err error", "Code int"}
+	var got []string
+	for _, issue := range compatIssues([]errorfinder.Def{sentinel, removed, internal, structured}, []errorfinder.Def{sentinel2, structured2}) {
+		got = append(got, issue.String())
+	}
+	want := []string{
+		`warning: example.com/p.ErrA: changed Error message from "a" to "a2"`,
+		`breaking: example.com/p.ErrB: removed exported sentinel`,
+		`breaking: example.com/p.PathError: changed receiver kind from value to pointer, changing the errors.As targets that match`,
+		`breaking: example.com/p.PathError: changed type of field Op from string to int`,
+		`breaking: example.com/p.PathError: removed exported field Path`,
+		`info: example.com/p.PathError: added exported field Code, breaking unkeyed composite literals`,
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("compatIssues(...) = %q, want %q", got, want)
+	}
+}
+
+func TestRunCompat(t *testing.T) {
+	var buf bytes.Buffer
+	err := runCompat(context.Background(), options{}, []string{"../../testdata/uboot", "../../testdata/uboot"}, &buf)
+	if err != nil || buf.Len() > 0 {
+		t.Errorf("runCompat(...) of the same version = %v and wrote %q, want nil and nothing", err, buf.String())
+	}
+	err = runCompat(context.Background(), options{}, []string{"../../testdata/uboot", "../../testdata/taxonomy"}, &buf)
+	if exitCode(err) != exitViolations || !strings.Contains(err.Error(), "found 2 breaking changes") {
+		t.Errorf("runCompat(...) with removed errors = %v, want 2 breaking changes", err)
+	}
+	if err := runCompat(context.Background(), options{}, []string{"../../testdata/uboot"}, io.Discard); err == nil {
+		t.Error("runCompat(...) with one version = nil, want error")
+	}
+}
+
+// moduleProxy serves the versions of module mod, each a map of file names to
+// contents, from a GOPROXY directory and returns the environment using it and
+// an empty module cache.
+func moduleProxy(t *testing.T, mod string, versions map[string]map[string]string) []string {
+	t.Helper()
+	proxy := t.TempDir()
+	dir := filepath.Join(proxy, filepath.FromSlash(mod), "@v")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	var list strings.Builder
+	for version, files := range versions {
+		fmt.Fprintln(&list, version)
+		if err := os.WriteFile(filepath.Join(dir, version+".info"), []byte(`{"Version":"`+version+`"}`), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, version+".mod"), []byte(files["go.mod"]), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		var buf bytes.Buffer
+		zw := zip.NewWriter(&buf)
+		for name, content := range files {
+			w, err := zw.Create(mod + "@" + version + "/" + name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			io.WriteString(w, content)
+		}
+		if err := zw.Close(); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, version+".zip"), buf.Bytes(), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir, "list"), []byte(list.String()), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return append(os.Environ(), "GOPROXY=file://"+filepath.ToSlash(proxy), "GOSUMDB=off", "GONOSUMDB=*", "GOMODCACHE="+t.TempDir(), "GOFLAGS=-modcacherw")
+}
+
+func TestRunDiffModules(t *testing.T) {
+	env := moduleProxy(t, "example.com/m", map[string]map[string]string{
+		"v1.0.0": {
+			"go.mod": "module example.com/m\n\ngo 1.21\n",
+			"m.go":   "package m\n\nimport \"errors\"\n\nvar ErrOld = errors.New(\"old\")\n",
+		},
+		"v1.1.0": {
+			"go.mod": "module example.com/m\n\ngo 1.21\n",
+			"m.go":   "package m\n\nimport \"errors\"\n\nvar ErrNew = errors.New(\"new\")\n",
+		},
+		"v1.2.0": {
+			"go.mod": "module example.com/m\n\ngo 1.21\n\nrequire example.com/dep v1.0.0\n",
+			"m.go":   "package m\n\nimport \"example.com/dep\"\n\nvar ErrDep = dep.Err\n",
+		},
+	})
+	opts := options{Config: errorfinder.Config{Env: env}}
+	var buf bytes.Buffer
+	if err := runDiff(context.Background(), opts, []string{"example.com/m@v1.0.0", "example.com/m@v1.1.0"}, &buf); err != nil {
+		t.Fatalf("runDiff(...) = %v, want nil", err)
+	}
+	want := []string{"+ example.com/m.ErrNew sentinel", "- example.com/m.ErrOld sentinel"}
+	if got := strings.Split(strings.TrimSpace(buf.String()), "\n"); !slices.Equal(got, want) {
+		t.Errorf("runDiff(...) wrote %q, want %q", got, want)
+	}
+	err := runDiff(context.Background(), opts, []string{"example.com/m@v1.0.0", "example.com/m@v1.2.0"}, io.Discard)
+	if err == nil || !strings.Contains(err.Error(), "scanning example.com/m@v1.2.0") {
+		t.Errorf("runDiff(...) with an unloadable module = %v, want an error scanning it", err)
+	}
+	if err := runDiff(context.Background(), opts, []string{"example.com/m@v1.0.0", "example.com/m@v9.0.0"}, io.Discard); err == nil || !strings.Contains(err.Error(), "downloading example.com/m@v9.0.0") {
+		t.Errorf("runDiff(...) with a missing version = %v, want an error downloading it", err)
+	}
+}
+
+func TestRunScanBaseline(t *testing.T) {
+	baseline := filepath.Join(t.TempDir(), "errors.baseline.json")
+	if err := runScan(context.Background(), options{Format: "json", Output: baseline}, []string{"../../testdata/uboot"}, io.Discard); err != nil {
+		t.Fatalf("runScan(...) = %v, want nil", err)
+	}
+	var stderr bytes.Buffer
+	opts := options{Format: "csv", Baseline: baseline, FailOn: []string{"new-exported"}, Stderr: &stderr}
+	err := runScan(context.Background(), opts, []string{"../../testdata/uboot", "../../testdata/taxonomy"}, io.Discard)
+	if exitCode(err) != exitViolations || !strings.Contains(err.Error(), "found 5 changes") {
+		t.Errorf("runScan(...) with new exported errors = %v, want 5 violations", err)
+	}
+	want := "errorfinder: new exported error " + taxonomyPath + ".ConfigError is not in the baseline\n"
+	if got := stderr.String(); !strings.HasPrefix(got, want) {
+		t.Errorf("runScan(...) wrote %q to stderr, want prefix %q", got, want)
+	}
+	opts = options{Format: "csv", Baseline: baseline, FailOn: []string{"removed-exported"}}
+	if err := runScan(context.Background(), opts, []string{"../../testdata/uboot", "../../testdata/taxonomy"}, io.Discard); err != nil {
+		t.Errorf("runScan(...) with only new exported errors = %v, want nil", err)
+	}
+	opts = options{Format: "csv", Baseline: baseline}
+	err = runScan(context.Background(), opts, []string{"../../testdata/taxonomy"}, io.Discard)
+	if exitCode(err) != exitViolations || !strings.Contains(err.Error(), "found 7 changes") {
+		t.Errorf("runScan(...) with removed and new exported errors = %v, want 7 violations", err)
+	}
+	opts = options{Format: "csv", FailOn: []string{"new-exported"}}
+	if err := runScan(context.Background(), opts, []string{"../../testdata/uboot"}, io.Discard); err == nil {
+		t.Error("runScan(...) with -fail-on but no -baseline = nil, want error")
+	}
+	if _, err := parseFailOn("new-exported,changed"); err == nil {
+		t.Error("parseFailOn(...) with an unknown condition = nil, want error")
+	}
+}
+
+func TestRunLint(t *testing.T) {
+	var buf bytes.Buffer
+	err := runLint(context.Background(), options{}, []string{"../../testdata/taxonomy"}, &buf)
+	if err == nil || !strings.Contains(err.Error(), "3 lint violations") {
+		t.Errorf("runLint(...) = %v, want 3 violations", err)
+	}
+	var got []string
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		_, finding, _ := strings.Cut(line, ": ")
+		got = append(got, finding)
+	}
+	want := []string{
+		"sentinel-name: sentinel DefaultConfigError should be named Err...",
+		"doc: exported DefaultConfigError should have a doc comment",
+		"doc: exported ConfigError should have a doc comment",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("runLint(...) wrote %q, want %q", got, want)
+	}
+	opts := options{Config: errorfinder.Config{Kinds: []errorfinder.ErrorType{errorfinder.ErrorTypeStructured}}}
+	if err := runLint(context.Background(), opts, []string{"../../testdata/taxonomy"}, io.Discard); err == nil {
+		t.Error("runLint(...) = nil, want error")
+	}
+}
+
+func TestRunStdlib(t *testing.T) {
+	var buf bytes.Buffer
+	if err := runStdlib(context.Background(), options{Header: true}, []string{"../../testdata/stdlib"}, &buf); err != nil {
+		t.Fatalf("runStdlib(...) = %v, want nil", err)
+	}
+	file, err := filepath.Abs(filepath.Join("..", "..", "testdata", "stdlib", "stdlib.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	const pkg = "github.com/matttproud/errorfinder/testdata/stdlib"
+	want := strings.Join([]string{
+		"error,kind,func,position",
+		"io.EOF,compares," + pkg + ".Read," + file + ":14:35",
+		"io.ErrUnexpectedEOF,returns," + pkg + ".Read," + file + ":17:9",
+		"io/fs.ErrNotExist,is," + pkg + ".Exists," + file + ":20:54",
+		"net.Error,as," + pkg + ".Timeout," + file + ":24:24",
+		"os.PathError,returns," + pkg + ".Open," + file + ":31:9",
+		"",
+	}, "\n")
+	if got := buf.String(); got != want {
+		t.Errorf("runStdlib(...) wrote:\n%v\nwant:\n%v", got, want)
+	}
+}
+
+func TestRunGen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "errors.md")
+	if err := runGen(context.Background(), options{Output: path}, []string{"../../testdata/taxonomy"}, io.Discard); err != nil {
+		t.Fatalf("runGen(...) = %v, want nil", err)
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"# Errors\n\n3 sentinels, 3 structured error types, and 0 constructors in 1 packages.\n",
+		"\n## " + taxonomyPath + "\n",
+		"| `ErrSyntax` | sentinel | `error` | syntax error | ErrSyntax indicates malformed input. |\n",
+		"| ~~`ErrUnsupported`~~ | sentinel | `error` | taxonomy: unsupported %w | Deprecated: Use [errors.ErrUnsupported] instead. |\n",
+	} {
+		if !strings.Contains(string(b), want) {
+			t.Errorf("runGen(...) wrote:\n%s\nwant it to contain:\n%s", b, want)
+		}
+	}
+}
+
+func TestMarkdownCell(t *testing.T) {
+	if got, want := markdownCell("a | b\nc"), `a \| b c`; got != want {
+		t.Errorf("markdownCell(...) = %q, want %q", got, want)
+	}
+}
+
+func TestServeHandler(t *testing.T) {
+	srv := httptest.NewServer(serveHandler(options{}, []string{"../../testdata/uboot"}))
+	defer srv.Close()
+	for _, test := range []struct {
+		query  string
+		status int
+		ctype  string
+		body   string
+	}{
+		{query: "", status: http.StatusOK, ctype: "text/html", body: "StructuredError"},
+		{query: "?format=json", status: http.StatusOK, ctype: "application/json", body: `"name": "ErrSentinel"`},
+		{query: "?format=bogus", status: http.StatusBadRequest, body: "unknown format"},
+		{query: "?format=template", status: http.StatusBadRequest, body: "unknown format"},
+	} {
+		resp, err := http.Get(srv.URL + test.query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.StatusCode != test.status {
+			t.Errorf("GET %q: status %v, want %v", test.query, resp.StatusCode, test.status)
+		}
+		if ctype := resp.Header.Get("Content-Type"); test.ctype != "" && !strings.HasPrefix(ctype, test.ctype) {
+			t.Errorf("GET %q: Content-Type %q, want %q", test.query, ctype, test.ctype)
+		}
+		if !strings.Contains(string(body), test.body) {
+			t.Errorf("GET %q returned:\n%s\nwant it to contain %q", test.query, body, test.body)
+		}
+	}
+}
+
+func TestExpandTargets(t *testing.T) {
+	list := filepath.Join(t.TempDir(), "targets.txt")
+	if err := os.WriteFile(list, []byte("./a\n\n# comment\n  ./b  \n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	got, err := expandTargets([]string{"./x", "@" + list, "./y"}, "-", strings.NewReader("./c\n./d\n"))
+	if err != nil {
+		t.Fatalf("expandTargets(...) = %v, want nil", err)
+	}
+	if want := []string{"./x", "./a", "./b", "./y", "./c", "./d"}; !slices.Equal(got, want) {
+		t.Errorf("expandTargets(...) = %q, want %q", got, want)
+	}
+	if _, err := expandTargets([]string{"@" + list + ".missing"}, "", nil); err == nil {
+		t.Error("expandTargets(...) with a missing file = nil, want error")
+	}
+}
+
+func TestExitCode(t *testing.T) {
+	loadErr := &errorfinder.LoadError{Patterns: []string{"./..."}, Err: errorfinder.ErrNoPackages}
+	for _, test := range []struct {
+		err  error
+		want int
+	}{
+		{nil, exitOK},
+		{errors.New("boom"), exitError},
+		{loadErr, exitLoad},
+		{fmt.Errorf("scanning: %w", loadErr), exitLoad},
+		{&violationsError{N: 1, What: "lint violations"}, exitViolations},
+	} {
+		if got := exitCode(test.err); got != test.want {
+			t.Errorf("exitCode(%v) = %v, want %v", test.err, got, test.want)
+		}
+	}
+	err := runLint(context.Background(), options{}, []string{"../../testdata/taxonomy"}, io.Discard)
+	if got := exitCode(err); got != exitViolations {
+		t.Errorf("exitCode(runLint(...)) = %v, want %v", got, exitViolations)
+	}
+	err = runScan(context.Background(), options{Format: "csv"}, []string{"./testdata/..."}, io.Discard)
+	if got := exitCode(err); got != exitLoad {
+		t.Errorf("exitCode(runScan(...)) = %v (%v), want %v", got, err, exitLoad)
+	}
+}
+
+func TestNewLogger(t *testing.T) {
+	if logger := newLogger(options{}, io.Discard); logger != nil {
+		t.Errorf("newLogger(options{}, ...) = %v, want nil", logger)
+	}
+	for _, test := range []struct {
+		opts options
+		want []string
+		omit []string
+	}{
+		{opts: options{Verbose: true}, want: []string{`msg="loaded packages"`, "packages=1"}, omit: []string{"scanned package"}},
+		{opts: options{Debug: true}, want: []string{`msg="loaded packages"`, `msg="scanned package"`, "package=" + ubootPath, "defs=2"}},
+	} {
+		var buf bytes.Buffer
+		opts := test.opts
+		opts.Format = "csv"
+		opts.Logger = newLogger(opts, &buf)
+		if err := runScan(context.Background(), opts, []string{"../../testdata/uboot"}, io.Discard); err != nil {
+			t.Fatalf("runScan(...) = %v, want nil", err)
+		}
+		for _, want := range test.want {
+			if !strings.Contains(buf.String(), want) {
+				t.Errorf("runScan(%+v, ...) logged:\n%v\nwant it to contain %q", test.opts, buf.String(), want)
+			}
+		}
+		for _, omit := range test.omit {
+			if strings.Contains(buf.String(), omit) {
+				t.Errorf("runScan(%+v, ...) logged:\n%v\nwant it to omit %q", test.opts, buf.String(), omit)
+			}
+		}
+	}
+}
+
+func TestProgressStatus(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	p := &progress{start: start}
+	if got, want := p.status(start.Add(3*time.Second)), "loading packages (3s)"; got != want {
+		t.Errorf("p.status(...) = %q, want %q", got, want)
+	}
+	p.scanning, p.scanStart, p.total, p.scanned = true, start.Add(10*time.Second), 4, 1
+	if got, want := p.status(start.Add(12*time.Second)), "scanning packages 1/4 (ETA 6s)"; got != want {
+		t.Errorf("p.status(...) = %q, want %q", got, want)
+	}
+	p.scanned = 4
+	if got, want := p.status(start.Add(20*time.Second)), "scanning packages 4/4"; got != want {
+		t.Errorf("p.status(...) = %q, want %q", got, want)
+	}
+}
+
+func TestRunProgress(t *testing.T) {
+	var buf bytes.Buffer
+	if err := runScan(context.Background(), options{Format: "csv", Progress: &buf}, []string{"../../testdata/uboot"}, io.Discard); err != nil {
+		t.Fatalf("runScan(...) = %v, want nil", err)
+	}
+	if got := buf.String(); !strings.HasSuffix(got, "\r\x1b[K") {
+		t.Errorf("runScan(...) reported progress %q, want it to end by clearing the line", got)
+	}
+}
+
+func TestRunPackageErrors(t *testing.T) {
+	args := []string{"../../testdata/uboot", "../../testdata/broken"}
+	var stdout, stderr bytes.Buffer
+	if err := runScan(context.Background(), options{Format: "json", Stderr: &stderr}, args, &stdout); err != nil {
+		t.Fatalf("runScan(...) = %v, want nil", err)
+	}
+	if want := `broken.go:7:22: cannot use "not an int"`; !strings.Contains(stderr.String(), want) {
+		t.Errorf("runScan(...) reported:\n%v\nwant it to contain %q", stderr.String(), want)
+	}
+	var doc struct {
+		PackageErrors []packageError `json:"packageErrors"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+	if len(doc.PackageErrors) != 1 {
+		t.Fatalf("runScan(...) emitted package errors %+v, want one", doc.PackageErrors)
+	}
+	if pe := doc.PackageErrors[0]; pe.Package != "github.com/matttproud/errorfinder/testdata/broken" || pe.Kind != "type" || !strings.HasSuffix(pe.Position, "broken.go:7:22") {
+		t.Errorf("runScan(...) emitted package error %+v", pe)
+	}
+
+	stdout.Reset()
+	if err := runScan(context.Background(), options{Format: "json", Schema: 1}, args, &stdout); err != nil {
+		t.Fatalf("runScan(...) = %v, want nil", err)
+	}
+	if strings.Contains(stdout.String(), "packageErrors") {
+		t.Errorf("runScan(...) with schema v1 emitted packageErrors:\n%v", stdout.String())
+	}
+
+	stdout.Reset()
+	if err := runScan(context.Background(), options{Format: "sarif"}, args, &stdout); err != nil {
+		t.Fatalf("runScan(...) = %v, want nil", err)
+	}
+	var log sarifLog
+	if err := json.Unmarshal(stdout.Bytes(), &log); err != nil {
+		t.Fatal(err)
+	}
+	if invs := log.Runs[0].Invocations; len(invs) != 1 || len(invs[0].ToolExecutionNotifications) != 1 || invs[0].ToolExecutionNotifications[0].Locations[0].PhysicalLocation.Region.StartLine != 7 {
+		t.Errorf("runScan(...) emitted SARIF invocations %+v, want one notification at line 7", invs)
+	}
+
+	stdout.Reset()
+	err := runScan(context.Background(), options{Format: "json", Strict: true}, args, &stdout)
+	if got := exitCode(err); got != exitLoad {
+		t.Errorf("exitCode(runScan(...) with -strict) = %v (%v), want %v", got, err, exitLoad)
+	}
+	if stdout.Len() > 0 {
+		t.Errorf("runScan(...) with -strict wrote %q, want nothing", stdout.String())
+	}
+	if err := runLint(context.Background(), options{Strict: true}, args, io.Discard); exitCode(err) != exitLoad {
+		t.Errorf("runLint(...) with -strict = %v, want a load failure", err)
+	}
+}
+
+func TestParsePosition(t *testing.T) {
+	for _, test := range []struct {
+		in   string
+		want token.Position
+		ok   bool
+	}{
+		{"/a/b.go:7:22", token.Position{Filename: "/a/b.go", Line: 7, Column: 22}, true},
+		{"/a/b.go:7", token.Position{Filename: "/a/b.go", Line: 7}, true},
+		{"", token.Position{}, false},
+		{"/a/b.go", token.Position{}, false},
+	} {
+		if got, ok := parsePosition(test.in); got != test.want || ok != test.ok {
+			t.Errorf("parsePosition(%q) = %v, %v; want %v, %v", test.in, got, ok, test.want, test.ok)
+		}
+	}
+}
+
+func TestRunOverlay(t *testing.T) {
+	dir := t.TempDir()
+	replacement := filepath.Join(dir, "overlay.go")
+	if err := os.WriteFile(replacement, []byte("package uboat\n\nimport \"errors\"\n\nvar ErrOverlay = errors.New(\"overlay\")\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	overlay := filepath.Join(dir, "overlay.json")
+	if err := os.WriteFile(overlay, []byte(`{"Replace": {"../../testdata/uboot/uboot.go": "`+replacement+`"}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	_, opts, args, err := parseArgs([]string{"-format=csv", "-columns=name", "-overlay=" + overlay, "../../testdata/uboot"}, io.Discard)
+	if err != nil {
+		t.Fatalf("parseArgs(...) = %v, want nil", err)
+	}
+	var buf bytes.Buffer
+	if err := runScan(context.Background(), opts, args, &buf); err != nil {
+		t.Fatalf("runScan(...) = %v, want nil", err)
+	}
+	if got, want := buf.String(), "ErrOverlay\n"; got != want {
+		t.Errorf("runScan(...) wrote %q, want %q", got, want)
+	}
+	if err := os.WriteFile(overlay, []byte(`{"Replace": {"../../testdata/uboot/uboot.go": ""}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := readOverlay(overlay); err == nil {
+		t.Error("readOverlay(...) with a deletion = nil, want error")
+	}
+}
+
+func TestRunTags(t *testing.T) {
+	for _, test := range []struct {
+		args []string
+		want string
+	}{
+		{[]string{"-columns=name", "../../testdata/tagged"}, "ErrUntagged\n"},
+		{[]string{"-columns=name", "-tags=extra,other", "../../testdata/tagged"}, "ErrTagged\nErrUntagged\n"},
+		{[]string{"-tags=extra", "scan", "-columns=name", "../../testdata/tagged"}, "ErrTagged\nErrUntagged\n"},
+	} {
+		_, opts, args, err := parseArgs(test.args, io.Discard)
+		if err != nil {
+			t.Fatalf("parseArgs(%q) = %v, want nil", test.args, err)
+		}
+		var buf bytes.Buffer
+		if err := runScan(context.Background(), opts, args, &buf); err != nil {
+			t.Fatalf("runScan(...) = %v, want nil", err)
+		}
+		if got := buf.String(); got != test.want {
+			t.Errorf("errorfinder %q wrote %q, want %q", test.args, got, test.want)
+		}
+	}
+}
+
+func TestRunIncludeTests(t *testing.T) {
+	_, opts, args, err := parseArgs([]string{"-include-tests", "-columns=path,name,test", "../../testdata/withtests"}, io.Discard)
+	if err != nil {
+		t.Fatalf("parseArgs(...) = %v, want nil", err)
+	}
+	var buf bytes.Buffer
+	if err := runScan(context.Background(), opts, args, &buf); err != nil {
+		t.Fatalf("runScan(...) = %v, want nil", err)
+	}
+	const path = "github.com/matttproud/errorfinder/testdata/withtests"
+	want := strings.Join([]string{
+		path + ",ErrLibrary,false",
+		path + "_test,ErrExternalTest,true",
+		path + ",errInternalTest,true",
+		"",
+	}, "\n")
+	if got := buf.String(); got != want {
+		t.Errorf("runScan(...) wrote:\n%v\nwant:\n%v", got, want)
+	}
 }
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"iter"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/matttproud/errorfinder"
+)
+
+// writeFile replaces the file at path with the output of write. The output
+// is staged in a temporary file in the same directory and renamed into place
+// only once write succeeds, so consumers never observe a partial file.
+func writeFile(path string, write func(io.Writer) error) (err error) {
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			f.Close()
+			os.Remove(f.Name())
+		}
+	}()
+	if err := write(f); err != nil {
+		return err
+	}
+	if err := f.Chmod(0o644); err != nil {
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+// packageDir maps an import path to a relative directory, replacing any
+// characters that are unsafe in file names.
+func packageDir(importPath string) string {
+	elems := strings.Split(importPath, "/")
+	for i, elem := range elems {
+		elem = strings.Map(func(r rune) rune {
+			switch {
+			case 'a' <= r && r <= 'z', 'A' <= r && r <= 'Z', '0' <= r && r <= '9', strings.ContainsRune("-._~+", r):
+				return r
+			}
+			return '_'
+		}, elem)
+		if elem == "" || elem == "." || elem == ".." {
+			elem = "_"
+		}
+		elems[i] = elem
+	}
+	return filepath.Join(elems...)
+}
+
+// writeSplit writes the defs of each import path to its own file in a
+// directory tree rooted at dir.
+func writeSplit(dir string, f format, opts options, defs iter.Seq2[errorfinder.Def, error]) error {
+	byPath := make(map[string][]errorfinder.Def)
+	for d, err := range defs {
+		if err != nil {
+			return err
+		}
+		byPath[d.ImportPath] = append(byPath[d.ImportPath], d)
+	}
+	name := "errors." + f.ext
+	if opts.Compress == "gzip" {
+		name += ".gz"
+	}
+	for _, path := range slices.Sorted(maps.Keys(byPath)) {
+		pkgDir := filepath.Join(dir, packageDir(path))
+		if err := os.MkdirAll(pkgDir, 0o755); err != nil {
+			return err
+		}
+		if err := writeFile(filepath.Join(pkgDir, name), func(w io.Writer) error {
+			return encode(w, f, opts, values(byPath[path]))
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// compress wraps w so that output is compressed with the named algorithm.
+// The returned close function flushes the compressed stream. Without
+// compression, w is returned as is.
+func compress(w io.Writer, algorithm string) (io.Writer, func() error, error) {
+	switch algorithm {
+	case "", "none":
+		return w, func() error { return nil }, nil
+	case "gzip":
+		zw := gzip.NewWriter(w)
+		return zw, zw.Close, nil
+	}
+	return nil, nil, fmt.Errorf("unknown compression %q", algorithm)
+}
@@ -0,0 +1,222 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+
+	"github.com/matttproud/errorfinder"
+)
+
+// The parquet format is written by hand to avoid a dependency on a Parquet
+// library. It produces a single row group of required UTF-8 string columns,
+// each stored as one uncompressed PLAIN-encoded data page. The page headers
+// and file metadata are Thrift structures serialized with the compact
+// protocol; see https://github.com/apache/parquet-format.
+
+const parquetMagic = "PAR1"
+
+// Thrift compact protocol type identifiers.
+const (
+	thriftI32    = 5
+	thriftI64    = 6
+	thriftBinary = 8
+	thriftList   = 9
+	thriftStruct = 12
+)
+
+// thriftWriter serializes Thrift structures with the compact protocol.
+type thriftWriter struct {
+	buf    bytes.Buffer
+	lastID []int16 // Last field ID written, one entry per open struct.
+}
+
+func (w *thriftWriter) varint(v uint64) {
+	w.buf.Write(binary.AppendUvarint(nil, v))
+}
+
+func (w *thriftWriter) zigzag(v int64) {
+	w.varint(uint64(v<<1) ^ uint64(v>>63))
+}
+
+func (w *thriftWriter) field(id int16, typ byte) {
+	last := &w.lastID[len(w.lastID)-1]
+	if delta := id - *last; delta > 0 && delta <= 15 {
+		w.buf.WriteByte(byte(delta)<<4 | typ)
+	} else {
+		w.buf.WriteByte(typ)
+		w.zigzag(int64(id))
+	}
+	*last = id
+}
+
+func (w *thriftWriter) beginStruct() { w.lastID = append(w.lastID, 0) }
+
+func (w *thriftWriter) endStruct() {
+	w.buf.WriteByte(0) // STOP
+	w.lastID = w.lastID[:len(w.lastID)-1]
+}
+
+func (w *thriftWriter) i32(id int16, v int32) {
+	w.field(id, thriftI32)
+	w.zigzag(int64(v))
+}
+
+func (w *thriftWriter) i64(id int16, v int64) {
+	w.field(id, thriftI64)
+	w.zigzag(v)
+}
+
+func (w *thriftWriter) binary(v string) {
+	w.varint(uint64(len(v)))
+	w.buf.WriteString(v)
+}
+
+func (w *thriftWriter) string(id int16, v string) {
+	w.field(id, thriftBinary)
+	w.binary(v)
+}
+
+func (w *thriftWriter) list(id int16, elemType byte, n int) {
+	w.field(id, thriftList)
+	if n < 15 {
+		w.buf.WriteByte(byte(n)<<4 | elemType)
+		return
+	}
+	w.buf.WriteByte(0xf0 | elemType)
+	w.varint(uint64(n))
+}
+
+func (w *thriftWriter) structField(id int16) {
+	w.field(id, thriftStruct)
+	w.beginStruct()
+}
+
+// Parquet enumeration values used by the writer.
+const (
+	parquetByteArray     = 6 // Type.BYTE_ARRAY
+	parquetRequired      = 0 // FieldRepetitionType.REQUIRED
+	parquetUTF8          = 0 // ConvertedType.UTF8
+	parquetPlain         = 0 // Encoding.PLAIN
+	parquetRLE           = 3 // Encoding.RLE
+	parquetUncompressed  = 0 // CompressionCodec.UNCOMPRESSED
+	parquetDataPage      = 0 // PageType.DATA_PAGE
+	parquetFormatVersion = 1
+)
+
+// parquetEncoder emits defs as a Parquet file with one column per selected
+// column.
+type parquetEncoder struct {
+	w    io.Writer
+	cols []errorfinder.Column
+	rows int
+	data []bytes.Buffer // PLAIN-encoded values, one buffer per column.
+}
+
+func newParquetEncoder(w io.Writer, opts options) (encoder, error) {
+	cols, err := parseColumns(opts.Columns, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &parquetEncoder{w: w, cols: cols, data: make([]bytes.Buffer, len(cols))}, nil
+}
+
+func (e *parquetEncoder) Encode(d errorfinder.Def) error {
+	for i, col := range e.cols {
+		v := col.Value(d)
+		e.data[i].Write(binary.LittleEndian.AppendUint32(nil, uint32(len(v))))
+		e.data[i].WriteString(v)
+	}
+	e.rows++
+	return nil
+}
+
+func (e *parquetEncoder) Close() error {
+	var file bytes.Buffer
+	file.WriteString(parquetMagic)
+	type chunk struct {
+		offset int64
+		size   int64
+	}
+	chunks := make([]chunk, len(e.cols))
+	if e.rows > 0 {
+		for i := range e.cols {
+			page := e.data[i].Bytes()
+			var hdr thriftWriter
+			hdr.beginStruct()
+			hdr.i32(1, parquetDataPage)
+			hdr.i32(2, int32(len(page)))
+			hdr.i32(3, int32(len(page)))
+			hdr.structField(5) // DataPageHeader
+			hdr.i32(1, int32(e.rows))
+			hdr.i32(2, parquetPlain)
+			hdr.i32(3, parquetRLE)
+			hdr.i32(4, parquetRLE)
+			hdr.endStruct()
+			hdr.endStruct()
+			chunks[i].offset = int64(file.Len())
+			chunks[i].size = int64(hdr.buf.Len() + len(page))
+			file.Write(hdr.buf.Bytes())
+			file.Write(page)
+		}
+	}
+
+	var meta thriftWriter
+	meta.beginStruct()
+	meta.i32(1, parquetFormatVersion)
+	meta.list(2, thriftStruct, len(e.cols)+1)
+	meta.beginStruct() // Root SchemaElement.
+	meta.string(4, "schema")
+	meta.i32(5, int32(len(e.cols)))
+	meta.endStruct()
+	for _, col := range e.cols {
+		meta.beginStruct()
+		meta.i32(1, parquetByteArray)
+		meta.i32(3, parquetRequired)
+		meta.string(4, col.Name)
+		meta.i32(6, parquetUTF8)
+		meta.structField(10) // LogicalType
+		meta.structField(1)  // StringType
+		meta.endStruct()
+		meta.endStruct()
+		meta.endStruct()
+	}
+	meta.i64(3, int64(e.rows))
+	if e.rows == 0 {
+		meta.list(4, thriftStruct, 0)
+	} else {
+		meta.list(4, thriftStruct, 1)
+		meta.beginStruct() // RowGroup
+		meta.list(1, thriftStruct, len(e.cols))
+		var total int64
+		for i, col := range e.cols {
+			total += chunks[i].size
+			meta.beginStruct() // ColumnChunk
+			meta.i64(2, chunks[i].offset)
+			meta.structField(3) // ColumnMetaData
+			meta.i32(1, parquetByteArray)
+			meta.list(2, thriftI32, 1)
+			meta.zigzag(parquetPlain)
+			meta.list(3, thriftBinary, 1)
+			meta.binary(col.Name)
+			meta.i32(4, parquetUncompressed)
+			meta.i64(5, int64(e.rows))
+			meta.i64(6, chunks[i].size)
+			meta.i64(7, chunks[i].size)
+			meta.i64(9, chunks[i].offset)
+			meta.endStruct()
+			meta.endStruct()
+		}
+		meta.i64(2, total)
+		meta.i64(3, int64(e.rows))
+		meta.endStruct()
+	}
+	meta.string(6, "errorfinder")
+	meta.endStruct()
+
+	file.Write(meta.buf.Bytes())
+	file.Write(binary.LittleEndian.AppendUint32(nil, uint32(meta.buf.Len())))
+	file.WriteString(parquetMagic)
+	_, err := e.w.Write(file.Bytes())
+	return err
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"fmt"
+	"go/token"
+	"io"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+
+	"github.com/matttproud/errorfinder"
+)
+
+// A packageError is an error encountered loading, parsing, or type checking a
+// package, whose defs may consequently be missing from the output.
+type packageError struct {
+	Package  string `json:"package,omitempty"`
+	Position string `json:"position,omitempty"`
+	Message  string `json:"message"`
+	Kind     string `json:"kind"` // One of list, parse, type, or unknown.
+}
+
+var packageErrorKinds = map[packages.ErrorKind]string{
+	packages.ListError:  "list",
+	packages.ParseError: "parse",
+	packages.TypeError:  "type",
+}
+
+func newPackageError(path string, e packages.Error) packageError {
+	pe := packageError{Package: path, Message: e.Msg, Kind: "unknown"}
+	if e.Pos != "" && e.Pos != "-" {
+		pe.Position = e.Pos
+	}
+	if kind, ok := packageErrorKinds[e.Kind]; ok {
+		pe.Kind = kind
+	}
+	return pe
+}
+
+func (e packageError) String() string {
+	if e.Position == "" {
+		return e.Message
+	}
+	return e.Position + ": " + e.Message
+}
+
+// parsePosition parses a position of the form file:line[:column] as reported
+// by go/packages.
+func parsePosition(s string) (token.Position, bool) {
+	var pos token.Position
+	rest, last, ok := cutLastColon(s)
+	if !ok {
+		return pos, false
+	}
+	n, err := strconv.Atoi(last)
+	if err != nil {
+		return pos, false
+	}
+	if file, line, ok := cutLastColon(rest); ok {
+		if l, err := strconv.Atoi(line); err == nil {
+			pos.Filename, pos.Line, pos.Column = file, l, n
+			return pos, true
+		}
+	}
+	pos.Filename, pos.Line = rest, n
+	return pos, true
+}
+
+func cutLastColon(s string) (before, after string, ok bool) {
+	i := strings.LastIndexByte(s, ':')
+	if i < 0 {
+		return s, "", false
+	}
+	return s[:i], s[i+1:], true
+}
+
+// packageErrors collects the errors of pkgs and their dependencies.
+func packageErrors(pkgs []*packages.Package) []packageError {
+	var errs []packageError
+	packages.Visit(pkgs, nil, func(pkg *packages.Package) {
+		for _, e := range pkg.Errors {
+			errs = append(errs, newPackageError(pkg.PkgPath, e))
+		}
+	})
+	return errs
+}
+
+// writePackageErrors reports errs to w, if it is non-nil.
+func writePackageErrors(w io.Writer, errs []packageError) {
+	if w == nil {
+		return
+	}
+	for _, e := range errs {
+		fmt.Fprintf(w, "errorfinder: %v\n", e)
+	}
+}
+
+// checkResult reports the package errors of res, the result of scanning the
+// packages matching patterns, and fails with -strict if there are any.
+func checkResult(opts options, patterns []string, res *errorfinder.Result) error {
+	errs := make([]packageError, len(res.PackageErrors))
+	for i, e := range res.PackageErrors {
+		errs[i] = newPackageError("", e)
+	}
+	writePackageErrors(opts.Stderr, errs)
+	return strictError(opts, patterns, errs)
+}
+
+// strictError fails the scan of the packages matching patterns with -strict if
+// there are package errors.
+func strictError(opts options, patterns []string, errs []packageError) error {
+	if !opts.Strict || len(errs) == 0 {
+		return nil
+	}
+	return &errorfinder.LoadError{Patterns: patterns, Err: fmt.Errorf("%d package errors with -strict", len(errs))}
+}
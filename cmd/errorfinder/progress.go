@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"golang.org/x/tools/go/packages"
+
+	"github.com/matttproud/errorfinder"
+)
+
+// newFinder returns a finder for the packages matching args. If opts.Progress
+// is set, the finder reports its progress there until the returned function is
+// called.
+func newFinder(opts options, args []string) (*errorfinder.Finder, func()) {
+	f := &errorfinder.Finder{Config: opts.Config, Patterns: args}
+	if opts.Progress == nil {
+		return f, func() {}
+	}
+	p := startProgress(opts.Progress, f)
+	return f, sync.OnceFunc(p.Stop)
+}
+
+// progressInterval is how often the status line is redrawn.
+const progressInterval = 100 * time.Millisecond
+
+// progress reports how far a finder has come on a terminal, rewriting a
+// single status line in place.
+type progress struct {
+	w     io.Writer
+	start time.Time
+	stop  chan struct{}
+	done  chan struct{}
+
+	mu        sync.Mutex
+	scanning  bool // Whether loading has finished.
+	scanStart time.Time
+	scanned   int // Packages whose extraction began.
+	total     int // Packages to scan.
+}
+
+func startProgress(w io.Writer, f *errorfinder.Finder) *progress {
+	p := &progress{w: w, start: time.Now(), stop: make(chan struct{}), done: make(chan struct{})}
+	f.OnScan = func(pkgs []*packages.Package) {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		p.scanning, p.scanStart, p.total = true, time.Now(), len(pkgs)
+	}
+	f.OnPackage = func(*packages.Package) {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		p.scanned++
+	}
+	go p.loop()
+	return p
+}
+
+func (p *progress) loop() {
+	defer close(p.done)
+	t := time.NewTicker(progressInterval)
+	defer t.Stop()
+	for {
+		select {
+		case <-p.stop:
+			fmt.Fprint(p.w, "\r\x1b[K")
+			return
+		case now := <-t.C:
+			fmt.Fprint(p.w, "\r\x1b[K"+p.status(now))
+		}
+	}
+}
+
+// status describes the progress as of now: the time spent loading or, once
+// extraction begins, the count of packages scanned and an estimate of the
+// time remaining.
+func (p *progress) status(now time.Time) string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.scanning {
+		return fmt.Sprintf("loading packages (%v)", now.Sub(p.start).Round(time.Second))
+	}
+	s := fmt.Sprintf("scanning packages %d/%d", p.scanned, p.total)
+	if p.scanned > 0 && p.scanned < p.total {
+		eta := now.Sub(p.scanStart) / time.Duration(p.scanned) * time.Duration(p.total-p.scanned)
+		s += fmt.Sprintf(" (ETA %v)", eta.Round(time.Second))
+	}
+	return s
+}
+
+// Stop clears the status line and stops reporting.
+func (p *progress) Stop() {
+	close(p.stop)
+	<-p.done
+}
@@ -0,0 +1,598 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/matttproud/errorfinder"
+)
+
+// The encoders in this file implement the wire and text formats for the
+// messages in errorfinder.proto by hand, which keeps the tool free of a
+// protocol buffer runtime dependency. Field numbers must be kept in sync with
+// the schema.
+
+const (
+	wireVarint = 0
+	wireBytes  = 2
+)
+
+func appendVarint(b []byte, v uint64) []byte {
+	for v >= 0x80 {
+		b = append(b, byte(v)|0x80)
+		v >>= 7
+	}
+	return append(b, byte(v))
+}
+
+func appendTag(b []byte, num, wireType int) []byte {
+	return appendVarint(b, uint64(num)<<3|uint64(wireType))
+}
+
+func appendEnumField(b []byte, num int, v int) []byte {
+	if v == 0 {
+		return b
+	}
+	b = appendTag(b, num, wireVarint)
+	return appendVarint(b, uint64(v))
+}
+
+func appendBoolField(b []byte, num int, v bool) []byte {
+	if !v {
+		return b
+	}
+	b = appendTag(b, num, wireVarint)
+	return appendVarint(b, 1)
+}
+
+func appendUintField(b []byte, num int, v uint64) []byte {
+	if v == 0 {
+		return b
+	}
+	b = appendTag(b, num, wireVarint)
+	return appendVarint(b, v)
+}
+
+func appendBytesField(b []byte, num int, v []byte) []byte {
+	if len(v) == 0 {
+		return b
+	}
+	b = appendTag(b, num, wireBytes)
+	b = appendVarint(b, uint64(len(v)))
+	return append(b, v...)
+}
+
+// appendProtoDef appends the encoding of d as a Def message with only the
+// fields of the schema version selected by opts.
+func appendProtoDef(b []byte, d errorfinder.Def, opts options) []byte {
+	b = appendEnumField(b, 1, int(d.ErrorType))
+	b = appendEnumField(b, 2, int(d.ExportType))
+	b = appendBytesField(b, 3, []byte(d.ImportPath))
+	b = appendBytesField(b, 4, []byte(d.PackageName))
+	b = appendBytesField(b, 5, []byte(d.Name))
+	b = appendBytesField(b, 6, []byte(d.BackingTypeName))
+	if opts.has("position") {
+		b = appendBytesField(b, 7, []byte(d.Position.String()))
+	}
+	if opts.has("doc") {
+		b = appendBytesField(b, 8, []byte(d.Doc))
+	}
+	if opts.has("message") {
+		b = appendBytesField(b, 9, []byte(d.Message))
+	}
+	if opts.has("deprecated") {
+		b = appendBoolField(b, 10, d.Deprecated)
+	}
+	if opts.has("deprecation") {
+		b = appendBytesField(b, 11, []byte(d.Deprecation))
+	}
+	if opts.has("errorTypeName") {
+		b = appendBytesField(b, 12, []byte(d.ErrorType.Name()))
+	}
+	if opts.has("test") {
+		b = appendBoolField(b, 13, d.Test)
+	}
+	if opts.has("initializer") {
+		b = appendBytesField(b, 14, []byte(d.Initializer))
+	}
+	if opts.has("wrapping") {
+		b = appendBoolField(b, 15, d.Wrapping)
+	}
+	if opts.has("const") {
+		b = appendBoolField(b, 16, d.Const)
+	}
+	if opts.has("func") {
+		b = appendBytesField(b, 17, []byte(d.Func))
+	}
+	if opts.has("unwrap") {
+		b = appendBytesField(b, 18, []byte(d.Unwrap))
+	}
+	if opts.has("wraps") {
+		for _, w := range d.Wraps {
+			b = appendBytesField(b, 19, []byte(w))
+		}
+	}
+	if opts.has("is") {
+		b = appendBoolField(b, 20, d.Is)
+	}
+	if opts.has("as") {
+		b = appendBoolField(b, 21, d.As)
+	}
+	if opts.has("receiverKind") {
+		b = appendBytesField(b, 22, []byte(d.ReceiverKind))
+	}
+	if opts.has("instantiations") {
+		for _, inst := range d.Instantiations {
+			b = appendBytesField(b, 23, []byte(inst))
+		}
+	}
+	if opts.has("methods") {
+		for _, m := range d.Methods {
+			b = appendBytesField(b, 24, []byte(m))
+		}
+	}
+	if opts.has("generated") {
+		b = appendBoolField(b, 25, d.Generated)
+	}
+	if opts.has("vendored") {
+		b = appendBoolField(b, 26, d.Vendored)
+	}
+	if opts.has("vendorModule") {
+		b = appendBytesField(b, 27, []byte(d.VendorModule))
+	}
+	if opts.has("embedding") {
+		for _, chain := range d.Embedding {
+			b = appendBytesField(b, 28, []byte(chain))
+		}
+	}
+	if opts.has("call") {
+		b = appendBytesField(b, 29, []byte(d.Call))
+	}
+	if opts.has("shadowsStdlib") {
+		for _, std := range d.ShadowsStdlib {
+			b = appendBytesField(b, 30, []byte(std))
+		}
+	}
+	if opts.has("wrapTypes") {
+		for _, t := range d.WrapTypes {
+			b = appendBytesField(b, 31, []byte(t))
+		}
+	}
+	if opts.has("codeField") {
+		b = appendBytesField(b, 32, []byte(d.CodeField))
+	}
+	if opts.has("codeType") {
+		b = appendBytesField(b, 33, []byte(d.CodeType))
+	}
+	if opts.has("codes") {
+		for _, c := range d.Codes {
+			b = appendBytesField(b, 34, []byte(c))
+		}
+	}
+	if opts.has("grpcCode") {
+		b = appendBytesField(b, 35, []byte(d.GRPCCode))
+	}
+	if opts.has("chains") {
+		for _, chain := range d.Chains {
+			b = appendBytesField(b, 36, []byte(chain))
+		}
+	}
+	if opts.has("panics") {
+		for _, fn := range d.Panics {
+			b = appendBytesField(b, 37, []byte(fn))
+		}
+	}
+	if opts.has("instanceOf") {
+		b = appendBytesField(b, 38, []byte(d.InstanceOf))
+	}
+	if opts.has("reassigned") {
+		b = appendBoolField(b, 39, d.Reassigned)
+	}
+	if opts.has("helper") {
+		b = appendBytesField(b, 40, []byte(d.Helper))
+	}
+	if opts.has("uses") {
+		for _, use := range d.Uses {
+			var m []byte
+			m = appendBytesField(m, 1, []byte(use.Kind))
+			m = appendBytesField(m, 2, []byte(use.Func))
+			m = appendBytesField(m, 3, []byte(use.Position.String()))
+			m = appendBytesField(m, 4, []byte(use.Problem))
+			b = appendBytesField(b, 41, m)
+		}
+	}
+	if opts.has("dead") {
+		b = appendBoolField(b, 42, d.Dead)
+	}
+	if opts.has("reach") {
+		b = appendBytesField(b, 43, []byte(d.Reach))
+	}
+	if opts.has("entryPoints") {
+		for _, fn := range d.EntryPoints {
+			b = appendBytesField(b, 44, []byte(fn))
+		}
+	}
+	if opts.has("refCount") {
+		b = appendUintField(b, 45, uint64(d.RefCount))
+	}
+	if opts.has("consumers") {
+		for _, path := range d.Consumers {
+			b = appendBytesField(b, 46, []byte(path))
+		}
+	}
+	if opts.has("returnedBy") {
+		for _, fn := range d.ReturnedBy {
+			b = appendBytesField(b, 47, []byte(fn))
+		}
+	}
+	if opts.has("undocumented") {
+		for _, fn := range d.Undocumented {
+			b = appendBytesField(b, 48, []byte(fn))
+		}
+	}
+	if opts.has("returnedBare") {
+		b = appendUintField(b, 49, uint64(d.ReturnedBare))
+	}
+	if opts.has("wrapped") {
+		b = appendUintField(b, 50, uint64(d.Wrapped))
+	}
+	if opts.has("untested") {
+		b = appendBoolField(b, 51, d.Untested)
+	}
+	if opts.has("interfaceMethods") {
+		for _, m := range d.InterfaceMethods {
+			b = appendBytesField(b, 52, []byte(m))
+		}
+	}
+	if opts.has("handlers") {
+		for _, fn := range d.Handlers {
+			b = appendBytesField(b, 53, []byte(fn))
+		}
+	}
+	if opts.has("satisfies") {
+		for _, iface := range d.Satisfies {
+			b = appendBytesField(b, 54, []byte(iface))
+		}
+	}
+	if opts.has("retryable") {
+		b = appendBytesField(b, 55, []byte(d.Retryable))
+	}
+	if opts.has("stack") {
+		b = appendBoolField(b, 56, d.Stack)
+	}
+	if opts.has("lateInit") {
+		b = appendBoolField(b, 57, d.LateInit)
+	}
+	if opts.has("fields") {
+		for _, f := range d.Fields {
+			b = appendBytesField(b, 58, []byte(f))
+		}
+	}
+	return b
+}
+
+// protoEncoder emits an Inventory message in the binary wire format. The
+// version fields are written first, after which each def can be written as
+// soon as it is encoded, since the wire format permits fields in any order.
+type protoEncoder struct {
+	w    io.Writer
+	opts options
+	buf  []byte
+}
+
+func newProtoEncoder(w io.Writer, opts options) (encoder, error) {
+	var b []byte
+	b = appendTag(b, 2, wireVarint)
+	b = appendVarint(b, uint64(opts.schema()))
+	b = appendBytesField(b, 3, []byte(buildVersion()))
+	if opts.has("packageErrors") {
+		for _, pe := range opts.PackageErrors {
+			var m []byte
+			m = appendBytesField(m, 1, []byte(pe.Package))
+			m = appendBytesField(m, 2, []byte(pe.Position))
+			m = appendBytesField(m, 3, []byte(pe.Message))
+			m = appendBytesField(m, 4, []byte(pe.Kind))
+			b = appendBytesField(b, 4, m)
+		}
+	}
+	if _, err := w.Write(b); err != nil {
+		return nil, err
+	}
+	return &protoEncoder{w: w, opts: opts}, nil
+}
+
+func (e *protoEncoder) Encode(d errorfinder.Def) error {
+	e.buf = appendBytesField(e.buf[:0], 1, appendProtoDef(nil, d, e.opts))
+	_, err := e.w.Write(e.buf)
+	return err
+}
+
+func (e *protoEncoder) Close() error { return nil }
+
+var protoErrorTypeNames = map[errorfinder.ErrorType]string{
+	errorfinder.ErrorTypeUnknown:     "ERROR_TYPE_UNKNOWN",
+	errorfinder.ErrorTypeSentinel:    "ERROR_TYPE_SENTINEL",
+	errorfinder.ErrorTypeStructured:  "ERROR_TYPE_STRUCTURED",
+	errorfinder.ErrorTypeConstructor: "ERROR_TYPE_CONSTRUCTOR",
+	errorfinder.ErrorTypeAlias:       "ERROR_TYPE_ALIAS",
+	errorfinder.ErrorTypeInterface:   "ERROR_TYPE_INTERFACE",
+}
+
+var protoExportTypeNames = map[errorfinder.ExportType]string{
+	errorfinder.ExportTypeUnknown:    "EXPORT_TYPE_UNKNOWN",
+	errorfinder.ExportTypeExported:   "EXPORT_TYPE_EXPORTED",
+	errorfinder.ExportTypeUnexported: "EXPORT_TYPE_UNEXPORTED",
+}
+
+// quoteProtoText quotes s as a protocol buffer text format string literal.
+func quoteProtoText(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '"', '\\':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			if c < 0x20 || c == 0x7f {
+				fmt.Fprintf(&b, `\%03o`, c)
+				continue
+			}
+			b.WriteByte(c)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// prototextEncoder emits an Inventory message in the text format.
+type prototextEncoder struct {
+	w    *bufio.Writer
+	opts options
+}
+
+func newPrototextEncoder(w io.Writer, opts options) (encoder, error) {
+	e := &prototextEncoder{w: bufio.NewWriter(w), opts: opts}
+	fmt.Fprintf(e.w, "schema_version: %d\n", opts.schema())
+	fmt.Fprintf(e.w, "version: %v\n", quoteProtoText(buildVersion()))
+	if opts.has("packageErrors") {
+		for _, pe := range opts.PackageErrors {
+			fmt.Fprintln(e.w, "package_errors {")
+			fmt.Fprintf(e.w, "  package: %v\n", quoteProtoText(pe.Package))
+			if pe.Position != "" {
+				fmt.Fprintf(e.w, "  position: %v\n", quoteProtoText(pe.Position))
+			}
+			fmt.Fprintf(e.w, "  message: %v\n", quoteProtoText(pe.Message))
+			fmt.Fprintf(e.w, "  kind: %v\n", quoteProtoText(pe.Kind))
+			fmt.Fprintln(e.w, "}")
+		}
+	}
+	return e, nil
+}
+
+func (e *prototextEncoder) Encode(d errorfinder.Def) error {
+	fmt.Fprintln(e.w, "defs {")
+	if name, ok := protoErrorTypeNames[d.ErrorType]; ok {
+		fmt.Fprintf(e.w, "  error_type: %v\n", name)
+	} else {
+		fmt.Fprintf(e.w, "  error_type: %d\n", d.ErrorType)
+	}
+	fmt.Fprintf(e.w, "  export_type: %v\n", protoExportTypeNames[d.ExportType])
+	fmt.Fprintf(e.w, "  import_path: %v\n", quoteProtoText(d.ImportPath))
+	fmt.Fprintf(e.w, "  package_name: %v\n", quoteProtoText(d.PackageName))
+	fmt.Fprintf(e.w, "  name: %v\n", quoteProtoText(d.Name))
+	fmt.Fprintf(e.w, "  backing_type_name: %v\n", quoteProtoText(d.BackingTypeName))
+	if e.opts.has("position") {
+		fmt.Fprintf(e.w, "  position: %v\n", quoteProtoText(d.Position.String()))
+	}
+	if d.Doc != "" && e.opts.has("doc") {
+		fmt.Fprintf(e.w, "  doc: %v\n", quoteProtoText(d.Doc))
+	}
+	if d.Message != "" && e.opts.has("message") {
+		fmt.Fprintf(e.w, "  message: %v\n", quoteProtoText(d.Message))
+	}
+	if d.Deprecated && e.opts.has("deprecated") {
+		fmt.Fprintln(e.w, "  deprecated: true")
+	}
+	if d.Deprecation != "" && e.opts.has("deprecation") {
+		fmt.Fprintf(e.w, "  deprecation: %v\n", quoteProtoText(d.Deprecation))
+	}
+	if e.opts.has("errorTypeName") {
+		fmt.Fprintf(e.w, "  error_type_name: %v\n", quoteProtoText(d.ErrorType.Name()))
+	}
+	if d.Test && e.opts.has("test") {
+		fmt.Fprintln(e.w, "  test: true")
+	}
+	if d.Initializer != "" && e.opts.has("initializer") {
+		fmt.Fprintf(e.w, "  initializer: %v\n", quoteProtoText(d.Initializer))
+	}
+	if d.Wrapping && e.opts.has("wrapping") {
+		fmt.Fprintln(e.w, "  wrapping: true")
+	}
+	if d.Const && e.opts.has("const") {
+		fmt.Fprintln(e.w, "  constant: true")
+	}
+	if d.Func != "" && e.opts.has("func") {
+		fmt.Fprintf(e.w, "  func: %v\n", quoteProtoText(d.Func))
+	}
+	if d.Unwrap != "" && e.opts.has("unwrap") {
+		fmt.Fprintf(e.w, "  unwrap: %v\n", quoteProtoText(d.Unwrap))
+	}
+	if e.opts.has("wraps") {
+		for _, w := range d.Wraps {
+			fmt.Fprintf(e.w, "  wraps: %v\n", quoteProtoText(w))
+		}
+	}
+	if d.Is && e.opts.has("is") {
+		fmt.Fprintln(e.w, "  is: true")
+	}
+	if d.As && e.opts.has("as") {
+		fmt.Fprintln(e.w, "  as: true")
+	}
+	if d.ReceiverKind != "" && e.opts.has("receiverKind") {
+		fmt.Fprintf(e.w, "  receiver_kind: %v\n", quoteProtoText(d.ReceiverKind))
+	}
+	if e.opts.has("instantiations") {
+		for _, inst := range d.Instantiations {
+			fmt.Fprintf(e.w, "  instantiations: %v\n", quoteProtoText(inst))
+		}
+	}
+	if e.opts.has("methods") {
+		for _, m := range d.Methods {
+			fmt.Fprintf(e.w, "  methods: %v\n", quoteProtoText(m))
+		}
+	}
+	if d.Generated && e.opts.has("generated") {
+		fmt.Fprintln(e.w, "  generated: true")
+	}
+	if d.Vendored && e.opts.has("vendored") {
+		fmt.Fprintln(e.w, "  vendored: true")
+	}
+	if d.VendorModule != "" && e.opts.has("vendorModule") {
+		fmt.Fprintf(e.w, "  vendor_module: %v\n", quoteProtoText(d.VendorModule))
+	}
+	if e.opts.has("embedding") {
+		for _, chain := range d.Embedding {
+			fmt.Fprintf(e.w, "  embedding: %v\n", quoteProtoText(chain))
+		}
+	}
+	if d.Call != "" && e.opts.has("call") {
+		fmt.Fprintf(e.w, "  call: %v\n", quoteProtoText(d.Call))
+	}
+	if e.opts.has("shadowsStdlib") {
+		for _, std := range d.ShadowsStdlib {
+			fmt.Fprintf(e.w, "  shadows_stdlib: %v\n", quoteProtoText(std))
+		}
+	}
+	if e.opts.has("wrapTypes") {
+		for _, t := range d.WrapTypes {
+			fmt.Fprintf(e.w, "  wrap_types: %v\n", quoteProtoText(t))
+		}
+	}
+	if d.CodeField != "" && e.opts.has("codeField") {
+		fmt.Fprintf(e.w, "  code_field: %v\n", quoteProtoText(d.CodeField))
+	}
+	if d.CodeType != "" && e.opts.has("codeType") {
+		fmt.Fprintf(e.w, "  code_type: %v\n", quoteProtoText(d.CodeType))
+	}
+	if e.opts.has("codes") {
+		for _, c := range d.Codes {
+			fmt.Fprintf(e.w, "  codes: %v\n", quoteProtoText(c))
+		}
+	}
+	if d.GRPCCode != "" && e.opts.has("grpcCode") {
+		fmt.Fprintf(e.w, "  grpc_code: %v\n", quoteProtoText(d.GRPCCode))
+	}
+	if e.opts.has("chains") {
+		for _, chain := range d.Chains {
+			fmt.Fprintf(e.w, "  chains: %v\n", quoteProtoText(chain))
+		}
+	}
+	if e.opts.has("panics") {
+		for _, fn := range d.Panics {
+			fmt.Fprintf(e.w, "  panics: %v\n", quoteProtoText(fn))
+		}
+	}
+	if d.InstanceOf != "" && e.opts.has("instanceOf") {
+		fmt.Fprintf(e.w, "  instance_of: %v\n", quoteProtoText(d.InstanceOf))
+	}
+	if d.Reassigned && e.opts.has("reassigned") {
+		fmt.Fprintln(e.w, "  reassigned: true")
+	}
+	if d.Helper != "" && e.opts.has("helper") {
+		fmt.Fprintf(e.w, "  helper: %v\n", quoteProtoText(d.Helper))
+	}
+	if e.opts.has("uses") {
+		for _, use := range d.Uses {
+			fmt.Fprintln(e.w, "  uses {")
+			fmt.Fprintf(e.w, "    kind: %v\n", quoteProtoText(string(use.Kind)))
+			if use.Func != "" {
+				fmt.Fprintf(e.w, "    func: %v\n", quoteProtoText(use.Func))
+			}
+			fmt.Fprintf(e.w, "    position: %v\n", quoteProtoText(use.Position.String()))
+			if use.Problem != "" {
+				fmt.Fprintf(e.w, "    problem: %v\n", quoteProtoText(use.Problem))
+			}
+			fmt.Fprintln(e.w, "  }")
+		}
+	}
+	if d.Dead && e.opts.has("dead") {
+		fmt.Fprintln(e.w, "  dead: true")
+	}
+	if d.Reach != "" && e.opts.has("reach") {
+		fmt.Fprintf(e.w, "  reach: %v\n", quoteProtoText(d.Reach))
+	}
+	if e.opts.has("entryPoints") {
+		for _, fn := range d.EntryPoints {
+			fmt.Fprintf(e.w, "  entry_points: %v\n", quoteProtoText(fn))
+		}
+	}
+	if d.RefCount > 0 && e.opts.has("refCount") {
+		fmt.Fprintf(e.w, "  ref_count: %d\n", d.RefCount)
+	}
+	if e.opts.has("consumers") {
+		for _, path := range d.Consumers {
+			fmt.Fprintf(e.w, "  consumers: %v\n", quoteProtoText(path))
+		}
+	}
+	if e.opts.has("returnedBy") {
+		for _, fn := range d.ReturnedBy {
+			fmt.Fprintf(e.w, "  returned_by: %v\n", quoteProtoText(fn))
+		}
+	}
+	if e.opts.has("undocumented") {
+		for _, fn := range d.Undocumented {
+			fmt.Fprintf(e.w, "  undocumented: %v\n", quoteProtoText(fn))
+		}
+	}
+	if d.ReturnedBare > 0 && e.opts.has("returnedBare") {
+		fmt.Fprintf(e.w, "  returned_bare: %d\n", d.ReturnedBare)
+	}
+	if d.Wrapped > 0 && e.opts.has("wrapped") {
+		fmt.Fprintf(e.w, "  wrapped: %d\n", d.Wrapped)
+	}
+	if d.Untested && e.opts.has("untested") {
+		fmt.Fprintln(e.w, "  untested: true")
+	}
+	if e.opts.has("interfaceMethods") {
+		for _, m := range d.InterfaceMethods {
+			fmt.Fprintf(e.w, "  interface_methods: %v\n", quoteProtoText(m))
+		}
+	}
+	if e.opts.has("handlers") {
+		for _, fn := range d.Handlers {
+			fmt.Fprintf(e.w, "  handlers: %v\n", quoteProtoText(fn))
+		}
+	}
+	if e.opts.has("satisfies") {
+		for _, iface := range d.Satisfies {
+			fmt.Fprintf(e.w, "  satisfies: %v\n", quoteProtoText(iface))
+		}
+	}
+	if d.Retryable != "" && e.opts.has("retryable") {
+		fmt.Fprintf(e.w, "  retryable: %v\n", quoteProtoText(d.Retryable))
+	}
+	if d.Stack && e.opts.has("stack") {
+		fmt.Fprintln(e.w, "  stack: true")
+	}
+	if d.LateInit && e.opts.has("lateInit") {
+		fmt.Fprintln(e.w, "  late_init: true")
+	}
+	if e.opts.has("fields") {
+		for _, f := range d.Fields {
+			fmt.Fprintf(e.w, "  fields: %v\n", quoteProtoText(f))
+		}
+	}
+	_, err := fmt.Fprintln(e.w, "}")
+	return err
+}
+
+func (e *prototextEncoder) Close() error { return e.w.Flush() }
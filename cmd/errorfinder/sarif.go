@@ -0,0 +1,180 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/matttproud/errorfinder"
+)
+
+// The types in this file model the subset of SARIF 2.1.0 that errorfinder
+// emits. See https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html.
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool        sarifTool         `json:"tool"`
+	Invocations []sarifInvocation `json:"invocations,omitempty"`
+	Results     []sarifResult     `json:"results"`
+}
+
+// sarifInvocation reports package errors as notifications, which code
+// scanning platforms surface as problems with the analysis itself.
+type sarifInvocation struct {
+	ExecutionSuccessful        bool                `json:"executionSuccessful"`
+	ToolExecutionNotifications []sarifNotification `json:"toolExecutionNotifications"`
+}
+
+type sarifNotification struct {
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations,omitempty"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           sarifRegion           `json:"region"`
+}
+
+type sarifArtifactLocation struct {
+	URI       string `json:"uri"`
+	URIBaseID string `json:"uriBaseId,omitempty"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn"`
+}
+
+var sarifDescriptions = map[errorfinder.ErrorType]string{
+	errorfinder.ErrorTypeSentinel:    "Error sentinel value.",
+	errorfinder.ErrorTypeStructured:  "Structured error type.",
+	errorfinder.ErrorTypeConstructor: "Function constructing errors of a concrete type.",
+	errorfinder.ErrorTypeAlias:       "Type alias of an error type.",
+	errorfinder.ErrorTypeInterface:   "Interface type embedding error.",
+}
+
+// newSARIFRule describes the rule for defs of error type t, which is named after
+// it.
+func newSARIFRule(t errorfinder.ErrorType) sarifRule {
+	desc, ok := sarifDescriptions[t]
+	if !ok {
+		desc = "Error of type " + t.Name() + "."
+	}
+	return sarifRule{ID: t.Name(), ShortDescription: sarifMessage{desc}}
+}
+
+// sarifEncoder emits defs as SARIF results, one rule per error type.
+type sarifEncoder struct {
+	w   io.Writer
+	wd  string
+	run sarifRun
+}
+
+func newSARIFEncoder(w io.Writer, opts options) (encoder, error) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+	e := &sarifEncoder{w: w, wd: wd}
+	e.run.Tool.Driver = sarifDriver{
+		Name:           "errorfinder",
+		InformationURI: "https://github.com/matttproud/errorfinder",
+	}
+	for _, t := range errorfinder.ErrorTypes() {
+		e.run.Tool.Driver.Rules = append(e.run.Tool.Driver.Rules, newSARIFRule(t))
+	}
+	e.run.Results = []sarifResult{}
+	if len(opts.PackageErrors) > 0 {
+		inv := sarifInvocation{ExecutionSuccessful: true}
+		for _, pe := range opts.PackageErrors {
+			n := sarifNotification{Level: "warning", Message: sarifMessage{fmt.Sprintf("%v: %v", pe.Package, pe.Message)}}
+			if pos, ok := parsePosition(pe.Position); ok {
+				n.Locations = []sarifLocation{{
+					PhysicalLocation: sarifPhysicalLocation{
+						ArtifactLocation: e.artifactLocation(pos.Filename),
+						Region:           sarifRegion{StartLine: pos.Line, StartColumn: pos.Column},
+					},
+				}}
+			}
+			inv.ToolExecutionNotifications = append(inv.ToolExecutionNotifications, n)
+		}
+		e.run.Invocations = []sarifInvocation{inv}
+	}
+	return e, nil
+}
+
+// artifactLocation reports file relative to the working directory where
+// possible, since code scanning platforms resolve results against the root
+// of the checkout.
+func (e *sarifEncoder) artifactLocation(file string) sarifArtifactLocation {
+	if rel, err := filepath.Rel(e.wd, file); err == nil && !strings.HasPrefix(rel, "..") {
+		return sarifArtifactLocation{URI: filepath.ToSlash(rel), URIBaseID: "%SRCROOT%"}
+	}
+	return sarifArtifactLocation{URI: (&url.URL{Scheme: "file", Path: filepath.ToSlash(file)}).String()}
+}
+
+func (e *sarifEncoder) Encode(d errorfinder.Def) error {
+	rule := newSARIFRule(d.ErrorType)
+	e.run.Results = append(e.run.Results, sarifResult{
+		RuleID:  rule.ID,
+		Level:   "note",
+		Message: sarifMessage{fmt.Sprintf("%v %v %v.%v of type %v", strings.TrimPrefix(d.ExportType.String(), "ExportType"), rule.ID, d.ImportPath, d.Name, d.BackingTypeName)},
+		Locations: []sarifLocation{{
+			PhysicalLocation: sarifPhysicalLocation{
+				ArtifactLocation: e.artifactLocation(d.Position.Filename),
+				Region:           sarifRegion{StartLine: d.Position.Line, StartColumn: d.Position.Column},
+			},
+		}},
+	})
+	return nil
+}
+
+func (e *sarifEncoder) Close() error {
+	enc := json.NewEncoder(e.w)
+	enc.SetIndent("", "\t")
+	return enc.Encode(sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{e.run},
+	})
+}
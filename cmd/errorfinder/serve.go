@@ -0,0 +1,67 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net"
+	"net/http"
+)
+
+// serveHandler scans the packages matching args afresh for each request and
+// responds with the defs in the format named by the format query parameter,
+// html by default.
+func serveHandler(opts options, args []string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		opts := opts
+		opts.Progress = nil
+		opts.Format = r.URL.Query().Get("format")
+		if opts.Format == "" {
+			opts.Format = "html"
+		}
+		f, ok := formats[opts.Format]
+		if !ok || opts.Format == "template" {
+			http.Error(w, fmt.Sprintf("unknown format %q", opts.Format), http.StatusBadRequest)
+			return
+		}
+		var buf bytes.Buffer
+		if err := runScan(r.Context(), opts, args, &buf); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		ctype := mime.TypeByExtension("." + f.ext)
+		if ctype == "" {
+			ctype = "application/octet-stream"
+		}
+		w.Header().Set("Content-Type", ctype)
+		w.Write(buf.Bytes())
+	})
+}
+
+// runServe implements the serve command, which serves reports of the defs
+// found in the packages matching args until ctx is done.
+func runServe(ctx context.Context, opts options, args []string, stdout io.Writer) error {
+	ln, err := net.Listen("tcp", opts.Addr)
+	if err != nil {
+		return err
+	}
+	srv := &http.Server{
+		Handler:     serveHandler(opts, args),
+		BaseContext: func(net.Listener) context.Context { return ctx },
+	}
+	go func() {
+		<-ctx.Done()
+		if err := srv.Shutdown(context.Background()); err != nil {
+			log.Println(err)
+		}
+	}()
+	fmt.Fprintf(stdout, "serving on http://%v/\n", ln.Addr())
+	if err := srv.Serve(ln); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"io"
+	"maps"
+	"slices"
+)
+
+// runStdlib implements the stdlib command, which writes the places where the
+// packages matching args return the standard library's sentinels and error
+// types or compare errors against them as CSV records of the error, the kind
+// of use, the enclosing function, and the position of the use, sorted by
+// error.
+func runStdlib(ctx context.Context, opts options, args []string, stdout io.Writer) error {
+	opts.Stdlib = true
+	finder, stop := newFinder(opts, args)
+	res, err := finder.Find(ctx)
+	stop()
+	if err != nil {
+		return err
+	}
+	if err := checkResult(opts, args, res); err != nil {
+		return err
+	}
+	w := csv.NewWriter(stdout)
+	if opts.Header {
+		if err := w.Write([]string{"error", "kind", "func", "position"}); err != nil {
+			return err
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(res.Stdlib)) {
+		for _, use := range res.Stdlib[name] {
+			if err := w.Write([]string{name, string(use.Kind), use.Func, use.Position.String()}); err != nil {
+				return err
+			}
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/matttproud/errorfinder"
+)
+
+// ANSI SGR sequences used by the table format.
+const (
+	ansiReset   = "\x1b[0m"
+	ansiBold    = "\x1b[1m"
+	ansiGreen   = "\x1b[32m"
+	ansiCyan    = "\x1b[36m"
+	ansiBlue    = "\x1b[34m"
+	ansiMagenta = "\x1b[35m"
+	ansiYellow  = "\x1b[33m"
+)
+
+var tableKindColors = map[errorfinder.ErrorType]string{
+	errorfinder.ErrorTypeSentinel:    ansiGreen,
+	errorfinder.ErrorTypeStructured:  ansiCyan,
+	errorfinder.ErrorTypeConstructor: ansiBlue,
+	errorfinder.ErrorTypeAlias:       ansiMagenta,
+	errorfinder.ErrorTypeInterface:   ansiYellow,
+}
+
+// isTerminal reports whether w is a character device such as a terminal.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
+
+// tableEncoder renders defs as an aligned table for interactive use.
+// Colorized output distinguishes error types and emphasizes exported names.
+type tableEncoder struct {
+	w     *bufio.Writer
+	color bool
+	cols  []errorfinder.Column
+	defs  []errorfinder.Def
+}
+
+func newTableEncoder(w io.Writer, opts options) (encoder, error) {
+	cols, err := parseColumns(opts.Columns, opts)
+	if err != nil {
+		return nil, err
+	}
+	var color bool
+	switch opts.Color {
+	case "", "auto":
+		color = isTerminal(w)
+	case "always":
+		color = true
+	case "never":
+	default:
+		return nil, fmt.Errorf("invalid color mode %q", opts.Color)
+	}
+	return &tableEncoder{w: bufio.NewWriter(w), color: color, cols: cols}, nil
+}
+
+func (e *tableEncoder) Encode(d errorfinder.Def) error {
+	e.defs = append(e.defs, d)
+	return nil
+}
+
+// style returns the SGR sequence for the colorized cell of col in d's row.
+func (e *tableEncoder) style(d errorfinder.Def, col errorfinder.Column) string {
+	style := tableKindColors[d.ErrorType]
+	if col.Name == "name" && d.ExportType == errorfinder.ExportTypeExported {
+		style += ansiBold
+	}
+	return style
+}
+
+func (e *tableEncoder) Close() error {
+	widths := make([]int, len(e.cols))
+	rows := make([][]string, len(e.defs)+1)
+	rows[0] = make([]string, len(e.cols))
+	for i, col := range e.cols {
+		rows[0][i] = strings.ToUpper(col.Name)
+	}
+	for r, d := range e.defs {
+		rows[r+1] = make([]string, len(e.cols))
+		for i, col := range e.cols {
+			// Collapse multi-line values such as doc comments onto one line.
+			rows[r+1][i] = strings.Join(strings.Fields(col.Value(d)), " ")
+		}
+	}
+	for _, row := range rows {
+		for i, cell := range row {
+			widths[i] = max(widths[i], utf8.RuneCountInString(cell))
+		}
+	}
+	for r, row := range rows {
+		for i, cell := range row {
+			var style string
+			switch {
+			case !e.color:
+			case r == 0:
+				style = ansiBold
+			default:
+				style = e.style(e.defs[r-1], e.cols[i])
+			}
+			if style != "" {
+				e.w.WriteString(style + cell + ansiReset)
+			} else {
+				e.w.WriteString(cell)
+			}
+			if i < len(row)-1 {
+				e.w.WriteString(strings.Repeat(" ", widths[i]-utf8.RuneCountInString(cell)+2))
+			}
+		}
+		e.w.WriteByte('\n')
+	}
+	return e.w.Flush()
+}
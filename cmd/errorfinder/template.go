@@ -0,0 +1,38 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"path/filepath"
+	"text/template"
+
+	"github.com/matttproud/errorfinder"
+)
+
+// templateEncoder executes a user-supplied text/template with the sorted
+// slice of defs as its data.
+type templateEncoder struct {
+	w    io.Writer
+	tmpl *template.Template
+	defs []errorfinder.Def
+}
+
+func newTemplateEncoder(w io.Writer, opts options) (encoder, error) {
+	if opts.Template == "" {
+		return nil, errors.New("-format=template requires -template")
+	}
+	tmpl, err := template.New(filepath.Base(opts.Template)).ParseFiles(opts.Template)
+	if err != nil {
+		return nil, err
+	}
+	return &templateEncoder{w: w, tmpl: tmpl, defs: []errorfinder.Def{}}, nil
+}
+
+func (e *templateEncoder) Encode(d errorfinder.Def) error {
+	e.defs = append(e.defs, d)
+	return nil
+}
+
+func (e *templateEncoder) Close() error {
+	return e.tmpl.Execute(e.w, e.defs)
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"encoding/csv"
+	"io"
+
+	"github.com/matttproud/errorfinder"
+)
+
+// usesEncoder writes the uses of the defs found by the usage analyses as CSV
+// records of the error, the kind of use, the enclosing function, the position
+// of the use, and the problem with it, if any.
+type usesEncoder struct {
+	w *csv.Writer
+}
+
+func newUsesEncoder(w io.Writer, opts options) (encoder, error) {
+	e := &usesEncoder{w: csv.NewWriter(w)}
+	if opts.Header {
+		if err := e.w.Write([]string{"error", "kind", "func", "position", "problem"}); err != nil {
+			return nil, err
+		}
+	}
+	return e, nil
+}
+
+func (e *usesEncoder) Encode(d errorfinder.Def) error {
+	for _, use := range d.Uses {
+		if err := e.w.Write([]string{d.ImportPath + "." + d.Name, string(use.Kind), use.Func, use.Position.String(), use.Problem}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *usesEncoder) Close() error {
+	e.w.Flush()
+	return e.w.Error()
+}
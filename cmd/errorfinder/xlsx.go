@@ -0,0 +1,135 @@
+package main
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/matttproud/errorfinder"
+)
+
+// The parts of a minimal SpreadsheetML (Office Open XML) workbook with a
+// single worksheet. Cells use inline strings, so no shared string table is
+// needed.
+const (
+	xlsxContentTypes = xml.Header + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">
+<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>
+<Default Extension="xml" ContentType="application/xml"/>
+<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>
+<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>
+<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>
+</Types>`
+	xlsxRels = xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>
+</Relationships>`
+	xlsxWorkbookRels = xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>
+<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>
+</Relationships>`
+	// Style 1 renders the header row in bold.
+	xlsxStyles = xml.Header + `<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+<fonts count="2"><font/><font><b/></font></fonts>
+<fills count="1"><fill><patternFill patternType="none"/></fill></fills>
+<borders count="1"><border/></borders>
+<cellStyleXfs count="1"><xf/></cellStyleXfs>
+<cellXfs count="2"><xf/><xf fontId="1" applyFont="1"/></cellXfs>
+</styleSheet>`
+	xlsxWorkbook = xml.Header + `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+<sheets><sheet name="errors" sheetId="1" r:id="rId1"/></sheets>
+<definedNames><definedName name="_xlnm._FilterDatabase" localSheetId="0" hidden="1">errors!%v</definedName></definedNames>
+</workbook>`
+)
+
+// xlsxColumn returns the spreadsheet column name for the zero-based index i.
+func xlsxColumn(i int) string {
+	var name []byte
+	for i++; i > 0; i = (i - 1) / 26 {
+		name = append([]byte{byte('A' + (i-1)%26)}, name...)
+	}
+	return string(name)
+}
+
+func xmlEscape(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+// xlsxEncoder emits an Excel workbook with a single sheet holding a header
+// row and one row per def, with filters enabled on every column.
+type xlsxEncoder struct {
+	w    io.Writer
+	cols []errorfinder.Column
+	rows [][]string
+}
+
+func newXLSXEncoder(w io.Writer, opts options) (encoder, error) {
+	cols, err := parseColumns(opts.Columns, opts)
+	if err != nil {
+		return nil, err
+	}
+	header := make([]string, len(cols))
+	for i, col := range cols {
+		header[i] = col.Name
+	}
+	return &xlsxEncoder{w: w, cols: cols, rows: [][]string{header}}, nil
+}
+
+func (e *xlsxEncoder) Encode(d errorfinder.Def) error {
+	row := make([]string, len(e.cols))
+	for i, col := range e.cols {
+		row[i] = col.Value(d)
+	}
+	e.rows = append(e.rows, row)
+	return nil
+}
+
+func (e *xlsxEncoder) sheet() string {
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	b.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">`)
+	b.WriteString(`<sheetViews><sheetView workbookViewId="0"><pane ySplit="1" topLeftCell="A2" activePane="bottomLeft" state="frozen"/></sheetView></sheetViews>`)
+	b.WriteString(`<sheetData>`)
+	for r, row := range e.rows {
+		fmt.Fprintf(&b, `<row r="%d">`, r+1)
+		for c, v := range row {
+			style := ""
+			if r == 0 {
+				style = ` s="1"`
+			}
+			fmt.Fprintf(&b, `<c r="%v%d" t="inlineStr"%v><is><t>%v</t></is></c>`, xlsxColumn(c), r+1, style, xmlEscape(v))
+		}
+		b.WriteString(`</row>`)
+	}
+	b.WriteString(`</sheetData>`)
+	fmt.Fprintf(&b, `<autoFilter ref="%v"/>`, e.filterRange())
+	b.WriteString(`</worksheet>`)
+	return b.String()
+}
+
+func (e *xlsxEncoder) filterRange() string {
+	return fmt.Sprintf("A1:%v%d", xlsxColumn(len(e.cols)-1), len(e.rows))
+}
+
+func (e *xlsxEncoder) Close() error {
+	z := zip.NewWriter(e.w)
+	for _, part := range []struct{ name, body string }{
+		{"[Content_Types].xml", xlsxContentTypes},
+		{"_rels/.rels", xlsxRels},
+		{"xl/workbook.xml", fmt.Sprintf(xlsxWorkbook, fmt.Sprintf("$A$1:$%v$%d", xlsxColumn(len(e.cols)-1), len(e.rows)))},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRels},
+		{"xl/styles.xml", xlsxStyles},
+		{"xl/worksheets/sheet1.xml", e.sheet()},
+	} {
+		f, err := z.Create(part.name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(f, part.body); err != nil {
+			return err
+		}
+	}
+	return z.Close()
+}
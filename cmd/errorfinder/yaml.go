@@ -0,0 +1,279 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/matttproud/errorfinder"
+)
+
+// yamlEncoder emits a YAML document with the schema and build versions, a
+// mapping from import path to the defs declared in that package, and any
+// package errors.
+type yamlEncoder struct {
+	w      *bufio.Writer
+	opts   options
+	report report
+}
+
+func newYAMLEncoder(w io.Writer, opts options) (encoder, error) {
+	return &yamlEncoder{w: bufio.NewWriter(w), opts: opts}, nil
+}
+
+func (e *yamlEncoder) Encode(d errorfinder.Def) error {
+	e.report.add(d)
+	return nil
+}
+
+// quoteYAML quotes s as a YAML double-quoted scalar. JSON strings are valid
+// YAML double-quoted scalars.
+func quoteYAML(s string) string {
+	b, _ := json.Marshal(s)
+	return string(b)
+}
+
+func (e *yamlEncoder) Close() error {
+	fmt.Fprintf(e.w, "schemaVersion: %d\n", e.opts.schema())
+	fmt.Fprintf(e.w, "version: %v\n", quoteYAML(buildVersion()))
+	if len(e.report.Packages) == 0 {
+		fmt.Fprintln(e.w, "packages: {}")
+	} else {
+		fmt.Fprintln(e.w, "packages:")
+	}
+	for _, pkg := range e.report.Packages {
+		fmt.Fprintf(e.w, "  %v:\n", quoteYAML(pkg.ImportPath))
+		for _, d := range pkg.Defs {
+			fmt.Fprintf(e.w, "    - errorType: %v\n", d.ErrorType)
+			fmt.Fprintf(e.w, "      exportType: %v\n", d.ExportType)
+			fmt.Fprintf(e.w, "      packageName: %v\n", quoteYAML(d.PackageName))
+			fmt.Fprintf(e.w, "      name: %v\n", quoteYAML(d.Name))
+			fmt.Fprintf(e.w, "      backingTypeName: %v\n", quoteYAML(d.BackingTypeName))
+			if e.opts.has("position") {
+				fmt.Fprintf(e.w, "      position: %v\n", quoteYAML(d.Position.String()))
+			}
+			if d.Doc != "" && e.opts.has("doc") {
+				fmt.Fprintf(e.w, "      doc: %v\n", quoteYAML(d.Doc))
+			}
+			if d.Message != "" && e.opts.has("message") {
+				fmt.Fprintf(e.w, "      message: %v\n", quoteYAML(d.Message))
+			}
+			if d.Deprecated && e.opts.has("deprecated") {
+				fmt.Fprintln(e.w, "      deprecated: true")
+				fmt.Fprintf(e.w, "      deprecation: %v\n", quoteYAML(d.Deprecation))
+			}
+			if d.Test && e.opts.has("test") {
+				fmt.Fprintln(e.w, "      test: true")
+			}
+			if d.Initializer != "" && e.opts.has("initializer") {
+				fmt.Fprintf(e.w, "      initializer: %v\n", quoteYAML(d.Initializer))
+			}
+			if d.Wrapping && e.opts.has("wrapping") {
+				fmt.Fprintln(e.w, "      wrapping: true")
+			}
+			if d.Const && e.opts.has("const") {
+				fmt.Fprintln(e.w, "      const: true")
+			}
+			if d.Func != "" && e.opts.has("func") {
+				fmt.Fprintf(e.w, "      func: %v\n", quoteYAML(d.Func))
+			}
+			if d.Unwrap != "" && e.opts.has("unwrap") {
+				fmt.Fprintf(e.w, "      unwrap: %v\n", quoteYAML(d.Unwrap))
+			}
+			if len(d.Wraps) > 0 && e.opts.has("wraps") {
+				fmt.Fprintln(e.w, "      wraps:")
+				for _, w := range d.Wraps {
+					fmt.Fprintf(e.w, "        - %v\n", quoteYAML(w))
+				}
+			}
+			if d.Is && e.opts.has("is") {
+				fmt.Fprintln(e.w, "      is: true")
+			}
+			if d.As && e.opts.has("as") {
+				fmt.Fprintln(e.w, "      as: true")
+			}
+			if d.ReceiverKind != "" && e.opts.has("receiverKind") {
+				fmt.Fprintf(e.w, "      receiverKind: %v\n", d.ReceiverKind)
+			}
+			if len(d.Instantiations) > 0 && e.opts.has("instantiations") {
+				fmt.Fprintln(e.w, "      instantiations:")
+				for _, inst := range d.Instantiations {
+					fmt.Fprintf(e.w, "        - %v\n", quoteYAML(inst))
+				}
+			}
+			if len(d.Methods) > 0 && e.opts.has("methods") {
+				fmt.Fprintln(e.w, "      methods:")
+				for _, m := range d.Methods {
+					fmt.Fprintf(e.w, "        - %v\n", quoteYAML(m))
+				}
+			}
+			if d.Generated && e.opts.has("generated") {
+				fmt.Fprintln(e.w, "      generated: true")
+			}
+			if d.Vendored && e.opts.has("vendored") {
+				fmt.Fprintln(e.w, "      vendored: true")
+			}
+			if d.VendorModule != "" && e.opts.has("vendorModule") {
+				fmt.Fprintf(e.w, "      vendorModule: %v\n", quoteYAML(d.VendorModule))
+			}
+			if len(d.Embedding) > 0 && e.opts.has("embedding") {
+				fmt.Fprintln(e.w, "      embedding:")
+				for _, chain := range d.Embedding {
+					fmt.Fprintf(e.w, "        - %v\n", quoteYAML(chain))
+				}
+			}
+			if d.Call != "" && e.opts.has("call") {
+				fmt.Fprintf(e.w, "      call: %v\n", quoteYAML(d.Call))
+			}
+			if len(d.ShadowsStdlib) > 0 && e.opts.has("shadowsStdlib") {
+				fmt.Fprintln(e.w, "      shadowsStdlib:")
+				for _, std := range d.ShadowsStdlib {
+					fmt.Fprintf(e.w, "        - %v\n", quoteYAML(std))
+				}
+			}
+			if len(d.WrapTypes) > 0 && e.opts.has("wrapTypes") {
+				fmt.Fprintln(e.w, "      wrapTypes:")
+				for _, t := range d.WrapTypes {
+					fmt.Fprintf(e.w, "        - %v\n", quoteYAML(t))
+				}
+			}
+			if d.CodeField != "" && e.opts.has("codeField") {
+				fmt.Fprintf(e.w, "      codeField: %v\n", quoteYAML(d.CodeField))
+			}
+			if d.CodeType != "" && e.opts.has("codeType") {
+				fmt.Fprintf(e.w, "      codeType: %v\n", quoteYAML(d.CodeType))
+			}
+			if len(d.Codes) > 0 && e.opts.has("codes") {
+				fmt.Fprintln(e.w, "      codes:")
+				for _, c := range d.Codes {
+					fmt.Fprintf(e.w, "        - %v\n", quoteYAML(c))
+				}
+			}
+			if d.GRPCCode != "" && e.opts.has("grpcCode") {
+				fmt.Fprintf(e.w, "      grpcCode: %v\n", quoteYAML(d.GRPCCode))
+			}
+			if len(d.Chains) > 0 && e.opts.has("chains") {
+				fmt.Fprintln(e.w, "      chains:")
+				for _, chain := range d.Chains {
+					fmt.Fprintf(e.w, "        - %v\n", quoteYAML(chain))
+				}
+			}
+			if len(d.Panics) > 0 && e.opts.has("panics") {
+				fmt.Fprintln(e.w, "      panics:")
+				for _, fn := range d.Panics {
+					fmt.Fprintf(e.w, "        - %v\n", quoteYAML(fn))
+				}
+			}
+			if d.InstanceOf != "" && e.opts.has("instanceOf") {
+				fmt.Fprintf(e.w, "      instanceOf: %v\n", quoteYAML(d.InstanceOf))
+			}
+			if d.Reassigned && e.opts.has("reassigned") {
+				fmt.Fprintln(e.w, "      reassigned: true")
+			}
+			if d.Helper != "" && e.opts.has("helper") {
+				fmt.Fprintf(e.w, "      helper: %v\n", quoteYAML(d.Helper))
+			}
+			if len(d.Uses) > 0 && e.opts.has("uses") {
+				fmt.Fprintln(e.w, "      uses:")
+				for _, use := range d.Uses {
+					fmt.Fprintf(e.w, "        - kind: %v\n", use.Kind)
+					if use.Func != "" {
+						fmt.Fprintf(e.w, "          func: %v\n", quoteYAML(use.Func))
+					}
+					fmt.Fprintf(e.w, "          position: %v\n", quoteYAML(use.Position.String()))
+					if use.Problem != "" {
+						fmt.Fprintf(e.w, "          problem: %v\n", quoteYAML(use.Problem))
+					}
+				}
+			}
+			if d.Dead && e.opts.has("dead") {
+				fmt.Fprintln(e.w, "      dead: true")
+			}
+			if d.Reach != "" && e.opts.has("reach") {
+				fmt.Fprintf(e.w, "      reach: %v\n", d.Reach)
+			}
+			if len(d.EntryPoints) > 0 && e.opts.has("entryPoints") {
+				fmt.Fprintln(e.w, "      entryPoints:")
+				for _, fn := range d.EntryPoints {
+					fmt.Fprintf(e.w, "        - %v\n", quoteYAML(fn))
+				}
+			}
+			if d.RefCount > 0 && e.opts.has("refCount") {
+				fmt.Fprintf(e.w, "      refCount: %d\n", d.RefCount)
+			}
+			if len(d.Consumers) > 0 && e.opts.has("consumers") {
+				fmt.Fprintln(e.w, "      consumers:")
+				for _, path := range d.Consumers {
+					fmt.Fprintf(e.w, "        - %v\n", quoteYAML(path))
+				}
+			}
+			if len(d.ReturnedBy) > 0 && e.opts.has("returnedBy") {
+				fmt.Fprintln(e.w, "      returnedBy:")
+				for _, fn := range d.ReturnedBy {
+					fmt.Fprintf(e.w, "        - %v\n", quoteYAML(fn))
+				}
+			}
+			if len(d.Undocumented) > 0 && e.opts.has("undocumented") {
+				fmt.Fprintln(e.w, "      undocumented:")
+				for _, fn := range d.Undocumented {
+					fmt.Fprintf(e.w, "        - %v\n", quoteYAML(fn))
+				}
+			}
+			if d.ReturnedBare > 0 && e.opts.has("returnedBare") {
+				fmt.Fprintf(e.w, "      returnedBare: %d\n", d.ReturnedBare)
+			}
+			if d.Wrapped > 0 && e.opts.has("wrapped") {
+				fmt.Fprintf(e.w, "      wrapped: %d\n", d.Wrapped)
+			}
+			if d.Untested && e.opts.has("untested") {
+				fmt.Fprintln(e.w, "      untested: true")
+			}
+			if len(d.InterfaceMethods) > 0 && e.opts.has("interfaceMethods") {
+				fmt.Fprintln(e.w, "      interfaceMethods:")
+				for _, m := range d.InterfaceMethods {
+					fmt.Fprintf(e.w, "        - %v\n", quoteYAML(m))
+				}
+			}
+			if len(d.Handlers) > 0 && e.opts.has("handlers") {
+				fmt.Fprintln(e.w, "      handlers:")
+				for _, fn := range d.Handlers {
+					fmt.Fprintf(e.w, "        - %v\n", quoteYAML(fn))
+				}
+			}
+			if len(d.Satisfies) > 0 && e.opts.has("satisfies") {
+				fmt.Fprintln(e.w, "      satisfies:")
+				for _, iface := range d.Satisfies {
+					fmt.Fprintf(e.w, "        - %v\n", quoteYAML(iface))
+				}
+			}
+			if d.Retryable != "" && e.opts.has("retryable") {
+				fmt.Fprintf(e.w, "      retryable: %v\n", d.Retryable)
+			}
+			if d.Stack && e.opts.has("stack") {
+				fmt.Fprintln(e.w, "      stack: true")
+			}
+			if d.LateInit && e.opts.has("lateInit") {
+				fmt.Fprintln(e.w, "      lateInit: true")
+			}
+			if len(d.Fields) > 0 && e.opts.has("fields") {
+				fmt.Fprintln(e.w, "      fields:")
+				for _, f := range d.Fields {
+					fmt.Fprintf(e.w, "        - %v\n", quoteYAML(f))
+				}
+			}
+		}
+	}
+	if len(e.opts.PackageErrors) > 0 && e.opts.has("packageErrors") {
+		fmt.Fprintln(e.w, "packageErrors:")
+		for _, pe := range e.opts.PackageErrors {
+			fmt.Fprintf(e.w, "  - package: %v\n", quoteYAML(pe.Package))
+			if pe.Position != "" {
+				fmt.Fprintf(e.w, "    position: %v\n", quoteYAML(pe.Position))
+			}
+			fmt.Fprintf(e.w, "    message: %v\n", quoteYAML(pe.Message))
+			fmt.Fprintf(e.w, "    kind: %v\n", pe.Kind)
+		}
+	}
+	return e.w.Flush()
+}
@@ -0,0 +1,12 @@
+// Binary errorfindervet runs the errorfinder analyzer, which with its -report
+// flag reports the error sentinels and structured error types of packages. It
+// can be run standalone or with go vet -vettool.
+package main
+
+import (
+	"golang.org/x/tools/go/analysis/singlechecker"
+
+	"github.com/matttproud/errorfinder"
+)
+
+func main() { singlechecker.Main(errorfinder.Analyzer) }
@@ -0,0 +1,73 @@
+package errorfinder
+
+import (
+	"go/ast"
+	"go/types"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// undocumentedReturns reports for each error, named by package path and name,
+// the exported functions and methods of pkgs whose return statements return
+// it, directly or wrapped, but whose doc comments do not mention it by name,
+// by their full names in sorted order.
+func undocumentedReturns(pkgs []*packages.Package) map[string][]string {
+	gaps := make(map[string][]string)
+	for _, pkg := range pkgs {
+		if pkg.TypesInfo == nil {
+			continue
+		}
+		docs := make(map[*types.Func]string)
+		for _, file := range pkg.Syntax {
+			for _, decl := range file.Decls {
+				if fd, ok := decl.(*ast.FuncDecl); ok {
+					if fn, ok := pkg.TypesInfo.Defs[fd.Name].(*types.Func); ok {
+						docs[fn] = fd.Doc.Text()
+					}
+				}
+			}
+		}
+		returns, _ := funcReturns(pkg)
+		for fn, names := range returns {
+			if !isExportedFunc(fn) {
+				continue
+			}
+			for _, name := range names {
+				if !mentions(docs[fn], name[strings.LastIndex(name, ".")+1:]) {
+					gaps[name] = append(gaps[name], fn.FullName())
+				}
+			}
+		}
+	}
+	for name, fns := range gaps {
+		slices.Sort(fns)
+		gaps[name] = slices.Compact(fns)
+	}
+	return gaps
+}
+
+// mentions reports whether doc contains name as a whole word, as in
+// "returns ErrNotFound", "[ErrNotFound]", or "p.ErrNotFound".
+func mentions(doc, name string) bool {
+	for i := strings.Index(doc, name); i >= 0; {
+		before, _ := utf8.DecodeLastRuneInString(doc[:i])
+		after, _ := utf8.DecodeRuneInString(doc[i+len(name):])
+		if !isIdentRune(before) && !isIdentRune(after) {
+			return true
+		}
+		next := strings.Index(doc[i+1:], name)
+		if next < 0 {
+			break
+		}
+		i += 1 + next
+	}
+	return false
+}
+
+func isIdentRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
@@ -0,0 +1,927 @@
+// Package errorfinder extracts error sentinel and structured error value types,
+// along with the functions that construct errors, from Go source code.
+package errorfinder
+
+import (
+	"cmp"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"go/token"
+	"go/types"
+	"iter"
+	"log/slog"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/tools/go/packages"
+)
+
+var errorInterface = types.Universe.Lookup("error").Type().Underlying().(*types.Interface)
+
+func isErrorType(t types.Type) bool {
+	return types.Implements(t, errorInterface)
+}
+
+// An ErrorType classifies a def. Besides the built-in types, others can be
+// added with RegisterErrorType.
+type ErrorType int
+
+const (
+	ErrorTypeUnknown ErrorType = iota
+	ErrorTypeSentinel
+	ErrorTypeStructured
+	ErrorTypeConstructor // A function returning a new error of a concrete type.
+	ErrorTypeAlias       // A type alias of an error type.
+	ErrorTypeInterface   // An interface type that embeds error.
+)
+
+// MarshalText encodes the error type as its name, such as "ErrorTypeSentinel".
+func (t ErrorType) MarshalText() ([]byte, error) { return []byte(t.String()), nil }
+
+//go:generate stringer -type=ExportType
+type ExportType int
+
+const (
+	ExportTypeUnknown ExportType = iota
+	ExportTypeExported
+	ExportTypeUnexported
+)
+
+// MarshalText encodes the export type as its name, such as "ExportTypeExported".
+func (t ExportType) MarshalText() ([]byte, error) { return []byte(t.String()), nil }
+
+// A Def is an error sentinel or structured error type declared at the top
+// level of a package.
+type Def struct {
+	ErrorType       `json:"errorType"`
+	ExportType      `json:"exportType"`
+	ImportPath      string         `json:"importPath"`
+	PackageName     string         `json:"packageName"`
+	Name            string         `json:"name"`
+	BackingTypeName string         `json:"backingTypeName"` // Type literals read as in source, such as "struct{error}".
+	Position        token.Position `json:"-"`               // Encoded as "file:line:column".
+	Doc             string         `json:"doc"`
+
+	// Sentinels: the message they were created with or, for constants of a
+	// string type, their value. Structured errors: the constant message or
+	// fmt.Sprintf format their Error method returns.
+	Message string `json:"message"`
+
+	Deprecated  bool   `json:"deprecated"`
+	Deprecation string `json:"deprecation"` // The reason given in a "Deprecated:" paragraph.
+	Test        bool   `json:"test"`        // Declared in a _test.go file.
+	Generated   bool   `json:"generated"`   // Declared in a file marked as generated.
+
+	// Whether the def is declared in a vendor directory and, if known, the
+	// module vendored there, such as "example.com/dep@v1.2.0".
+	Vendored     bool   `json:"vendored"`
+	VendorModule string `json:"vendorModule"`
+
+	// Sentinels: the function that created the value, such as errors.New,
+	// fmt.Errorf, or a constructor named by its full name, such as
+	// "example.com/myerrors.New", the call as written, the Must-style helper
+	// the call applies to the function's result, if any, such as
+	// "example.com/p.must" for must(errors.New("x")), and whether the
+	// fmt.Errorf format string has a %w verb.
+	Initializer string `json:"initializer"`
+	Call        string `json:"call"`
+	Helper      string `json:"helper"`
+	Wrapping    bool   `json:"wrapping"`
+	Const       bool   `json:"const"` // Sentinels: declared as a constant.
+
+	// Sentinels: the standard library's exported sentinels with the same
+	// name, such as "os.ErrClosed" for a package's own ErrClosed.
+	ShadowsStdlib []string `json:"shadowsStdlib,omitempty"`
+
+	// Sentinels: the static types of the arguments of the fmt.Errorf %w
+	// verbs, in order, such as "*io/fs.PathError".
+	WrapTypes []string `json:"wrapTypes,omitempty"`
+
+	// Sentinels: the gRPC code of those created with status.Error or
+	// status.Errorf, such as "NotFound".
+	GRPCCode string `json:"grpcCode"`
+
+	Func string `json:"func"` // Declared in the body of this function, such as "(*T).M".
+
+	// Structured errors: the result type of the Unwrap method, either "error"
+	// or "[]error", or empty if there is none, and whether Is(error) bool and
+	// As(any) bool methods customize errors.Is and errors.As.
+	Unwrap string `json:"unwrap"`
+	Is     bool   `json:"is"`
+	As     bool   `json:"as"`
+
+	// Structured errors: "value" if the type implements error, or "pointer"
+	// if only a pointer to it does, as errors.As targets must then be. Empty
+	// for interface types.
+	ReceiverKind string `json:"receiverKind"`
+
+	// Generic structured errors: the instantiations of the type observed in
+	// the scanned packages, such as "p.WrapError[int]", when
+	// Config.Instantiations is set.
+	Instantiations []string `json:"instantiations,omitempty"`
+
+	// Structured errors: the errors embedded directly or through other
+	// embedded structs, each as the chain of embedded types leading to it,
+	// such as "p.Base > p.CodeError".
+	Embedding []string `json:"embedding,omitempty"`
+
+	// The chains of errors that the def wraps, directly or through what those
+	// errors wrap in turn, each starting with the def, such as
+	// "p.ConfigError > p.ParseError > p.ErrSyntax". A chain that returns to an
+	// error already in it ends with that error. Only a Finder reports them.
+	Chains []string `json:"chains,omitempty"`
+
+	// The functions of the scanned packages that panic with the def's value
+	// or a value of its type, such as "(*example.com/p.Parser).Parse". See
+	// Config.Panics.
+	Panics []string `json:"panics,omitempty"`
+
+	// The uses of the def that the usage analyses selected by Config.Uses
+	// found in the scanned packages, in discovery order.
+	Uses []Use `json:"uses,omitempty"`
+
+	// Exported sentinels and structured errors: whether the scanned packages
+	// never return, wrap, or compare the error, making it a candidate for
+	// removal. See Config.Dead.
+	Dead bool `json:"dead"`
+
+	// Sentinels and structured errors: whether the error can reach callers
+	// outside its package, "external", or not, "internal". Exported errors
+	// are external, as are unexported ones that the package's exported API
+	// returns or that its exported sentinels wrap. See Config.Reach.
+	Reach string `json:"reach"`
+
+	// The exported functions and methods of the scanned packages to which the
+	// error may propagate, such as "(*example.com/p.Client).Get". See
+	// Config.Propagation.
+	EntryPoints []string `json:"entryPoints,omitempty"`
+
+	// The exported functions and methods of the scanned packages whose
+	// return statements return the error, directly or wrapped. See
+	// Config.Returns.
+	ReturnedBy []string `json:"returnedBy,omitempty"`
+
+	// The exported functions and methods of the scanned packages that return
+	// the error but whose doc comments do not mention it by name. See
+	// Config.DocCoverage.
+	Undocumented []string `json:"undocumented,omitempty"`
+
+	// The number of identifiers in the scanned packages referring to the
+	// def. See Config.RefCount.
+	RefCount int `json:"refCount"`
+
+	// Exported errors: the import paths of the scanned packages besides the
+	// def's own that refer to it. See Config.Consumers.
+	Consumers []string `json:"consumers,omitempty"`
+
+	// How many return statements of the scanned packages return the error
+	// bare and how many calls in their functions wrap it, with fmt.Errorf's
+	// %w verb or errors.Join. See Config.ReturnStats.
+	ReturnedBare int `json:"returnedBare"`
+	Wrapped      int `json:"wrapped"`
+
+	// Exported sentinels and structured errors: whether no _test.go file of
+	// the scanned packages refers to the error. See Config.Untested.
+	Untested bool `json:"untested"`
+
+	// The methods of the interfaces declared in the scanned packages that may
+	// return the error, as implemented by the types declared there, such as
+	// "(example.com/p.Store).Get". See Config.InterfaceMethods.
+	InterfaceMethods []string `json:"interfaceMethods,omitempty"`
+
+	// The HTTP handlers of the scanned packages that may write the error to
+	// their responses, such as "(example.com/p.Server).ServeHTTP". See
+	// Config.Handlers.
+	Handlers []string `json:"handlers,omitempty"`
+
+	// Structured errors: the well-known interfaces that the type or its
+	// pointer type satisfies, such as "net.Error" or
+	// "interface{ Timeout() bool }". See Config.Satisfies.
+	Satisfies []string `json:"satisfies,omitempty"`
+
+	// Sentinels and structured errors: whether the error is worth retrying,
+	// "yes", "no", or "unknown", judged by its doc comment, the constant
+	// results of its Temporary and Timeout methods, whether it wraps
+	// context.DeadlineExceeded or context.Canceled, and the words of its
+	// name, such as "Unavailable" or "Invalid". It is a hint, not a policy.
+	Retryable string `json:"retryable"`
+
+	// Whether the error carries a stack trace, as sentinels created by
+	// stack-capturing constructors such as github.com/pkg/errors.New do and
+	// structured errors do that have a StackTrace method or hold a
+	// runtime.Frames or the []uintptr that runtime.Callers fills. Capturing
+	// one costs allocations, and traces may expose details of the program.
+	Stack bool `json:"stack"`
+
+	// Sentinels: whether the scanned packages assign to the variable or take
+	// its address after its initialization, so that errors.Is may stop
+	// matching the errors it was compared against. Only a Finder reports it.
+	Reassigned bool `json:"reassigned"`
+
+	// Sentinels: whether the variable is declared without a value and
+	// initialized by an assignment in an init function, from which its
+	// initializer, call, and message are then taken.
+	LateInit bool `json:"lateInit"`
+
+	// Structured errors: the exported fields of the struct, each as its name
+	// and type, such as "Op string", in declaration order.
+	Fields []string `json:"fields,omitempty"`
+
+	// Structured errors: the field holding the error's code, its type and, if
+	// the type is a named one, the constants of that type declared in its
+	// package, such as "CodeNotFound=1". See Config.CodeFields.
+	CodeField string   `json:"codeField"`
+	CodeType  string   `json:"codeType"`
+	Codes     []string `json:"codes,omitempty"`
+
+	// Interfaces: the methods required besides Error, such as
+	// "Temporary() bool", in sorted order.
+	Methods []string `json:"methods,omitempty"`
+
+	// Relationships to other types, named by package path and type name.
+	InstanceOf string   `json:"instanceOf,omitempty"` // Sentinels and constructors: the named type of the value, if any, such as a structured def.
+	Embeds     []string `json:"-"`                    // Structured errors: embedded error types.
+	Wraps      []string `json:"wraps,omitempty"`      // Structured errors: what Unwrap returns; sentinels: what %w wraps or errors.Join joins.
+	AliasOf    string   `json:"-"`                    // Aliases: the aliased type.
+}
+
+func (d Def) MarshalJSON() ([]byte, error) {
+	type def Def // Without methods, lest json.Marshal recurse.
+	return json.Marshal(struct {
+		def
+		Position string `json:"position"`
+	}{def(d), d.Position.String()})
+}
+
+// A Column is a field of a def in tabular formats.
+type Column struct {
+	Name  string
+	Value func(Def) string
+}
+
+const escapes = "" // Convenient code formatting with Markdown.
+
+// Columns are the fields of a def written by WriteCSV in order.
+var Columns = []Column{
+	{"kind", func(d Def) string { return d.ErrorType.String() }},
+	{"export", func(d Def) string { return d.ExportType.String() }},
+	{"path", func(d Def) string { return escapes + d.ImportPath + escapes }},
+	{"package", func(d Def) string { return d.PackageName }},
+	{"name", func(d Def) string { return escapes + d.Name + escapes }},
+	{"type", func(d Def) string { return d.BackingTypeName }},
+	{"pos", func(d Def) string { return d.Position.String() }},
+	{"doc", func(d Def) string { return d.Doc }},
+	{"message", func(d Def) string { return d.Message }},
+	{"deprecated", func(d Def) string { return strconv.FormatBool(d.Deprecated) }},
+	{"deprecation", func(d Def) string { return d.Deprecation }},
+	{"test", func(d Def) string { return strconv.FormatBool(d.Test) }},
+	{"initializer", func(d Def) string { return d.Initializer }},
+	{"wrapping", func(d Def) string { return strconv.FormatBool(d.Wrapping) }},
+	{"const", func(d Def) string { return strconv.FormatBool(d.Const) }},
+	{"func", func(d Def) string { return d.Func }},
+	{"unwrap", func(d Def) string { return d.Unwrap }},
+	{"wraps", func(d Def) string { return strings.Join(d.Wraps, " ") }},
+	{"is", func(d Def) string { return strconv.FormatBool(d.Is) }},
+	{"as", func(d Def) string { return strconv.FormatBool(d.As) }},
+	{"receiver", func(d Def) string { return d.ReceiverKind }},
+	{"instantiations", func(d Def) string { return strings.Join(d.Instantiations, "; ") }},
+	{"methods", func(d Def) string { return strings.Join(d.Methods, "; ") }},
+	{"generated", func(d Def) string { return strconv.FormatBool(d.Generated) }},
+	{"vendored", func(d Def) string { return strconv.FormatBool(d.Vendored) }},
+	{"module", func(d Def) string { return d.VendorModule }},
+	{"embedding", func(d Def) string { return strings.Join(d.Embedding, "; ") }},
+	{"call", func(d Def) string { return d.Call }},
+	{"shadows", func(d Def) string { return strings.Join(d.ShadowsStdlib, " ") }},
+	{"wraptypes", func(d Def) string { return strings.Join(d.WrapTypes, " ") }},
+	{"codefield", func(d Def) string { return d.CodeField }},
+	{"codetype", func(d Def) string { return d.CodeType }},
+	{"codes", func(d Def) string { return strings.Join(d.Codes, " ") }},
+	{"grpc", func(d Def) string { return d.GRPCCode }},
+	{"chains", func(d Def) string { return strings.Join(d.Chains, "; ") }},
+	{"panics", func(d Def) string { return strings.Join(d.Panics, " ") }},
+	{"instance", func(d Def) string { return d.InstanceOf }},
+	{"reassigned", func(d Def) string { return strconv.FormatBool(d.Reassigned) }},
+	{"helper", func(d Def) string { return d.Helper }},
+	{"uses", func(d Def) string {
+		uses := make([]string, len(d.Uses))
+		for i, u := range d.Uses {
+			uses[i] = u.String()
+		}
+		return strings.Join(uses, "; ")
+	}},
+	{"dead", func(d Def) string { return strconv.FormatBool(d.Dead) }},
+	{"reach", func(d Def) string { return d.Reach }},
+	{"entrypoints", func(d Def) string { return strings.Join(d.EntryPoints, " ") }},
+	{"refcount", func(d Def) string { return strconv.Itoa(d.RefCount) }},
+	{"consumers", func(d Def) string { return strings.Join(d.Consumers, " ") }},
+	{"returnedby", func(d Def) string { return strings.Join(d.ReturnedBy, " ") }},
+	{"undocumented", func(d Def) string { return strings.Join(d.Undocumented, " ") }},
+	{"bare", func(d Def) string { return strconv.Itoa(d.ReturnedBare) }},
+	{"wrapped", func(d Def) string { return strconv.Itoa(d.Wrapped) }},
+	{"untested", func(d Def) string { return strconv.FormatBool(d.Untested) }},
+	{"interfaces", func(d Def) string { return strings.Join(d.InterfaceMethods, " ") }},
+	{"handlers", func(d Def) string { return strings.Join(d.Handlers, " ") }},
+	{"satisfies", func(d Def) string { return strings.Join(d.Satisfies, "; ") }},
+	{"retryable", func(d Def) string { return d.Retryable }},
+	{"stack", func(d Def) string { return strconv.FormatBool(d.Stack) }},
+	{"lateinit", func(d Def) string { return strconv.FormatBool(d.LateInit) }},
+	{"fields", func(d Def) string { return strings.Join(d.Fields, "; ") }},
+}
+
+// WriteCSV writes d to w as a record of Columns.
+func (d Def) WriteCSV(w *csv.Writer) error {
+	record := make([]string, len(Columns))
+	for i, col := range Columns {
+		record[i] = col.Value(d)
+	}
+	return w.Write(record)
+}
+
+// Compare orders defs by kind, export, import path, package, name, backing
+// type, and finally position. It is suitable for use with slices.SortFunc.
+func Compare(a, b Def) int {
+	switch v := cmp.Compare(a.ErrorType, b.ErrorType); v {
+	case -1, 1:
+		return v
+	}
+	switch v := cmp.Compare(a.ExportType, b.ExportType); v {
+	case -1, 1:
+		return v
+	}
+	switch v := cmp.Compare(a.ImportPath, b.ImportPath); v {
+	case -1, 1:
+		return v
+	}
+	switch v := cmp.Compare(a.PackageName, b.PackageName); v {
+	case -1, 1:
+		return v
+	}
+	switch v := cmp.Compare(a.Name, b.Name); v {
+	case -1, 1:
+		return v
+	}
+	switch v := cmp.Compare(a.BackingTypeName, b.BackingTypeName); v {
+	case -1, 1:
+		return v
+	}
+	switch v := cmp.Compare(a.Position.Filename, b.Position.Filename); v {
+	case -1, 1:
+		return v
+	}
+	return cmp.Compare(a.Position.Offset, b.Position.Offset)
+}
+
+// Config controls how packages are loaded and which of their defs are
+// reported. The errorfinder command exposes each field as a flag.
+type Config struct {
+	Dir   string   // Directory in which to run the build tool; empty means the current one.
+	Env   []string // Environment of the build tool; nil means the current one.
+	Tags  []string // Build tags to satisfy when selecting files.
+	Tests bool     // Also scan the test files and external test packages.
+
+	// Overlay maps the absolute paths of files to contents that replace
+	// those on disk, or that supply files missing from it.
+	Overlay map[string][]byte
+
+	// Depth is how many import edges away from the matched packages
+	// dependencies are scanned too. Zero scans only the matched packages,
+	// and a negative depth scans all dependencies.
+	Depth int
+	// Timeout bounds the duration of a scan; zero means no limit.
+	Timeout time.Duration
+
+	Kinds        []ErrorType // If non-empty, report only defs of these kinds.
+	ExportedOnly bool        // Report only exported defs.
+	Local        bool        // Also report sentinels and error types declared in function bodies.
+
+	// ExcludeGenerated omits the defs declared in files with the standard
+	// "Code generated ... DO NOT EDIT." marker.
+	ExcludeGenerated bool
+
+	// TestsOnly reports only the defs declared in _test.go files, which are
+	// scanned as if Tests were set, to tell test-only errors apart from
+	// production ones.
+	TestsOnly bool
+
+	// CodeFields names the fields of structured errors that hold their codes;
+	// nil means Code, ErrCode, and Status.
+	CodeFields []string
+
+	// Stdlib, if set, records in Result.Stdlib the standard library's
+	// sentinels and error types that the scanned packages return or compare
+	// errors against, the external error contracts they depend on. Only Find
+	// reports them.
+	Stdlib bool
+
+	// Loggers names the functions and methods that the UseLogs analysis
+	// treats as loggers by their full names, such as "log.Printf" or
+	// "(*log/slog.Logger).Error"; nil means the printing functions and
+	// methods of the log and log/slog packages.
+	Loggers []string
+
+	// Instantiations, if set, records in each generic error type's def the
+	// instantiations of the type observed in the scanned packages.
+	Instantiations bool
+
+	// Panics, if set, records in each def the functions of the scanned
+	// packages that panic with it.
+	Panics bool
+
+	// Uses selects the usage analyses, such as UseReturns, whose findings
+	// each def records in its Uses.
+	Uses []UseKind
+
+	// Dead, if set, records in each exported sentinel's and structured
+	// error's def whether the scanned packages never return, wrap, or
+	// compare it.
+	Dead bool
+
+	// Reach, if set, records in each sentinel's and structured error's def
+	// whether the error can reach callers outside its package, judged from
+	// the signatures and, on a best-effort basis, the bodies of the exported
+	// functions.
+	Reach bool
+
+	// Propagation, if set, records in each def the exported functions and
+	// methods of the scanned packages to which the error may propagate,
+	// judged from their call graph as found by class hierarchy analysis.
+	Propagation bool
+
+	// RefCount, if set, records in each def the number of identifiers in the
+	// scanned packages referring to it.
+	RefCount bool
+
+	// Consumers, if set, records in each exported def the other scanned
+	// packages that refer to it.
+	Consumers bool
+
+	// Returns, if set, records in each def the exported functions and
+	// methods of the scanned packages whose return statements return it.
+	// Unlike Propagation, only the function's own body is considered.
+	Returns bool
+
+	// DocCoverage, if set, records in each def the exported functions and
+	// methods of the scanned packages that return it, as for Returns, but
+	// whose doc comments do not mention it by name.
+	DocCoverage bool
+
+	// ReturnStats, if set, counts in each def how often the functions of the
+	// scanned packages return the error bare and how often they wrap it,
+	// measuring how consistently callers are given context.
+	ReturnStats bool
+
+	// Untested, if set, flags in each def of an exported sentinel or
+	// structured error declared outside test files whether no _test.go file
+	// refers to it. Packages are scanned as if Tests were set.
+	Untested bool
+
+	// InterfaceMethods, if set, records in each def the methods of the
+	// interfaces declared in the scanned packages that may return it, judged
+	// from the methods implementing them in the types declared there.
+	InterfaceMethods bool
+
+	// Handlers, if set, records in each def the HTTP handlers of the scanned
+	// packages that may write it to their responses, judged from the errors
+	// passed to http.Error and other functions writing responses and those
+	// matched to choose status codes.
+	Handlers bool
+
+	// Satisfies, if set, records in each structured error's def which of the
+	// KnownInterfaces the type or its pointer type satisfies.
+	Satisfies bool
+
+	// KnownInterfaces names the well-known interfaces for Satisfies, each by
+	// package path and name, such as "net.Error", or as an interface type
+	// literal of predeclared types, such as "interface{ Timeout() bool }";
+	// nil means net.Error and the interfaces of the Temporary, Timeout, and
+	// Unwrap methods. Interfaces declared in packages that were not loaded
+	// are ignored.
+	KnownInterfaces []string
+
+	// Logger, if non-nil, receives diagnostics about scans: the packages
+	// loaded and scanned, how long that took, and the ignored files and
+	// errors encountered along the way.
+	Logger *slog.Logger
+}
+
+var defaultCodeFields = []string{"Code", "ErrCode", "Status"}
+
+var defaultLoggers = []string{
+	"log.Print", "log.Printf", "log.Println",
+	"(*log.Logger).Print", "(*log.Logger).Printf", "(*log.Logger).Println",
+	"log/slog.Debug", "log/slog.Info", "log/slog.Warn", "log/slog.Error",
+	"log/slog.DebugContext", "log/slog.InfoContext", "log/slog.WarnContext", "log/slog.ErrorContext",
+	"log/slog.Log",
+	"(*log/slog.Logger).Debug", "(*log/slog.Logger).Info", "(*log/slog.Logger).Warn", "(*log/slog.Logger).Error",
+	"(*log/slog.Logger).DebugContext", "(*log/slog.Logger).InfoContext", "(*log/slog.Logger).WarnContext", "(*log/slog.Logger).ErrorContext",
+	"(*log/slog.Logger).Log",
+}
+
+func (c *Config) loggers() []string {
+	if c.Loggers == nil {
+		return defaultLoggers
+	}
+	return c.Loggers
+}
+
+var defaultKnownInterfaces = []string{
+	"net.Error",
+	"interface{ Temporary() bool }",
+	"interface{ Timeout() bool }",
+	"interface{ Unwrap() error }",
+}
+
+func (c *Config) knownInterfaces() []string {
+	if c.KnownInterfaces == nil {
+		return defaultKnownInterfaces
+	}
+	return c.KnownInterfaces
+}
+
+func (c *Config) codeFields() []string {
+	if c.CodeFields == nil {
+		return defaultCodeFields
+	}
+	return c.CodeFields
+}
+
+// match reports whether the config's filters select d.
+func (c *Config) match(d Def) bool {
+	if len(c.Kinds) > 0 && !slices.Contains(c.Kinds, d.ErrorType) {
+		return false
+	}
+	if c.ExcludeGenerated && d.Generated {
+		return false
+	}
+	if c.TestsOnly && !d.Test {
+		return false
+	}
+	return !c.ExportedOnly || d.ExportType == ExportTypeExported
+}
+
+// LoadMode is the information about packages that extraction requires.
+const LoadMode = packages.NeedName | packages.NeedFiles | packages.NeedCompiledGoFiles | packages.NeedModule | packages.NeedImports | packages.NeedDeps | packages.NeedTypes | packages.NeedSyntax | packages.NeedTypesInfo
+
+// A Finder extracts defs from the packages named by Patterns, which are
+// import paths or directories as understood by go/packages.
+type Finder struct {
+	Config   Config
+	Patterns []string
+
+	// OnScan, if non-nil, is called with the packages whose defs are about
+	// to be extracted in the order OnPackage sees them.
+	OnScan func([]*packages.Package)
+	// OnPackage, if non-nil, is called with each loaded package before its
+	// defs are extracted.
+	OnPackage func(*packages.Package)
+	// OnDef, if non-nil, is called with each def as it is found. Returning
+	// false stops the scan after that def.
+	OnDef func(Def) bool
+	// Classify, if non-nil, is called with each def as it is found and
+	// returns its error type, which may be one added with
+	// RegisterErrorType. The config's filters apply to the returned type.
+	Classify func(Def) ErrorType
+}
+
+// Load loads the packages named by the finder's patterns. Failures are
+// reported as a *LoadError.
+func (f *Finder) Load(ctx context.Context) ([]*packages.Package, error) {
+	cfg := &packages.Config{
+		Context: ctx,
+		Mode:    LoadMode,
+		Dir:     f.Config.Dir,
+		Env:     f.Config.Env,
+		Tests:   f.Config.Tests || f.Config.TestsOnly || f.Config.Untested,
+		Overlay: f.Config.Overlay,
+	}
+	if len(f.Config.Tags) > 0 {
+		cfg.BuildFlags = []string{"-tags=" + strings.Join(f.Config.Tags, ",")}
+	}
+	log := f.Config.logger()
+	start := time.Now()
+	pkgs, err := packages.Load(cfg, f.Patterns...)
+	if err != nil {
+		// go/packages does not reliably wrap the error of a done context.
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			err = ctxErr
+		}
+		return nil, &LoadError{Patterns: f.Patterns, Err: err}
+	}
+	// Patterns naming nothing loadable, such as a missing directory, yield
+	// packages without files that hold the reason.
+	if i := slices.IndexFunc(pkgs, hasFiles); i < 0 {
+		err := ErrNoPackages
+		if len(pkgs) > 0 && len(pkgs[0].Errors) > 0 {
+			err = fmt.Errorf("%w: %v", ErrNoPackages, pkgs[0].Errors[0])
+		}
+		return nil, &LoadError{Patterns: f.Patterns, Err: err}
+	}
+	log.InfoContext(ctx, "loaded packages", "patterns", f.Patterns, "packages", len(pkgs), "duration", time.Since(start))
+	if log.Enabled(ctx, slog.LevelInfo) {
+		packages.Visit(pkgs, nil, func(pkg *packages.Package) {
+			for _, e := range pkg.Errors {
+				log.InfoContext(ctx, "package error", "package", pkg.PkgPath, "error", e)
+			}
+		})
+	}
+	if log.Enabled(ctx, slog.LevelDebug) {
+		for _, pkg := range f.scanned(pkgs) {
+			if len(pkg.IgnoredFiles) > 0 {
+				log.DebugContext(ctx, "ignored files", "package", pkg.PkgPath, "files", pkg.IgnoredFiles)
+			}
+		}
+	}
+	return pkgs, nil
+}
+
+// brokenPackage reports whether pkg itself, rather than only its
+// dependencies, contains errors or was not type checked, so that its defs
+// cannot be extracted reliably.
+func brokenPackage(pkg *packages.Package) bool {
+	return pkg.TypesInfo == nil || len(pkg.Errors) > 0
+}
+
+func hasFiles(pkg *packages.Package) bool {
+	return len(pkg.GoFiles) > 0 || len(pkg.CompiledGoFiles) > 0
+}
+
+// A Result is the outcome of a scan.
+type Result struct {
+	Defs []Def // Sorted by Compare.
+
+	// PackageErrors are the errors encountered loading, parsing, and type
+	// checking the packages and their dependencies.
+	PackageErrors []packages.Error
+	// Skipped holds the import paths of the packages whose defs were not
+	// extracted because they contain errors. Errors in their dependencies
+	// alone do not skip them, although their defs may then be incomplete.
+	Skipped []string
+
+	// Stdlib maps the sentinels and error types of the standard library that
+	// the scanned packages return or compare errors against, named by
+	// package path and name, such as "io.EOF", to those uses. See
+	// Config.Stdlib.
+	Stdlib map[string][]Use
+}
+
+// Find loads the finder's packages and reports their defs. Packages that
+// contain errors are skipped rather than failing the scan, so the result can
+// be partial.
+func (f *Finder) Find(ctx context.Context) (*Result, error) {
+	ctx, cancel := f.withTimeout(ctx)
+	defer cancel()
+	pkgs, err := f.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	res := new(Result)
+	packages.Visit(pkgs, nil, func(pkg *packages.Package) {
+		res.PackageErrors = append(res.PackageErrors, pkg.Errors...)
+	})
+	for _, pkg := range f.scanned(pkgs) {
+		if brokenPackage(pkg) {
+			res.Skipped = append(res.Skipped, pkg.PkgPath)
+		}
+	}
+	for def, err := range f.Extract(ctx, pkgs) {
+		if err != nil {
+			return nil, err
+		}
+		res.Defs = append(res.Defs, def)
+	}
+	slices.SortFunc(res.Defs, Compare)
+	if f.Config.Stdlib {
+		std, err := f.stdPackages(ctx)
+		if err != nil {
+			return nil, err
+		}
+		res.Stdlib = stdlibUses(f.scanned(pkgs), std)
+	}
+	return res, nil
+}
+
+// All loads the finder's packages and yields their defs in discovery order as
+// they are extracted, without retaining them. If the packages cannot be
+// loaded or ctx is done before extraction completes, it yields the error and
+// stops.
+func (f *Finder) All(ctx context.Context) iter.Seq2[Def, error] {
+	return func(yield func(Def, error) bool) {
+		ctx, cancel := f.withTimeout(ctx)
+		defer cancel()
+		pkgs, err := f.Load(ctx)
+		if err != nil {
+			yield(Def{}, err)
+			return
+		}
+		for def, err := range f.Extract(ctx, pkgs) {
+			if !yield(def, err) {
+				return
+			}
+		}
+	}
+}
+
+// withTimeout bounds ctx by the configured timeout, if any.
+func (f *Finder) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if f.Config.Timeout > 0 {
+		return context.WithTimeout(ctx, f.Config.Timeout)
+	}
+	return context.WithCancel(ctx)
+}
+
+// scanned returns pkgs followed by their dependencies out to the configured
+// depth in breadth-first order, each package once.
+func (f *Finder) scanned(pkgs []*packages.Package) []*packages.Package {
+	seen := make(map[*packages.Package]bool)
+	var scanned []*packages.Package
+	level := pkgs
+	for depth := 0; len(level) > 0 && (f.Config.Depth < 0 || depth <= f.Config.Depth); depth++ {
+		var next []*packages.Package
+		for _, pkg := range level {
+			if seen[pkg] {
+				continue
+			}
+			seen[pkg] = true
+			scanned = append(scanned, pkg)
+			for _, path := range slices.Sorted(maps.Keys(pkg.Imports)) {
+				next = append(next, pkg.Imports[path])
+			}
+		}
+		level = next
+	}
+	return scanned
+}
+
+// Extract yields the defs of pkgs, which must have been loaded with at least
+// LoadMode, and of their dependencies out to the configured depth in
+// discovery order while invoking the finder's hooks. Only defs selected by
+// the config's filters are yielded, and each only once even when the test
+// variant of a package repeats them. Packages that are ill-typed are skipped.
+// If ctx is done before extraction completes, it yields the error and stops.
+func (f *Finder) Extract(ctx context.Context, pkgs []*packages.Package) iter.Seq2[Def, error] {
+	return func(yield func(Def, error) bool) {
+		log := f.Config.logger()
+		seen := make(map[token.Position]bool)
+		scanned := f.scanned(pkgs)
+		if f.OnScan != nil {
+			f.OnScan(scanned)
+		}
+		var instances map[string][]string
+		if f.Config.Instantiations {
+			instances = instantiations(scanned)
+		}
+		var payloads map[string][]string
+		if f.Config.Panics {
+			payloads = panicPayloads(scanned)
+		}
+		chains := newChainResolver(pkgs)
+		reassigned := reassignedVars(scanned)
+		uses := findUses(scanned, f.Config.Uses, f.Config.loggers())
+		var live map[string][]Use
+		if f.Config.Dead {
+			live = findUses(scanned, liveKinds, nil)
+		}
+		var external map[string]bool
+		if f.Config.Reach {
+			external = externalErrors(scanned)
+		}
+		var entries map[string][]string
+		if f.Config.Propagation {
+			entries = entryPoints(scanned)
+		}
+		var returnedBy map[string][]string
+		if f.Config.Returns {
+			returnedBy = returningFuncs(scanned)
+		}
+		var undocumented map[string][]string
+		if f.Config.DocCoverage {
+			undocumented = undocumentedReturns(scanned)
+		}
+		var ifaceMethods map[string][]string
+		if f.Config.InterfaceMethods {
+			ifaceMethods = interfaceErrors(scanned)
+		}
+		var handlers map[string][]string
+		if f.Config.Handlers {
+			handlers = handlerErrors(scanned)
+		}
+		var satisfied map[string][]string
+		if f.Config.Satisfies {
+			satisfied = satisfiedInterfaces(scanned, resolveInterfaces(pkgs, f.Config.knownInterfaces()))
+		}
+		var returned map[string][]Use
+		if f.Config.ReturnStats {
+			returned = findUses(scanned, []UseKind{UseReturns, useWraps}, nil)
+		}
+		var refs map[string]*references
+		if f.Config.RefCount || f.Config.Consumers || f.Config.Untested {
+			refs = findReferences(scanned)
+		}
+		for _, pkg := range scanned {
+			if err := ctx.Err(); err != nil {
+				yield(Def{}, err)
+				return
+			}
+			if f.OnPackage != nil {
+				f.OnPackage(pkg)
+			}
+			if brokenPackage(pkg) {
+				log.InfoContext(ctx, "skipped package with errors", "package", pkg.PkgPath)
+				continue
+			}
+			start := time.Now()
+			var n int
+			for def := range extractDefs([]*source{packageSource(pkg)}, f.Config.Local) {
+				if len(def.Wraps) > 0 && def.Func == "" {
+					def.Chains = chains.chains(def.ImportPath + "." + def.Name)
+				}
+				if def.ErrorType != ErrorTypeConstructor && def.Func == "" {
+					def.Panics = payloads[def.ImportPath+"."+def.Name]
+					def.Uses = uses[def.ImportPath+"."+def.Name]
+					def.EntryPoints = entries[def.ImportPath+"."+def.Name]
+					def.ReturnedBy = returnedBy[def.ImportPath+"."+def.Name]
+					def.Undocumented = undocumented[def.ImportPath+"."+def.Name]
+					def.InterfaceMethods = ifaceMethods[def.ImportPath+"."+def.Name]
+					def.Handlers = handlers[def.ImportPath+"."+def.Name]
+					for _, use := range returned[def.ImportPath+"."+def.Name] {
+						switch {
+						case use.Kind == UseReturns:
+							def.ReturnedBare++
+						case use.Func != "": // Not a sentinel's initializer.
+							def.Wrapped++
+						}
+					}
+				}
+				if r := refs[def.ImportPath+"."+def.Name]; r != nil && def.Func == "" {
+					if f.Config.RefCount {
+						def.RefCount = r.Count
+					}
+					if f.Config.Consumers && def.ExportType == ExportTypeExported {
+						def.Consumers = r.Consumers
+					}
+				}
+				if f.Config.Untested && def.ExportType == ExportTypeExported && def.Func == "" && !def.Test && (def.ErrorType == ErrorTypeSentinel || def.ErrorType == ErrorTypeStructured) {
+					r := refs[def.ImportPath+"."+def.Name]
+					def.Untested = r == nil || !r.Tested
+				}
+				if def.ErrorType == ErrorTypeStructured && def.Func == "" {
+					def.Satisfies = satisfied[def.ImportPath+"."+def.Name]
+				}
+				if def.ErrorType == ErrorTypeSentinel && def.Func == "" {
+					def.Reassigned = reassigned[def.ImportPath+"."+def.Name]
+				}
+				if f.Config.Dead && def.ExportType == ExportTypeExported && def.Func == "" && (def.ErrorType == ErrorTypeSentinel || def.ErrorType == ErrorTypeStructured) {
+					def.Dead = len(live[def.ImportPath+"."+def.Name]) == 0
+				}
+				if f.Config.Reach && def.Func == "" && (def.ErrorType == ErrorTypeSentinel || def.ErrorType == ErrorTypeStructured) {
+					def.Reach = reachInternal
+					if def.ExportType == ExportTypeExported || external[def.ImportPath+"."+def.Name] {
+						def.Reach = reachExternal
+					}
+				}
+				if def.ErrorType == ErrorTypeStructured && def.Func == "" {
+					def.Instantiations = instances[def.ImportPath+"."+def.Name]
+					if tn, ok := pkg.Types.Scope().Lookup(def.Name).(*types.TypeName); ok {
+						def.CodeField, def.CodeType, def.Codes = errorCode(tn.Type(), f.Config.codeFields())
+					}
+				}
+				if f.Classify != nil {
+					def.ErrorType = f.Classify(def)
+				}
+				if seen[def.Position] || !f.Config.match(def) {
+					continue
+				}
+				seen[def.Position] = true
+				n++
+				if !yield(def, nil) {
+					return
+				}
+				if f.OnDef != nil && !f.OnDef(def) {
+					return
+				}
+			}
+			log.DebugContext(ctx, "scanned package", "package", pkg.PkgPath, "defs", n, "duration", time.Since(start))
+		}
+	}
+}
+
+// Find reports the defs of the packages matching patterns.
+func Find(ctx context.Context, cfg Config, patterns ...string) (*Result, error) {
+	f := &Finder{Config: cfg, Patterns: patterns}
+	return f.Find(ctx)
+}
+
+// Defs yields the defs declared at the top level of pkgs in discovery order.
+// The packages must have been loaded with at least LoadMode.
+func Defs(pkgs []*packages.Package) iter.Seq[Def] {
+	srcs := make([]*source, len(pkgs))
+	for i, pkg := range pkgs {
+		srcs[i] = packageSource(pkg)
+	}
+	return extractDefs(srcs, false)
+}
@@ -0,0 +1,1502 @@
+package errorfinder
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"go/build"
+	"go/token"
+	"log/slog"
+	"maps"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"golang.org/x/tools/go/packages"
+)
+
+const (
+	ubootPath    = "github.com/matttproud/errorfinder/testdata/uboot"
+	taxonomyPath = "github.com/matttproud/errorfinder/testdata/taxonomy"
+	brokenPath   = "github.com/matttproud/errorfinder/testdata/broken"
+)
+
+func TestFind(t *testing.T) {
+	res, err := Find(context.Background(), Config{}, "./testdata/uboot")
+	if err != nil {
+		t.Fatalf("Find(...) = %v, want nil", err)
+	}
+	type summary struct {
+		ErrorType
+		ExportType
+		ImportPath      string
+		Name            string
+		BackingTypeName string
+		Line            int
+	}
+	var got []summary
+	for _, d := range res.Defs {
+		got = append(got, summary{d.ErrorType, d.ExportType, d.ImportPath, d.Name, d.BackingTypeName, d.Position.Line})
+	}
+	want := []summary{
+		{ErrorTypeSentinel, ExportTypeExported, ubootPath, "ErrSentinel", "error", 5},
+		{ErrorTypeStructured, ExportTypeExported, ubootPath, "StructuredError", ubootPath + ".StructuredError", 9},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("Find(...) = %v, want %v", got, want)
+	}
+}
+
+func TestFinderAll(t *testing.T) {
+	f := &Finder{Patterns: []string{"./testdata/uboot", "./testdata/taxonomy"}}
+	var n int
+	for def, err := range f.All(context.Background()) {
+		if err != nil {
+			t.Fatalf("f.All(...) yielded %v, want nil", err)
+		}
+		if def.Name == "" {
+			t.Errorf("f.All(...) yielded def without name: %+v", def)
+		}
+		n++
+	}
+	if want := 8; n != want {
+		t.Errorf("f.All(...) yielded %d defs, want %d", n, want)
+	}
+	for def, err := range f.All(context.Background()) {
+		if err != nil {
+			t.Fatalf("f.All(...) yielded %v, want nil", err)
+		}
+		if def.ImportPath != ubootPath && def.ImportPath != taxonomyPath {
+			t.Errorf("f.All(...) yielded def of %q", def.ImportPath)
+		}
+		break
+	}
+}
+
+func TestFinderHooks(t *testing.T) {
+	var scanning []string
+	var pkgs []string
+	var seen []string
+	f := &Finder{
+		Patterns: []string{"./testdata/uboot", "./testdata/taxonomy"},
+		OnScan: func(scanned []*packages.Package) {
+			for _, pkg := range scanned {
+				scanning = append(scanning, pkg.PkgPath)
+			}
+		},
+		OnPackage: func(pkg *packages.Package) { pkgs = append(pkgs, pkg.PkgPath) },
+		OnDef: func(def Def) bool {
+			seen = append(seen, def.Name)
+			return def.Name != "ParseError"
+		},
+	}
+	res, err := f.Find(context.Background())
+	if err != nil {
+		t.Fatalf("f.Find(...) = %v, want nil", err)
+	}
+	if !slices.Contains(seen, "ParseError") {
+		t.Fatalf("OnDef saw %v, want ParseError among them", seen)
+	}
+	if got, want := len(res.Defs), len(seen); got != want {
+		t.Errorf("f.Find(...) returned %d defs after stopping, want %d", got, want)
+	}
+	if seen[len(seen)-1] != "ParseError" {
+		t.Errorf("OnDef saw %v after returning false", seen[slices.Index(seen, "ParseError")+1:])
+	}
+	if pkgs[len(pkgs)-1] != taxonomyPath {
+		t.Errorf("OnPackage saw %v, want %v last", pkgs, taxonomyPath)
+	}
+	if !slices.Equal(scanning, pkgs) {
+		t.Errorf("OnScan saw %v, want %v as seen by OnPackage", scanning, pkgs)
+	}
+}
+
+func TestFindCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := Find(ctx, Config{}, "./testdata/uboot"); !errors.Is(err, context.Canceled) {
+		t.Errorf("Find(...) = %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestFindNoPackages(t *testing.T) {
+	// Wildcards do not match directories named testdata.
+	_, err := Find(context.Background(), Config{}, "./testdata/...")
+	if !errors.Is(err, ErrNoPackages) {
+		t.Errorf("Find(...) = %v, want %v", err, ErrNoPackages)
+	}
+	var loadErr *LoadError
+	if !errors.As(err, &loadErr) || !slices.Equal(loadErr.Patterns, []string{"./testdata/..."}) {
+		t.Errorf("Find(...) = %#v, want *LoadError for the pattern", err)
+	}
+}
+
+func TestFindMissingDirectory(t *testing.T) {
+	_, err := Find(context.Background(), Config{}, "./testdata/missing")
+	if !errors.Is(err, ErrNoPackages) {
+		t.Errorf("Find(...) = %v, want %v", err, ErrNoPackages)
+	}
+}
+
+func TestFindOverlay(t *testing.T) {
+	file, err := filepath.Abs(filepath.Join("testdata", "uboot", "overlay.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := Config{Overlay: map[string][]byte{
+		file: []byte("package uboat\n\nimport \"errors\"\n\nvar ErrOverlay = errors.New(\"overlay\")\n"),
+	}}
+	res, err := Find(context.Background(), cfg, "./testdata/uboot")
+	if err != nil {
+		t.Fatalf("Find(...) = %v, want nil", err)
+	}
+	if got, want := names(res.Defs), []string{"ErrOverlay", "ErrSentinel", "StructuredError"}; !slices.Equal(got, want) {
+		t.Errorf("Find(...) = %v, want %v", got, want)
+	}
+}
+
+func TestFindTests(t *testing.T) {
+	res, err := Find(context.Background(), Config{}, "./testdata/withtests")
+	if err != nil {
+		t.Fatalf("Find(...) = %v, want nil", err)
+	}
+	if got, want := names(res.Defs), []string{"ErrLibrary"}; !slices.Equal(got, want) {
+		t.Errorf("Find(...) = %v, want %v", got, want)
+	}
+	res, err = Find(context.Background(), Config{Tests: true}, "./testdata/withtests")
+	if err != nil {
+		t.Fatalf("Find(...) = %v, want nil", err)
+	}
+	got := make(map[string]bool)
+	for _, d := range res.Defs {
+		if _, ok := got[d.Name]; ok {
+			t.Errorf("Find(...) reported %v more than once", d.Name)
+		}
+		got[d.Name] = d.Test
+	}
+	if want := map[string]bool{"ErrLibrary": false, "errInternalTest": true, "ErrExternalTest": true}; !maps.Equal(got, want) {
+		t.Errorf("Find(...) reported test defs %v, want %v", got, want)
+	}
+	res, err = Find(context.Background(), Config{TestsOnly: true}, "./testdata/withtests")
+	if err != nil {
+		t.Fatalf("Find(...) = %v, want nil", err)
+	}
+	if got, want := names(res.Defs), []string{"ErrExternalTest", "errInternalTest"}; !slices.Equal(got, want) {
+		t.Errorf("Find(..., TestsOnly: true) = %v, want %v", got, want)
+	}
+}
+
+func TestFindConstructors(t *testing.T) {
+	cfg := Config{Kinds: []ErrorType{ErrorTypeConstructor}}
+	res, err := Find(context.Background(), cfg, "./testdata/constructors")
+	if err != nil {
+		t.Fatalf("Find(...) = %v, want nil", err)
+	}
+	const path = "github.com/matttproud/errorfinder/testdata/constructors"
+	type constructor struct{ Name, BackingTypeName, InstanceOf, Doc string }
+	var got []constructor
+	for _, d := range res.Defs {
+		got = append(got, constructor{d.Name, d.BackingTypeName, d.InstanceOf, d.Doc})
+	}
+	want := []constructor{
+		{"NewConfigError", "*" + path + ".ConfigError", path + ".ConfigError", "NewConfigError returns a ConfigError for path.\n"},
+		{"invalid", "*" + path + ".ValidationError", path + ".ValidationError", "invalid is a constructor by the composite literal it returns.\n"},
+		{"newTimeout", path + ".timeoutError", path + ".timeoutError", "newTimeout hides the concrete type of the error it returns.\n"},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("Find(...) = %+v, want %+v", got, want)
+	}
+}
+
+func TestFindConstants(t *testing.T) {
+	cfg := Config{Kinds: []ErrorType{ErrorTypeSentinel}}
+	res, err := Find(context.Background(), cfg, "./testdata/consterrors")
+	if err != nil {
+		t.Fatalf("Find(...) = %v, want nil", err)
+	}
+	type sentinel struct {
+		Name, Message, Doc string
+		Const              bool
+	}
+	var got []sentinel
+	for _, d := range res.Defs {
+		got = append(got, sentinel{d.Name, d.Message, d.Doc, d.Const})
+	}
+	want := []sentinel{
+		{"ErrExists", "already exists", "", true},
+		{"ErrNotFound", "not found", "ErrNotFound indicates a missing key.\n", true},
+		{"KindInternal", "", "", true},
+		{"KindInvalid", "", "", true},
+		{"errClosed", "store: closed", "", true},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("Find(...) = %+v, want %+v", got, want)
+	}
+}
+
+func TestFindMultiName(t *testing.T) {
+	cfg := Config{Kinds: []ErrorType{ErrorTypeSentinel}}
+	res, err := Find(context.Background(), cfg, "./testdata/multiname")
+	if err != nil {
+		t.Fatalf("Find(...) = %v, want nil", err)
+	}
+	type sentinel struct {
+		Name, Initializer, Message string
+	}
+	var got []sentinel
+	for _, d := range res.Defs {
+		got = append(got, sentinel{d.Name, d.Initializer, d.Message})
+	}
+	want := []sentinel{
+		{"ErrA", "errors.New", "a"},
+		{"ErrB", "errors.New", "b"},
+		{"ErrCode", "", "code"},
+		{"ErrParse", "strconv.Atoi", ""},
+		{"ErrTyped", "errors.New", "typed"},
+		{"errNil", "", ""},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("Find(...) = %+v, want %+v", got, want)
+	}
+}
+
+func TestFindMessages(t *testing.T) {
+	cfg := Config{Kinds: []ErrorType{ErrorTypeStructured}}
+	res, err := Find(context.Background(), cfg, "./testdata/messages")
+	if err != nil {
+		t.Fatalf("Find(...) = %v, want nil", err)
+	}
+	got := make(map[string]string)
+	for _, d := range res.Defs {
+		got[d.Name] = d.Message
+	}
+	want := map[string]string{
+		"CodeError":       "",
+		"DynamicError":    "",
+		"NotFoundError":   "not found",
+		"PathError":       "open %s: %v",
+		"WrappedNotFound": "not found",
+	}
+	if !maps.Equal(got, want) {
+		t.Errorf("Find(...) messages = %v, want %v", got, want)
+	}
+}
+
+func TestFindJoined(t *testing.T) {
+	cfg := Config{Kinds: []ErrorType{ErrorTypeSentinel}}
+	res, err := Find(context.Background(), cfg, "./testdata/joined")
+	if err != nil {
+		t.Fatalf("Find(...) = %v, want nil", err)
+	}
+	const pkg = "github.com/matttproud/errorfinder/testdata/joined"
+	got := make(map[string][]string)
+	for _, d := range res.Defs {
+		if d.Initializer == "errors.Join" {
+			got[d.Name] = d.Wraps
+		}
+	}
+	want := map[string][]string{
+		"ErrBoth":    {pkg + ".ErrA", pkg + ".ErrB"},
+		"ErrSpread":  nil,
+		"ErrWithEOF": {pkg + ".ErrA", "io.EOF"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Find(...) joined = %v, want %v", got, want)
+	}
+}
+
+func TestFindShadowsStdlib(t *testing.T) {
+	cfg := Config{Kinds: []ErrorType{ErrorTypeSentinel}}
+	res, err := Find(context.Background(), cfg, "./testdata/shadows", "io")
+	if err != nil {
+		t.Fatalf("Find(...) = %v, want nil", err)
+	}
+	const pkg = "github.com/matttproud/errorfinder/testdata/shadows"
+	got := make(map[string][]string)
+	for _, d := range res.Defs {
+		got[d.ImportPath+"."+d.Name] = d.ShadowsStdlib
+	}
+	for name, want := range map[string][]string{
+		pkg + ".EOF":         {"io.EOF"},
+		pkg + ".ErrNotFound": {"os/exec.ErrNotFound"},
+		pkg + ".ErrOwn":      nil,
+		"io.EOF":             nil,
+	} {
+		if !slices.Equal(got[name], want) {
+			t.Errorf("Find(...) %v.ShadowsStdlib = %v, want %v", name, got[name], want)
+		}
+	}
+	if shadows := got[pkg+".ErrClosed"]; !slices.Contains(shadows, "os.ErrClosed") {
+		t.Errorf("Find(...) %v.ErrClosed.ShadowsStdlib = %v, want it to contain os.ErrClosed", pkg, shadows)
+	}
+}
+
+func TestFindAnonymous(t *testing.T) {
+	cfg := Config{Kinds: []ErrorType{ErrorTypeSentinel}}
+	res, err := Find(context.Background(), cfg, "./testdata/anonymous")
+	if err != nil {
+		t.Fatalf("Find(...) = %v, want nil", err)
+	}
+	type sentinel struct {
+		Name, BackingTypeName, InstanceOf string
+	}
+	var got []sentinel
+	for _, d := range res.Defs {
+		got = append(got, sentinel{d.Name, d.BackingTypeName, d.InstanceOf})
+	}
+	const pkg = "github.com/matttproud/errorfinder/testdata/anonymous"
+	want := []sentinel{
+		{"ErrCoded", "struct{error; Code code; Mode fs.FileMode}", ""},
+		{"ErrEmbedded", "struct{error}", ""},
+		{"ErrLazy", pkg + ".errorFunc", pkg + ".errorFunc"},
+		{"ErrPointer", "*struct{error}", ""},
+		{"ErrTemporary", "interface{Temporary() bool; error}", ""},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("Find(...) = %+v, want %+v", got, want)
+	}
+}
+
+func TestFindLocal(t *testing.T) {
+	for _, test := range []struct {
+		local bool
+		want  []string
+	}{
+		{false, []string{"ErrTop", "constError"}},
+		{true, []string{"ErrTop", "constError", "errEOF (*Parser).Parse", "errNested init", "skipError Walk"}},
+	} {
+		res, err := Find(context.Background(), Config{Local: test.local, Kinds: []ErrorType{ErrorTypeSentinel, ErrorTypeStructured}}, "./testdata/local")
+		if err != nil {
+			t.Fatalf("Find(...) = %v, want nil", err)
+		}
+		var got []string
+		for _, d := range res.Defs {
+			if d.Func != "" && d.ExportType != ExportTypeUnexported {
+				t.Errorf("Find(...) reported local %v as %v", d.Name, d.ExportType)
+			}
+			got = append(got, strings.TrimSpace(d.Name+" "+d.Func))
+		}
+		slices.Sort(got)
+		if !slices.Equal(got, test.want) {
+			t.Errorf("Find(..., Local: %v) = %q, want %q", test.local, got, test.want)
+		}
+	}
+}
+
+func TestFindErrorMethods(t *testing.T) {
+	cfg := Config{Kinds: []ErrorType{ErrorTypeStructured}}
+	res, err := Find(context.Background(), cfg, "./testdata/unwrap")
+	if err != nil {
+		t.Fatalf("Find(...) = %v, want nil", err)
+	}
+	type unwrap struct {
+		Name, Unwrap, Wraps string
+		Is, As              bool
+	}
+	var got []unwrap
+	for _, d := range res.Defs {
+		got = append(got, unwrap{d.Name, d.Unwrap, strings.Join(d.Wraps, " "), d.Is, d.As})
+	}
+	want := []unwrap{
+		{"CodeError", "", "", true, false},
+		{"MultiError", "[]error", "error", false, true},
+		{"PathError", "error", "os.PathError", false, false},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("Find(...) = %+v, want %+v", got, want)
+	}
+}
+
+func TestFindReceiverKind(t *testing.T) {
+	cfg := Config{Kinds: []ErrorType{ErrorTypeStructured}}
+	res, err := Find(context.Background(), cfg, "./testdata/constructors")
+	if err != nil {
+		t.Fatalf("Find(...) = %v, want nil", err)
+	}
+	got := make(map[string]string)
+	for _, d := range res.Defs {
+		got[d.Name] = d.ReceiverKind
+	}
+	want := map[string]string{"ConfigError": "pointer", "ValidationError": "pointer", "timeoutError": "value"}
+	if !maps.Equal(got, want) {
+		t.Errorf("Find(...) receiver kinds = %v, want %v", got, want)
+	}
+}
+
+func TestFindGeneric(t *testing.T) {
+	const path = "github.com/matttproud/errorfinder/testdata/generic"
+	for _, test := range []struct {
+		instantiations bool
+		want           map[string][]string
+	}{
+		{false, map[string][]string{
+			path + ".RangeError[N]": nil,
+			path + ".WrapError[T]":  nil,
+		}},
+		{true, map[string][]string{
+			path + ".RangeError[N]": {path + ".RangeError[int]"},
+			path + ".WrapError[T]":  {path + ".WrapError[[]byte]", path + ".WrapError[string]"},
+		}},
+	} {
+		cfg := Config{Kinds: []ErrorType{ErrorTypeStructured}, Depth: 1, Instantiations: test.instantiations}
+		res, err := Find(context.Background(), cfg, "./testdata/generic/user")
+		if err != nil {
+			t.Fatalf("Find(...) = %v, want nil", err)
+		}
+		got := make(map[string][]string)
+		for _, d := range res.Defs {
+			got[d.BackingTypeName] = d.Instantiations
+		}
+		if !maps.EqualFunc(got, test.want, slices.Equal) {
+			t.Errorf("Find(..., Instantiations: %v) = %q, want %q", test.instantiations, got, test.want)
+		}
+	}
+}
+
+func TestFindAliases(t *testing.T) {
+	cfg := Config{Kinds: []ErrorType{ErrorTypeAlias}}
+	res, err := Find(context.Background(), cfg, "./testdata/aliases")
+	if err != nil {
+		t.Fatalf("Find(...) = %v, want nil", err)
+	}
+	type alias struct {
+		Name, BackingTypeName, AliasOf, ReceiverKind string
+		Deprecated                                   bool
+	}
+	var got []alias
+	for _, d := range res.Defs {
+		got = append(got, alias{d.Name, d.BackingTypeName, d.AliasOf, d.ReceiverKind, d.Deprecated})
+	}
+	want := []alias{
+		{"ParseError", taxonomyPath + ".ParseError", taxonomyPath + ".ParseError", "value", true},
+		{"PathError", "io/fs.PathError", "io/fs.PathError", "pointer", false},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("Find(...) = %+v, want %+v", got, want)
+	}
+}
+
+func TestFindInterfaces(t *testing.T) {
+	res, err := Find(context.Background(), Config{}, "./testdata/interfaces")
+	if err != nil {
+		t.Fatalf("Find(...) = %v, want nil", err)
+	}
+	type iface struct{ Kind, Name, Methods string }
+	var got []iface
+	for _, d := range res.Defs {
+		got = append(got, iface{d.ErrorType.Name(), d.Name, strings.Join(d.Methods, "; ")})
+	}
+	want := []iface{
+		{"interface", "TemporaryError", "Temporary() bool"},
+		{"interface", "TimeoutError", "Deadline() (time.Time, bool); Temporary() bool"},
+		{"interface", "coder", "Code(detail string) int"},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("Find(...) = %+v, want %+v", got, want)
+	}
+}
+
+func TestFindGenerated(t *testing.T) {
+	for _, test := range []struct {
+		exclude bool
+		want    map[string]bool
+	}{
+		{false, map[string]bool{"ErrHandWritten": false, "ErrInvalidStatus": true}},
+		{true, map[string]bool{"ErrHandWritten": false}},
+	} {
+		res, err := Find(context.Background(), Config{ExcludeGenerated: test.exclude}, "./testdata/generated")
+		if err != nil {
+			t.Fatalf("Find(...) = %v, want nil", err)
+		}
+		got := make(map[string]bool)
+		for _, d := range res.Defs {
+			got[d.Name] = d.Generated
+		}
+		if !maps.Equal(got, test.want) {
+			t.Errorf("Find(..., ExcludeGenerated: %v) = %v, want %v", test.exclude, got, test.want)
+		}
+	}
+}
+
+func TestFindCgo(t *testing.T) {
+	if !build.Default.CgoEnabled {
+		t.Skip("cgo is not enabled")
+	}
+	res, err := Find(context.Background(), Config{}, "./testdata/cgo")
+	if err != nil {
+		t.Fatalf("Find(...) = %v, want nil", err)
+	}
+	type cgoDef struct {
+		Name      string
+		File      string
+		Generated bool
+	}
+	var got []cgoDef
+	for _, d := range res.Defs {
+		got = append(got, cgoDef{d.Name, filepath.Base(d.Position.Filename), d.Generated})
+	}
+	want := []cgoDef{
+		{"ErrBusy", "status.go", true},
+		{"ErrInvalid", "cgo.go", false},
+		{"ErrPure", "pure.go", false},
+		{"ErrnoError", "cgo.go", false},
+		{"Fail", "cgo.go", false},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("Find(...) = %+v, want %+v", got, want)
+	}
+}
+
+func TestFindVendored(t *testing.T) {
+	cfg := Config{Dir: "testdata/vendormod", Env: append(os.Environ(), "GOFLAGS=-mod=vendor"), Depth: 1, Kinds: []ErrorType{ErrorTypeSentinel}}
+	res, err := Find(context.Background(), cfg, ".")
+	if err != nil {
+		t.Fatalf("Find(...) = %v, want nil", err)
+	}
+	type vendored struct {
+		Name         string
+		Vendored     bool
+		VendorModule string
+	}
+	var got []vendored
+	for _, d := range res.Defs {
+		if d.ImportPath == "errors" {
+			continue
+		}
+		got = append(got, vendored{d.Name, d.Vendored, d.VendorModule})
+	}
+	want := []vendored{
+		{"ErrApp", false, ""},
+		{"ErrVendored", true, "example.com/dep@v1.2.0"},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("Find(...) = %+v, want %+v", got, want)
+	}
+}
+
+func TestFindEmbedding(t *testing.T) {
+	cfg := Config{Kinds: []ErrorType{ErrorTypeStructured}}
+	res, err := Find(context.Background(), cfg, "./testdata/embedding")
+	if err != nil {
+		t.Fatalf("Find(...) = %v, want nil", err)
+	}
+	const path = "github.com/matttproud/errorfinder/testdata/embedding"
+	got := make(map[string][]string)
+	for _, d := range res.Defs {
+		got[d.Name] = d.Embedding
+	}
+	want := map[string][]string{
+		"Base":      {path + ".CodeError"},
+		"CodeError": nil,
+		"List":      {path + ".List", "error"},
+		"OpError":   {path + ".Base", path + ".Base > " + path + ".CodeError", "error"},
+	}
+	if !maps.EqualFunc(got, want, slices.Equal) {
+		t.Errorf("Find(...) embedding = %q, want %q", got, want)
+	}
+}
+
+func TestFindProvenance(t *testing.T) {
+	cfg := Config{Kinds: []ErrorType{ErrorTypeSentinel}}
+	res, err := Find(context.Background(), cfg, "./testdata/provenance")
+	if err != nil {
+		t.Fatalf("Find(...) = %v, want nil", err)
+	}
+	const path = "github.com/matttproud/errorfinder/testdata/provenance/myerrors"
+	type provenance struct{ Name, Initializer, Call, Message string }
+	var got []provenance
+	for _, d := range res.Defs {
+		got = append(got, provenance{d.Name, d.Initializer, d.Call, d.Message})
+	}
+	want := []provenance{
+		{"ErrDynamic", "", "(func() error literal)()", ""},
+		{"ErrLiteral", "", "", ""},
+		{"ErrPlain", "errors.New", `errors.New("plain")`, "plain"},
+		{"ErrRegistry", "(*" + path + ".Registry).New", `registry.New("registered")`, ""},
+		{"ErrTimeout", path + ".New", `myerrors.New(myerrors.CodeTimeout, "timed out")`, ""},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("Find(...) = %+v, want %+v", got, want)
+	}
+}
+
+func TestFindLogger(t *testing.T) {
+	var buf bytes.Buffer
+	cfg := Config{Logger: slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))}
+	if _, err := Find(context.Background(), cfg, "./testdata/tagged", "./testdata/broken"); err != nil {
+		t.Fatalf("Find(...) = %v, want nil", err)
+	}
+	for _, want := range []string{
+		`msg="loaded packages"`,
+		`msg="package error" package=` + brokenPath,
+		`msg="skipped package with errors" package=` + brokenPath,
+		`msg="ignored files" package=github.com/matttproud/errorfinder/testdata/tagged`,
+		`msg="scanned package" package=github.com/matttproud/errorfinder/testdata/tagged defs=1`,
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("Find(...) logged:\n%v\nwant it to contain %q", buf.String(), want)
+		}
+	}
+}
+
+func TestFindSkipsIllTyped(t *testing.T) {
+	res, err := Find(context.Background(), Config{}, "./testdata/uboot", "./testdata/broken")
+	if err != nil {
+		t.Fatalf("Find(...) = %v, want nil", err)
+	}
+	if want := []string{brokenPath}; !slices.Equal(res.Skipped, want) {
+		t.Errorf("Find(...).Skipped = %v, want %v", res.Skipped, want)
+	}
+	if len(res.PackageErrors) == 0 {
+		t.Errorf("Find(...).PackageErrors = %v, want the type error", res.PackageErrors)
+	}
+	for _, d := range res.Defs {
+		if d.ImportPath != ubootPath {
+			t.Errorf("Find(...) returned def %v of %v, want only defs of %v", d.Name, d.ImportPath, ubootPath)
+		}
+	}
+}
+
+func TestFindBrokenDependency(t *testing.T) {
+	res, err := Find(context.Background(), Config{}, "./testdata/brokendep/a")
+	if err != nil {
+		t.Fatalf("Find(...) = %v, want nil", err)
+	}
+	if len(res.Skipped) > 0 {
+		t.Errorf("Find(...).Skipped = %v, want none", res.Skipped)
+	}
+	if len(res.PackageErrors) == 0 {
+		t.Errorf("Find(...).PackageErrors = %v, want the dependency's type error", res.PackageErrors)
+	}
+	if got, want := names(res.Defs), []string{"ErrA"}; !slices.Equal(got, want) {
+		t.Errorf("Find(...) = %v, want %v", got, want)
+	}
+}
+
+// names returns the names of defs in order.
+func names(defs []Def) []string {
+	names := make([]string, len(defs))
+	for i, d := range defs {
+		names[i] = d.Name
+	}
+	return names
+}
+
+func TestFindConfig(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		cfg      Config
+		patterns []string
+		want     []string
+	}{
+		{"kinds", Config{Kinds: []ErrorType{ErrorTypeStructured}}, []string{"./testdata/taxonomy"}, []string{"ConfigError", "ParseError", "syntaxError"}},
+		{"exported", Config{Kinds: []ErrorType{ErrorTypeSentinel}, ExportedOnly: true}, []string{"./testdata/taxonomy"}, []string{"DefaultConfigError", "ErrSyntax", "ErrUnsupported"}},
+		{"no depth", Config{}, []string{"./testdata/importer"}, nil},
+		{"depth", Config{Depth: 1, ExportedOnly: true, Kinds: []ErrorType{ErrorTypeStructured}}, []string{"./testdata/importer"}, []string{"ConfigError", "ParseError"}},
+		{"untagged", Config{}, []string{"./testdata/tagged"}, []string{"ErrUntagged"}},
+		{"tagged", Config{Tags: []string{"extra"}}, []string{"./testdata/tagged"}, []string{"ErrTagged", "ErrUntagged"}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			res, err := Find(context.Background(), test.cfg, test.patterns...)
+			if err != nil {
+				t.Fatalf("Find(...) = %v, want nil", err)
+			}
+			if got := names(res.Defs); !slices.Equal(got, test.want) {
+				t.Errorf("Find(...) = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+var errorTypeWrapped = RegisterErrorType("wrapped")
+
+func TestRegisterErrorType(t *testing.T) {
+	if got, want := errorTypeWrapped.String(), "ErrorTypeWrapped"; got != want {
+		t.Errorf("errorTypeWrapped.String() = %q, want %q", got, want)
+	}
+	if got := ErrorTypes(); !slices.Contains(got, errorTypeWrapped) {
+		t.Errorf("ErrorTypes() = %v, want it to contain %v", got, errorTypeWrapped)
+	}
+	for _, name := range []string{"", "Upper", "a,b", "sentinel", "wrapped"} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("RegisterErrorType(%q) did not panic", name)
+				}
+			}()
+			RegisterErrorType(name)
+		}()
+	}
+}
+
+func TestFinderClassify(t *testing.T) {
+	f := &Finder{
+		Config:   Config{Kinds: []ErrorType{ErrorTypeStructured, errorTypeWrapped}},
+		Patterns: []string{"./testdata/taxonomy"},
+		Classify: func(d Def) ErrorType {
+			if len(d.Wraps) > 0 {
+				return errorTypeWrapped
+			}
+			return d.ErrorType
+		},
+	}
+	res, err := f.Find(context.Background())
+	if err != nil {
+		t.Fatalf("f.Find(...) = %v, want nil", err)
+	}
+	var got []string
+	for _, d := range res.Defs {
+		got = append(got, d.ErrorType.Name()+" "+d.Name)
+	}
+	want := []string{"wrapped ConfigError", "wrapped ErrUnsupported", "wrapped ParseError", "wrapped syntaxError"}
+	if !slices.Equal(got, want) {
+		t.Errorf("f.Find(...) = %v, want %v", got, want)
+	}
+}
+
+func TestParseErrorType(t *testing.T) {
+	for name, want := range map[string]ErrorType{"sentinel": ErrorTypeSentinel, "structured": ErrorTypeStructured, "constructor": ErrorTypeConstructor, "alias": ErrorTypeAlias, "interface": ErrorTypeInterface, "wrapped": errorTypeWrapped} {
+		if got, err := ParseErrorType(name); got != want || err != nil {
+			t.Errorf("ParseErrorType(%q) = %v, %v; want %v, nil", name, got, err, want)
+		}
+	}
+	if _, err := ParseErrorType("unknown"); err == nil {
+		t.Errorf("ParseErrorType(%q) = _, nil; want error", "unknown")
+	}
+}
+
+func TestFinderRelations(t *testing.T) {
+	f := &Finder{Patterns: []string{"./testdata/taxonomy"}}
+	res, err := f.Find(context.Background())
+	if err != nil {
+		t.Fatalf("f.Find(...) = %v, want nil", err)
+	}
+	byName := make(map[string]Def)
+	for _, d := range res.Defs {
+		byName[d.Name] = d
+	}
+	if got, want := byName["DefaultConfigError"].InstanceOf, taxonomyPath+".ConfigError"; got != want {
+		t.Errorf("DefaultConfigError.InstanceOf = %q, want %q", got, want)
+	}
+	if got, want := byName["ConfigError"].Embeds, []string{taxonomyPath + ".ParseError"}; !slices.Equal(got, want) {
+		t.Errorf("ConfigError.Embeds = %q, want %q", got, want)
+	}
+	if got, want := byName["syntaxError"].Wraps, []string{taxonomyPath + ".ErrSyntax"}; !slices.Equal(got, want) {
+		t.Errorf("syntaxError.Wraps = %q, want %q", got, want)
+	}
+	if got, want := byName["ErrUnsupported"].Wraps, []string{"errors.ErrUnsupported"}; !slices.Equal(got, want) {
+		t.Errorf("ErrUnsupported.Wraps = %q, want %q", got, want)
+	}
+}
+
+func TestFindInitializers(t *testing.T) {
+	res, err := Find(context.Background(), Config{Kinds: []ErrorType{ErrorTypeSentinel}}, "./testdata/taxonomy")
+	if err != nil {
+		t.Fatalf("Find(...) = %v, want nil", err)
+	}
+	type init struct {
+		Name, Initializer, Message string
+		Wrapping                   bool
+	}
+	var got []init
+	for _, d := range res.Defs {
+		got = append(got, init{d.Name, d.Initializer, d.Message, d.Wrapping})
+	}
+	want := []init{
+		{"DefaultConfigError", "", "", false},
+		{"ErrSyntax", "errors.New", "syntax error", false},
+		{"ErrUnsupported", "fmt.Errorf", "taxonomy: unsupported %w", true},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("Find(...) = %+v, want %+v", got, want)
+	}
+}
+
+func TestFindWrapTypes(t *testing.T) {
+	res, err := Find(context.Background(), Config{Kinds: []ErrorType{ErrorTypeSentinel}}, "./testdata/wrapping")
+	if err != nil {
+		t.Fatalf("Find(...) = %v, want nil", err)
+	}
+	const pkg = "github.com/matttproud/errorfinder/testdata/wrapping"
+	type wrap struct {
+		Name             string
+		Wrapping         bool
+		Wraps, WrapTypes string
+	}
+	var got []wrap
+	for _, d := range res.Defs {
+		got = append(got, wrap{d.Name, d.Wrapping, strings.Join(d.Wraps, " "), strings.Join(d.WrapTypes, " ")})
+	}
+	want := []wrap{
+		{"ErrBase", false, "", ""},
+		{"ErrBoth", true, pkg + ".ErrBase io.EOF", "error error"},
+		{"ErrChain", true, pkg + ".ErrBase", "error"},
+		{"ErrMissing", true, "", ""},
+		{"ErrMixed", true, pkg + ".ErrBase", "error"},
+		{"ErrNil", true, "", "untyped nil"},
+		{"ErrPath", true, "io/fs.PathError", "*io/fs.PathError"},
+		{"ErrPlain", false, "", ""},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("Find(...) = %+v, want %+v", got, want)
+	}
+}
+
+func TestFindCodes(t *testing.T) {
+	const pkg = "github.com/matttproud/errorfinder/testdata/codes"
+	type code struct{ Name, CodeField, CodeType, Codes string }
+	for _, test := range []struct {
+		fields []string
+		want   []code
+	}{
+		{nil, []code{
+			{"APIError", "Code", pkg + ".Code", "CodeNotFound=1 CodePermission=2 CodeUnknown=0"},
+			{"HTTPError", "Status", "int", ""},
+			{"KindError", "", "", ""},
+		}},
+		{[]string{"Kind"}, []code{
+			{"APIError", "", "", ""},
+			{"HTTPError", "", "", ""},
+			{"KindError", "Kind", pkg + ".Kind", `KindIO="io"`},
+		}},
+	} {
+		cfg := Config{Kinds: []ErrorType{ErrorTypeStructured}, CodeFields: test.fields}
+		res, err := Find(context.Background(), cfg, "./testdata/codes")
+		if err != nil {
+			t.Fatalf("Find(...) = %v, want nil", err)
+		}
+		var got []code
+		for _, d := range res.Defs {
+			got = append(got, code{d.Name, d.CodeField, d.CodeType, strings.Join(d.Codes, " ")})
+		}
+		if !slices.Equal(got, test.want) {
+			t.Errorf("Find(..., CodeFields: %q) = %+v, want %+v", test.fields, got, test.want)
+		}
+	}
+}
+
+func TestFindGRPCCodes(t *testing.T) {
+	cfg := Config{Dir: "testdata/grpcmod", Env: append(os.Environ(), "GOFLAGS=-mod=vendor"), Kinds: []ErrorType{ErrorTypeSentinel}}
+	res, err := Find(context.Background(), cfg, ".")
+	if err != nil {
+		t.Fatalf("Find(...) = %v, want nil", err)
+	}
+	type grpc struct{ Name, Initializer, Message, GRPCCode string }
+	var got []grpc
+	for _, d := range res.Defs {
+		got = append(got, grpc{d.Name, d.Initializer, d.Message, d.GRPCCode})
+	}
+	want := []grpc{
+		{"ErrDenied", grpcStatus + ".Errorf", "svc: %s denied", "PermissionDenied"},
+		{"ErrNotFound", grpcStatus + ".Error", "svc: not found", "NotFound"},
+		{"ErrNumbered", grpcStatus + ".Error", "svc: numbered", "99"},
+		{"ErrPlain", "errors.New", "svc: plain", ""},
+		{"ErrUnavailable", grpcStatus + ".Error", "svc: unavailable", "Unavailable"},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("Find(...) = %+v, want %+v", got, want)
+	}
+}
+
+func TestFindChains(t *testing.T) {
+	res, err := Find(context.Background(), Config{}, "./testdata/chains")
+	if err != nil {
+		t.Fatalf("Find(...) = %v, want nil", err)
+	}
+	const pkg = "github.com/matttproud/errorfinder/testdata/chains"
+	got := make(map[string][]string)
+	for _, d := range res.Defs {
+		if len(d.Chains) > 0 {
+			got[d.Name] = d.Chains
+		}
+	}
+	want := map[string][]string{
+		"ConfigError": {pkg + ".ConfigError > " + pkg + ".ParseError > " + pkg + ".ErrSyntax"},
+		"ErrConfig":   {pkg + ".ErrConfig > " + pkg + ".ConfigError > " + pkg + ".ParseError > " + pkg + ".ErrSyntax"},
+		"ErrEOF":      {pkg + ".ErrEOF > io.EOF"},
+		"ParseError":  {pkg + ".ParseError > " + pkg + ".ErrSyntax"},
+		"PingError":   {pkg + ".PingError > " + pkg + ".PongError > " + pkg + ".PingError"},
+		"PongError":   {pkg + ".PongError > " + pkg + ".PingError > " + pkg + ".PongError"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Find(...) chains = %v, want %v", got, want)
+	}
+}
+
+func TestFindPanics(t *testing.T) {
+	const pkg = "github.com/matttproud/errorfinder/testdata/panics"
+	for _, test := range []struct {
+		panics bool
+		want   map[string][]string
+	}{
+		{false, map[string][]string{}},
+		{true, map[string][]string{
+			"ErrFatal":       {pkg + ".Fail", pkg + ".Later"},
+			"InvariantError": {"(*" + pkg + ".Parser).Parse", pkg + ".Check"},
+		}},
+	} {
+		res, err := Find(context.Background(), Config{Panics: test.panics}, "./testdata/panics")
+		if err != nil {
+			t.Fatalf("Find(...) = %v, want nil", err)
+		}
+		got := make(map[string][]string)
+		for _, d := range res.Defs {
+			if len(d.Panics) > 0 {
+				got[d.Name] = d.Panics
+			}
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("Find(..., Panics: %v) panics = %v, want %v", test.panics, got, test.want)
+		}
+	}
+}
+
+func TestFindReassigned(t *testing.T) {
+	res, err := Find(context.Background(), Config{}, "./testdata/reassigned/...")
+	if err != nil {
+		t.Fatalf("Find(...) = %v, want nil", err)
+	}
+	got := make(map[string]bool)
+	for _, d := range res.Defs {
+		if d.ImportPath != "errors" {
+			got[d.Name] = d.Reassigned
+		}
+	}
+	want := map[string]bool{
+		"ErrStable":   false,
+		"ErrReplaced": true,
+		"ErrInit":     true,
+		"ErrTarget":   true,
+		"ErrRanged":   true,
+		"ErrTuple":    true,
+		"ErrRemote":   true,
+		"ErrShadowed": false,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Find(...) reassigned = %v, want %v", got, want)
+	}
+}
+
+func TestFindLateInit(t *testing.T) {
+	res, err := Find(context.Background(), Config{}, "./testdata/lateinit")
+	if err != nil {
+		t.Fatalf("Find(...) = %v, want nil", err)
+	}
+	type late struct {
+		Name, Initializer, Message string
+		LateInit, Reassigned       bool
+		Chains                     string
+	}
+	var got []late
+	for _, d := range res.Defs {
+		got = append(got, late{d.Name, d.Initializer, d.Message, d.LateInit, d.Reassigned, strings.Join(d.Chains, ", ")})
+	}
+	const pkg = "github.com/matttproud/errorfinder/testdata/lateinit"
+	want := []late{
+		{"ErrA", "errors.New", "a", true, false, ""},
+		{"ErrB", "errors.New", "b", true, false, ""},
+		{"ErrEarly", "errors.New", "early", false, true, ""},
+		{"ErrFormat", "fmt.Errorf", "format: %w", true, false, pkg + ".ErrFormat > " + pkg + ".ErrEarly"},
+		{"ErrLate", "errors.New", "late", true, false, ""},
+		{"ErrNever", "", "", false, false, ""},
+		{"ErrTwice", "errors.New", "first", true, true, ""},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("Find(...) = %+v, want %+v", got, want)
+	}
+}
+
+func TestFindHelpers(t *testing.T) {
+	const pkg = "github.com/matttproud/errorfinder/testdata/helpers"
+	res, err := Find(context.Background(), Config{}, "./testdata/helpers")
+	if err != nil {
+		t.Fatalf("Find(...) = %v, want nil", err)
+	}
+	type helper struct {
+		Name, Initializer, Message, Helper string
+		Wraps                              []string
+	}
+	var got []helper
+	for _, d := range res.Defs {
+		if d.ImportPath == pkg {
+			got = append(got, helper{d.Name, d.Initializer, d.Message, d.Helper, d.Wraps})
+		}
+	}
+	want := []helper{
+		{"ErrDirect", pkg + ".newErr", "", "", nil},
+		{"ErrGeneric", pkg + ".parseErr", "", pkg + ".Must", nil},
+		{"ErrMust", "errors.New", "must", pkg + ".must", nil},
+		{"ErrNested", "errors.New", "nested", pkg + ".must", nil},
+		{"ErrPlain", "errors.New", "plain", "", nil},
+		{"ErrWrapped", "fmt.Errorf", "wrapped: %w", pkg + ".must", []string{pkg + ".ErrMust"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Find(...) = %+v, want %+v", got, want)
+	}
+}
+
+func TestFindUses(t *testing.T) {
+	const pkg = "github.com/matttproud/errorfinder/testdata/uses"
+	for _, test := range []struct {
+		uses []UseKind
+		want map[string][]string
+	}{
+		{nil, map[string][]string{}},
+		{[]UseKind{UseReturns}, map[string][]string{
+			"ErrNotFound": {"returns in " + pkg + ".Find at uses.go:19:14", "returns in (*" + pkg + ".Store).Get at uses.go:34:33"},
+			"QueryError":  {"returns in " + pkg + ".Query at uses.go:26:10"},
+		}},
+		{[]UseKind{UseIs}, map[string][]string{
+			"ErrNotFound": {"is in " + pkg + ".IsNotFound at uses.go:38:57", "is in " + pkg + ".Retry at uses.go:42:49"},
+			"ErrUnused":   {"is in " + pkg + ".Retry at uses.go:42:22"},
+		}},
+		{[]UseKind{UseAs}, map[string][]string{
+			"QueryError": {
+				"as in " + pkg + ".AsQuery at uses.go:54:24",
+				"as in " + pkg + ".AsQueryValue at uses.go:59:24 (target is uses.QueryError but only *uses.QueryError implements error)",
+			},
+			"TimeoutError": {"as in " + pkg + ".AsTimeout at uses.go:64:24 (target is *uses.TimeoutError but uses.TimeoutError values implement error)"},
+		}},
+		{[]UseKind{UseCompares}, map[string][]string{
+			"ErrNotFound": {
+				"compares in " + pkg + ".IsMissing at uses.go:67:48 (compared with == rather than errors.Is)",
+				"compares in " + pkg + ".Classify at uses.go:71:7 (switch case rather than errors.Is)",
+			},
+			"ErrUnused": {"compares in " + pkg + ".Classify at uses.go:73:7 (switch case rather than errors.Is)"},
+		}},
+		{[]UseKind{UseAsserts}, map[string][]string{
+			"QueryError": {
+				"asserts in " + pkg + ".IsQuery at uses.go:80:16 (type assertion rather than errors.As)",
+				"asserts in " + pkg + ".Kind at uses.go:86:7 (type switch case rather than errors.As)",
+			},
+			"TimeoutError": {"asserts in " + pkg + ".Kind at uses.go:88:7 (type switch case rather than errors.As)"},
+		}},
+	} {
+		res, err := Find(context.Background(), Config{Uses: test.uses}, "./testdata/uses")
+		if err != nil {
+			t.Fatalf("Find(...) = %v, want nil", err)
+		}
+		got := make(map[string][]string)
+		for _, d := range res.Defs {
+			for _, use := range d.Uses {
+				use.Position.Filename = filepath.Base(use.Position.Filename)
+				got[d.Name] = append(got[d.Name], use.String())
+			}
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("Find(..., Uses: %v) uses = %v, want %v", test.uses, got, test.want)
+		}
+	}
+}
+
+func TestFindUsesIllTyped(t *testing.T) {
+	res, err := Find(context.Background(), Config{Uses: UseKinds, Stdlib: true}, "./testdata/illtyped")
+	if err != nil {
+		t.Fatalf("Find(...) = %v, want nil", err)
+	}
+	if got, want := res.Skipped, []string{"github.com/matttproud/errorfinder/testdata/illtyped"}; !slices.Equal(got, want) {
+		t.Errorf("Find(...).Skipped = %v, want %v", got, want)
+	}
+	// Walk the package's syntax as though it type-checked, so that the
+	// analyses meet expressions without types.
+	pkgs, err := packages.Load(&packages.Config{Mode: LoadMode}, "./testdata/illtyped")
+	if err != nil {
+		t.Fatalf("packages.Load(...) = %v, want nil", err)
+	}
+	for _, pkg := range pkgs {
+		pkg.Errors = nil
+	}
+	cfg := Config{}
+	findUses(pkgs, UseKinds, cfg.loggers())
+}
+
+func TestFindLogged(t *testing.T) {
+	const pkg = "github.com/matttproud/errorfinder/testdata/logged"
+	for _, test := range []struct {
+		loggers []string
+		want    map[string][]string
+	}{
+		{nil, map[string][]string{
+			"ErrStale":   {"logs in " + pkg + ".Refresh at logged.go:16:28 (logged but not returned)"},
+			"FetchError": {"logs in " + pkg + ".Poll at logged.go:27:35 (logged but not returned)"},
+		}},
+		{[]string{pkg + ".audit"}, map[string][]string{
+			"FetchError": {"logs in " + pkg + ".Report at logged.go:31:38 (logged but not returned)"},
+		}},
+	} {
+		res, err := Find(context.Background(), Config{Uses: []UseKind{UseLogs}, Loggers: test.loggers}, "./testdata/logged")
+		if err != nil {
+			t.Fatalf("Find(...) = %v, want nil", err)
+		}
+		got := make(map[string][]string)
+		for _, d := range res.Defs {
+			for _, use := range d.Uses {
+				use.Position.Filename = filepath.Base(use.Position.Filename)
+				got[d.Name] = append(got[d.Name], use.String())
+			}
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("Find(..., Loggers: %v) uses = %v, want %v", test.loggers, got, test.want)
+		}
+	}
+}
+
+func TestFindDiscarded(t *testing.T) {
+	const pkg = "github.com/matttproud/errorfinder/testdata/discarded"
+	res, err := Find(context.Background(), Config{Uses: []UseKind{UseDiscards}}, "./testdata/discarded")
+	if err != nil {
+		t.Fatalf("Find(...) = %v, want nil", err)
+	}
+	got := make(map[string][]string)
+	for _, d := range res.Defs {
+		for _, use := range d.Uses {
+			use.Position.Filename = filepath.Base(use.Position.Filename)
+			got[d.Name] = append(got[d.Name], use.String())
+		}
+	}
+	want := map[string][]string{
+		"ErrClosed": {
+			"discards in " + pkg + ".Shutdown at discarded.go:18:6 (error from " + pkg + ".Close discarded)",
+			"discards at discarded.go:23:9 (error from " + pkg + ".Close discarded)",
+		},
+		"FlushError": {"discards in " + pkg + ".Shutdown at discarded.go:19:10 (error from " + pkg + ".Sync discarded)"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Find(...) uses = %v, want %v", got, want)
+	}
+}
+
+func TestFindKeys(t *testing.T) {
+	const pkg = "github.com/matttproud/errorfinder/testdata/keys"
+	res, err := Find(context.Background(), Config{Uses: []UseKind{UseKeys}}, "./testdata/keys")
+	if err != nil {
+		t.Fatalf("Find(...) = %v, want nil", err)
+	}
+	got := make(map[string][]string)
+	for _, d := range res.Defs {
+		for _, use := range d.Uses {
+			use.Position.Filename = filepath.Base(use.Position.Filename)
+			got[d.Name] = append(got[d.Name], use.String())
+		}
+	}
+	want := map[string][]string{
+		"ErrNotFound": {"keys at keys.go:12:2"},
+		"ErrGone":     {"keys at keys.go:13:2", "keys in " + pkg + ".Retryable at keys.go:22:7"},
+		"ErrBusy":     {"keys in " + pkg + ".Count at keys.go:18:43"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Find(...) uses = %v, want %v", got, want)
+	}
+}
+
+func TestFindStdlib(t *testing.T) {
+	res, err := Find(context.Background(), Config{Stdlib: true}, "./testdata/stdlib")
+	if err != nil {
+		t.Fatalf("Find(...) = %v, want nil", err)
+	}
+	got := make(map[string][]UseKind)
+	for name, uses := range res.Stdlib {
+		for _, use := range uses {
+			got[name] = append(got[name], use.Kind)
+		}
+	}
+	want := map[string][]UseKind{
+		"io.EOF":              {UseCompares},
+		"io.ErrUnexpectedEOF": {UseReturns},
+		"io/fs.ErrNotExist":   {UseIs},
+		"net.Error":           {UseAs},
+		"os.PathError":        {UseReturns},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Find(...) stdlib = %v, want %v", got, want)
+	}
+}
+
+func TestFindStdlibDotlessModule(t *testing.T) {
+	cfg := Config{Dir: "testdata/dotless", Env: append(os.Environ(), "GOWORK=off"), Stdlib: true}
+	res, err := Find(context.Background(), cfg, "./...")
+	if err != nil {
+		t.Fatalf("Find(...) = %v, want nil", err)
+	}
+	if got, want := slices.Sorted(maps.Keys(res.Stdlib)), []string{"io.EOF"}; !slices.Equal(got, want) {
+		t.Errorf("Find(...) stdlib = %v, want %v", got, want)
+	}
+}
+
+// TestFindDefFields tests the Def fields that Find fills in for the
+// defs of the testdata packages, with zero values left out of want.
+func TestFindDefFields(t *testing.T) {
+	const testdata = "github.com/matttproud/errorfinder/testdata/"
+	var (
+		entryPoints      = func(d Def) any { return d.EntryPoints }
+		returnedBy       = func(d Def) any { return d.ReturnedBy }
+		interfaceMethods = func(d Def) any { return d.InterfaceMethods }
+		satisfies        = func(d Def) any { return d.Satisfies }
+	)
+	satisfiesDefault := map[string]any{
+		"NetError":     []string{"net.Error", "interface{ Temporary() bool }", "interface{ Timeout() bool }"},
+		"TimeoutError": []string{"interface{ Timeout() bool }"},
+		"WrapError":    []string{"interface{ Unwrap() error }"},
+	}
+	ifacesMethods := map[string]any{
+		"ErrNotFound":  []string{"(" + testdata + "ifaces.Store).Get"},
+		"ErrReadOnly":  []string{"(" + testdata + "ifaces.Store).Put"},
+		"TimeoutError": []string{"(" + testdata + "ifaces.Store).Get"},
+	}
+	for _, test := range []struct {
+		name    string
+		cfg     Config
+		pattern string
+		field   func(Def) any
+		want    map[string]any
+	}{
+		{"no dead", Config{}, "./testdata/dead", func(d Def) any { return d.Dead }, map[string]any{}},
+		{"dead", Config{Dead: true}, "./testdata/dead", func(d Def) any { return d.Dead }, map[string]any{
+			"ErrDead":   true,
+			"DeadError": true,
+		}},
+		{"reach", Config{Reach: true, Kinds: []ErrorType{ErrorTypeSentinel, ErrorTypeStructured}}, "./testdata/reach", func(d Def) any { return d.Reach }, map[string]any{
+			"ErrPublic":   "external",
+			"errReturned": "external",
+			"errWrapped":  "external",
+			"errIndirect": "external",
+			"errChained":  "external",
+			"ErrChain":    "external",
+			"errInternal": "internal",
+			"errClosure":  "internal",
+			"queryError":  "external",
+			"hiddenError": "internal",
+		}},
+		{"propagation", Config{Propagation: true}, "./testdata/propagation", entryPoints, map[string]any{
+			"ErrInvalid":   []string{"(*" + testdata + "propagation.Client).Get", testdata + "propagation.Check"},
+			"ErrNotFound":  []string{"(*" + testdata + "propagation.Client).Get", testdata + "propagation.Wrap"},
+			"TimeoutError": []string{"(*" + testdata + "propagation.Client).Get"},
+		}},
+		{"returns", Config{Returns: true}, "./testdata/uses", returnedBy, map[string]any{
+			"ErrNotFound": []string{testdata + "uses.Find", testdata + "uses.Query"},
+			"QueryError":  []string{testdata + "uses.Query"},
+		}},
+		{"doc coverage", Config{DocCoverage: true}, "./testdata/doccoverage", func(d Def) any { return d.Undocumented }, map[string]any{
+			"ErrInvalid":  []string{testdata + "doccoverage.Get", testdata + "doccoverage.Put"},
+			"ErrNotFound": []string{testdata + "doccoverage.Delete"},
+		}},
+		{"return stats", Config{ReturnStats: true}, "./testdata/uses", func(d Def) any { return [2]int{d.ReturnedBare, d.Wrapped} }, map[string]any{
+			"ErrNotFound": [2]int{2, 1},
+			"QueryError":  [2]int{1, 0},
+		}},
+		{"no untested", Config{}, "./testdata/untested", func(d Def) any { return d.Untested }, map[string]any{}},
+		{"untested", Config{Untested: true}, "./testdata/untested", func(d Def) any { return d.Untested }, map[string]any{
+			"ErrUncovered":   true,
+			"UncoveredError": true,
+		}},
+		{"interface methods", Config{InterfaceMethods: true}, "./testdata/ifaces", interfaceMethods, ifacesMethods},
+		// A depth of -1 scans the standard library, whose package unsafe
+		// declares types that are not named.
+		{"interface methods of all depths", Config{InterfaceMethods: true, Depth: -1}, "./testdata/ifaces", interfaceMethods, ifacesMethods},
+		{"handlers", Config{Handlers: true}, "./testdata/handlers", func(d Def) any { return d.Handlers }, map[string]any{
+			"ErrNotFound":  []string{testdata + "handlers.GetItem"},
+			"ErrForbidden": []string{testdata + "handlers.GetItem"},
+			"ErrInternal":  []string{"(" + testdata + "handlers.Uploader).ServeHTTP"},
+			"QuotaError":   []string{testdata + "handlers.Quota"},
+		}},
+		{"satisfies", Config{Satisfies: true}, "./testdata/wellknown", satisfies, satisfiesDefault},
+		{"satisfies known", Config{Satisfies: true, KnownInterfaces: []string{"interface{ Unwrap() error }", "example.com/missing.Error", "int"}}, "./testdata/wellknown", satisfies, map[string]any{
+			"WrapError": []string{"interface{ Unwrap() error }"},
+		}},
+		{"satisfies of all depths", Config{Satisfies: true, Depth: -1}, "./testdata/wellknown", satisfies, satisfiesDefault},
+		{"retryable", Config{}, "./testdata/retry", func(d Def) any { return d.Retryable }, map[string]any{
+			"ErrUnavailable": "yes",
+			"ErrInvalid":     "no",
+			"ErrDeadline":    "yes",
+			"ErrCanceled":    "no",
+			"ErrOdd":         "unknown",
+			"ErrConflict":    "yes",
+			"ErrBusyForever": "no",
+			"TemporaryError": "yes",
+			"PermanentError": "no",
+			"QueryError":     "unknown",
+		}},
+		{"stack", Config{}, "./testdata/stack", func(d Def) any { return d.Stack }, map[string]any{
+			"FramesError":   true,
+			"CallersError":  true,
+			"TracedError":   true,
+			"EmbeddedError": true,
+		}},
+		{"fields", Config{}, "./testdata/stack", func(d Def) any { return d.Fields }, map[string]any{
+			"FramesError": []string{"Frames *runtime.Frames"},
+			"PlainError":  []string{"Op string"},
+			"ListError":   []string{"Next *" + testdata + "stack.ListError"},
+		}},
+		{"ref count", Config{RefCount: true}, "./testdata/uses", func(d Def) any { return d.RefCount }, map[string]any{
+			"ErrNotFound":  7,
+			"ErrUnused":    2,
+			"QueryError":   6,
+			"TimeoutError": 3,
+		}},
+		{"consumers", Config{Consumers: true}, "./testdata/consumers/...", func(d Def) any { return d.Consumers }, map[string]any{
+			"ErrShared":  []string{testdata + "consumers/cli", testdata + "consumers/svc"},
+			"ErrService": []string{testdata + "consumers/svc"},
+		}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			res, err := Find(context.Background(), test.cfg, test.pattern)
+			if err != nil {
+				t.Fatalf("Find(...) = %v, want nil", err)
+			}
+			got := make(map[string]any)
+			for _, d := range res.Defs {
+				if v := test.field(d); strings.HasPrefix(d.ImportPath, testdata) && !reflect.ValueOf(v).IsZero() {
+					got[d.Name] = v
+				}
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("Find(...) = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestFindDeadIllTyped(t *testing.T) {
+	res, err := Find(context.Background(), Config{Dead: true}, "./testdata/illtyped", "./testdata/dead")
+	if err != nil {
+		t.Fatalf("Find(...) = %v, want nil", err)
+	}
+	var got []string
+	for _, d := range res.Defs {
+		if d.Dead {
+			got = append(got, d.Name)
+		}
+	}
+	if want := []string{"ErrDead", "DeadError"}; !slices.Equal(got, want) {
+		t.Errorf("Find(..., Dead: true) dead = %v, want %v", got, want)
+	}
+	// Find the live uses in the package's syntax as though it type-checked.
+	pkgs, err := packages.Load(&packages.Config{Mode: LoadMode}, "./testdata/illtyped")
+	if err != nil {
+		t.Fatalf("packages.Load(...) = %v, want nil", err)
+	}
+	for _, pkg := range pkgs {
+		pkg.Errors = nil
+	}
+	findUses(pkgs, liveKinds, nil)
+}
+
+func TestFormatVerbs(t *testing.T) {
+	for format, want := range map[string]string{
+		"":                      "",
+		"plain":                 "",
+		"100%% %v":              "v",
+		"%s: %w":                "sw",
+		"%*d %-8.3f %+q %#x %w": "*dfqxw",
+		"%w and %w":             "ww",
+		"trailing %":            "",
+		"unicode %é":            "é",
+	} {
+		if got := string(formatVerbs(format)); got != want {
+			t.Errorf("formatVerbs(%q) = %q, want %q", format, got, want)
+		}
+	}
+}
+
+var exampleDef = Def{
+	ErrorType:       ErrorTypeSentinel,
+	ExportType:      ExportTypeExported,
+	ImportPath:      "example.com/p",
+	PackageName:     "p",
+	Name:            "ErrX",
+	BackingTypeName: "error",
+	Position:        token.Position{Filename: "p.go", Offset: 30, Line: 3, Column: 5},
+	Message:         "x",
+	InstanceOf:      "example.com/p.Error",
+}
+
+func TestDefMarshalJSON(t *testing.T) {
+	b, err := json.Marshal(exampleDef)
+	if err != nil {
+		t.Fatalf("json.Marshal(...) = %v, want nil", err)
+	}
+	var got map[string]any
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatalf("json.Unmarshal(...) = %v, want nil", err)
+	}
+	want := map[string]any{
+		"errorType":       "ErrorTypeSentinel",
+		"exportType":      "ExportTypeExported",
+		"importPath":      "example.com/p",
+		"packageName":     "p",
+		"name":            "ErrX",
+		"backingTypeName": "error",
+		"position":        "p.go:3:5",
+		"doc":             "",
+		"message":         "x",
+		"deprecated":      false,
+		"deprecation":     "",
+		"test":            false,
+		"generated":       false,
+		"vendored":        false,
+		"vendorModule":    "",
+		"initializer":     "",
+		"call":            "",
+		"helper":          "",
+		"codeField":       "",
+		"codeType":        "",
+		"grpcCode":        "",
+		"instanceOf":      "example.com/p.Error",
+		"reassigned":      false,
+		"dead":            false,
+		"reach":           "",
+		"refCount":        float64(0),
+		"returnedBare":    float64(0),
+		"wrapped":         float64(0),
+		"untested":        false,
+		"retryable":       "",
+		"stack":           false,
+		"lateInit":        false,
+		"wrapping":        false,
+		"const":           false,
+		"func":            "",
+		"unwrap":          "",
+		"is":              false,
+		"as":              false,
+		"receiverKind":    "",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("json.Marshal(...) = %s, want %v", b, want)
+	}
+}
+
+func TestDefWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := exampleDef.WriteCSV(w); err != nil {
+		t.Fatalf("d.WriteCSV(...) = %v, want nil", err)
+	}
+	w.Flush()
+	if got, want := buf.String(), "ErrorTypeSentinel,ExportTypeExported,example.com/p,p,ErrX,error,p.go:3:5,,x,false,,false,,false,false,,,,false,false,,,,false,false,,,,,,,,,,,,example.com/p.Error,false,,,false,,,0,,,,0,0,false,,,,,false,false,\n"; got != want {
+		t.Errorf("d.WriteCSV(...) wrote %q, want %q", got, want)
+	}
+}
+
+func TestDeprecation(t *testing.T) {
+	for _, test := range []struct {
+		doc        string
+		deprecated bool
+		reason     string
+	}{
+		{"", false, ""},
+		{"E is an error.\n", false, ""},
+		{"E is an error.\n\nDeprecated: Use F\ninstead.\n", true, "Use F instead."},
+		{"Deprecated:\n", true, ""},
+		{"E is not Deprecated: here.\n", false, ""},
+	} {
+		deprecated, reason := deprecation(test.doc)
+		if deprecated != test.deprecated || reason != test.reason {
+			t.Errorf("deprecation(%q) = %v, %q; want %v, %q", test.doc, deprecated, reason, test.deprecated, test.reason)
+		}
+	}
+}
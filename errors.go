@@ -0,0 +1,23 @@
+package errorfinder
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrNoPackages indicates that the patterns given to a Finder matched no
+// packages. It is reported wrapped in a LoadError.
+var ErrNoPackages = errors.New("no packages matched")
+
+// A LoadError reports a failure to load the packages named by Patterns.
+type LoadError struct {
+	Patterns []string
+	Err      error
+}
+
+func (e *LoadError) Error() string {
+	return fmt.Sprintf("loading packages %v: %v", strings.Join(e.Patterns, " "), e.Err)
+}
+
+func (e *LoadError) Unwrap() error { return e.Err }
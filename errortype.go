@@ -0,0 +1,65 @@
+package errorfinder
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// errorTypeNames holds the names of the error types indexed by value: the
+// built-in ones followed by those added with RegisterErrorType.
+var errorTypeNames = []string{"unknown", "sentinel", "structured", "constructor", "alias", "interface"}
+
+// RegisterErrorType adds an error type with the given name, such as
+// "constructor", for a Finder's Classify hook to assign. Defs of the new type
+// sort after those of every type registered before it. RegisterErrorType is
+// meant to be called during program initialization; it panics if the name is
+// empty, not in lower case, contains a comma, or is already registered.
+func RegisterErrorType(name string) ErrorType {
+	if name == "" || strings.ToLower(name) != name || strings.Contains(name, ",") {
+		panic(fmt.Sprintf("errorfinder: invalid error type name %q", name))
+	}
+	if slices.Contains(errorTypeNames, name) {
+		panic(fmt.Sprintf("errorfinder: error type %q already registered", name))
+	}
+	errorTypeNames = append(errorTypeNames, name)
+	return ErrorType(len(errorTypeNames) - 1)
+}
+
+// ErrorTypes returns the known error types other than ErrorTypeUnknown in
+// order.
+func ErrorTypes() []ErrorType {
+	types := make([]ErrorType, len(errorTypeNames)-1)
+	for i := range types {
+		types[i] = ErrorType(i + 1)
+	}
+	return types
+}
+
+// ParseErrorType parses the name of an error type, such as "sentinel".
+func ParseErrorType(name string) (ErrorType, error) {
+	if i := slices.Index(errorTypeNames, name); i > 0 {
+		return ErrorType(i), nil
+	}
+	return ErrorTypeUnknown, fmt.Errorf("unknown error type %q", name)
+}
+
+// Name returns the name the error type was registered with, such as
+// "sentinel".
+func (t ErrorType) Name() string {
+	if t < 0 || int(t) >= len(errorTypeNames) {
+		return strconv.Itoa(int(t))
+	}
+	return errorTypeNames[t]
+}
+
+// String returns the name of the error type's identifier, such as
+// "ErrorTypeSentinel", which registered types mimic.
+func (t ErrorType) String() string {
+	if t < 0 || int(t) >= len(errorTypeNames) {
+		return "ErrorType(" + strconv.FormatInt(int64(t), 10) + ")"
+	}
+	name := errorTypeNames[t]
+	return "ErrorType" + strings.ToUpper(name[:1]) + name[1:]
+}
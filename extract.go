@@ -0,0 +1,829 @@
+package errorfinder
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"iter"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/tools/go/packages"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// A source is a type-checked package from which defs are extracted. It holds
+// the subset of packages.Package that extraction needs, so that an
+// analysis.Pass can be described by one too.
+type source struct {
+	PkgPath   string
+	Name      string
+	Module    string // The path@version of the package's module, if known.
+	Fset      *token.FileSet
+	Syntax    []*ast.File
+	TypesInfo *types.Info
+
+	inits map[*types.Var]*ast.AssignStmt // Memoizes lateInits.
+}
+
+// lateInits reports the assignments in the package's init functions that
+// initialize its package-level variables declared without a value, as the
+// function lateInits does, computing them once.
+func (s *source) lateInits() map[*types.Var]*ast.AssignStmt {
+	if s.inits == nil {
+		s.inits = lateInits(s.Syntax, s.TypesInfo)
+	}
+	return s.inits
+}
+
+func packageSource(pkg *packages.Package) *source {
+	src := &source{
+		PkgPath:   pkg.PkgPath,
+		Name:      pkg.Name,
+		Fset:      pkg.Fset,
+		Syntax:    pkg.Syntax,
+		TypesInfo: pkg.TypesInfo,
+	}
+	if pkg.Module != nil {
+		src.Module = moduleVersion(pkg.Module.Path, pkg.Module.Version)
+	}
+	return src
+}
+
+// moduleVersion joins a module path and version, which may be empty, as in
+// "example.com/dep@v1.2.0".
+func moduleVersion(path, version string) string {
+	if version == "" {
+		return path
+	}
+	return path + "@" + version
+}
+
+type searchTree struct {
+	Decl      ast.Decl
+	File      *ast.File
+	Info      *types.Info
+	Pkg       *source
+	Generated bool // Whether File is marked as generated; see isGenerated.
+}
+
+func topLevelDecls(pkgs []*source) iter.Seq[searchTree] {
+	return func(yield func(searchTree) bool) {
+		for _, pkg := range pkgs {
+			for _, file := range pkg.Syntax {
+				if cgo, orig := cgoSource(pkg.Fset, file); cgo && orig == "" {
+					continue // Declarations cgo adds to the package, not the user's.
+				}
+				generated := isGenerated(pkg.Fset, file)
+				for _, decl := range file.Decls {
+					if !yield(searchTree{decl, file, pkg.TypesInfo, pkg, generated}) {
+						return
+					}
+				}
+			}
+		}
+	}
+}
+
+// cgoGenerated is the marker cgo writes at the top of the files it generates.
+const cgoGenerated = "// Code generated by cmd/cgo; DO NOT EDIT."
+
+// cgoSource reports whether file was generated by cgo and, if so, the name of
+// the file that uses cgo from which it was translated, which its //line
+// directives refer to. The name is empty for the files that cgo adds to the
+// package, such as _cgo_gotypes.go.
+func cgoSource(fset *token.FileSet, file *ast.File) (cgo bool, orig string) {
+	for _, group := range file.Comments {
+		if group.Pos() > file.Package {
+			break
+		}
+		for _, c := range group.List {
+			if c.Text != cgoGenerated {
+				continue
+			}
+			name := fset.Position(file.Package).Filename
+			if name == fset.File(file.Package).Name() {
+				return true, ""
+			}
+			return true, name
+		}
+	}
+	return false, ""
+}
+
+// isGenerated reports whether file is marked as generated. For a file that
+// cgo translated, it reports whether the file that uses cgo is, since cgo
+// marks all of its output as generated.
+func isGenerated(fset *token.FileSet, file *ast.File) bool {
+	cgo, orig := cgoSource(fset, file)
+	if !cgo {
+		return ast.IsGenerated(file)
+	}
+	header, err := parser.ParseFile(token.NewFileSet(), orig, nil, parser.PackageClauseOnly|parser.ParseComments)
+	return err == nil && ast.IsGenerated(header)
+}
+
+func expType(id *ast.Ident) ExportType {
+	if ast.IsExported(id.Name) {
+		return ExportTypeExported
+	}
+	return ExportTypeUnexported
+}
+
+// docText returns the text of the doc comment for spec, falling back to that
+// of its declaration when the declaration holds only spec.
+func docText(decl *ast.GenDecl, specDoc *ast.CommentGroup) string {
+	if specDoc == nil && len(decl.Specs) == 1 {
+		specDoc = decl.Doc
+	}
+	return specDoc.Text()
+}
+
+// isFunc reports whether fn is the package-level function pkgPath.name.
+func isFunc(fn *types.Func, pkgPath, name string) bool {
+	return fn != nil && fn.Pkg() != nil && fn.Pkg().Path() == pkgPath && fn.Name() == name
+}
+
+// A sentinelInit describes the call that initializes a sentinel.
+type sentinelInit struct {
+	Call      string   // The call as written.
+	Func      string   // The function called, such as "fmt.Errorf".
+	Message   string   // The constant message or format string, if any.
+	Wrapping  bool     // Whether the format string has a %w verb.
+	Wraps     []string // The errors passed for %w verbs or joined by errors.Join.
+	WrapTypes []string // The static types of the arguments for %w verbs.
+	GRPCCode  string   // The gRPC code passed to status.Error or status.Errorf.
+	Helper    string   // The Must-style helper applied to the call, if any.
+}
+
+// The import paths of gRPC's status package, whose Error and Errorf functions
+// create errors with a code, and of the package of the codes.
+const (
+	grpcStatus = "google.golang.org/grpc/status"
+	grpcCodes  = "google.golang.org/grpc/codes"
+)
+
+// initSentinel describes the call in a sentinel's initializer, returning the
+// zero sentinelInit for an initializer that is not a call. Only for calls to
+// errors.New, fmt.Errorf, and gRPC's status.Error and status.Errorf is the
+// message known, and errors.Join records the errors it joins; other
+// functions, such as a package's own constructors, are named by their full
+// name. A Must-style helper applied to another call is looked through to
+// describe that call instead.
+func initSentinel(info *types.Info, value ast.Expr) sentinelInit {
+	var init sentinelInit
+	call, ok := ast.Unparen(value).(*ast.CallExpr)
+	if !ok {
+		return init
+	}
+	init.Call = types.ExprString(call)
+	switch fn := typeutil.StaticCallee(info, call); {
+	case fn == nil:
+		return init
+	case isFunc(fn, "errors", "New"):
+		init.Func = "errors.New"
+	case isFunc(fn, "fmt", "Errorf"):
+		init.Func = "fmt.Errorf"
+	case isFunc(fn, "errors", "Join"):
+		init.Func = "errors.Join"
+		if call.Ellipsis.IsValid() {
+			return init // The errors in a spread slice are not known.
+		}
+		for _, arg := range call.Args {
+			if target := errorTarget(info, arg); target != "" {
+				init.Wraps = append(init.Wraps, target)
+			}
+		}
+		return init
+	case isFunc(fn, grpcStatus, "Error"), isFunc(fn, grpcStatus, "Errorf"):
+		init.Func = fn.FullName()
+		if len(call.Args) < 2 {
+			return init
+		}
+		init.GRPCCode = grpcCode(info, call.Args[0])
+		if tv := info.Types[call.Args[1]]; tv.Value != nil && tv.Value.Kind() == constant.String {
+			init.Message = constant.StringVal(tv.Value)
+		}
+		return init
+	default:
+		if inner, ok := helperArg(info, call); ok {
+			init = initSentinel(info, inner)
+			init.Call, init.Helper = types.ExprString(call), fn.FullName()
+			return init
+		}
+		init.Func = fn.FullName()
+		return init
+	}
+	if len(call.Args) == 0 {
+		return init
+	}
+	tv := info.Types[call.Args[0]]
+	if tv.Value == nil || tv.Value.Kind() != constant.String {
+		return init
+	}
+	init.Message = constant.StringVal(tv.Value)
+	if init.Func != "fmt.Errorf" {
+		return init
+	}
+	for i, verb := range formatVerbs(init.Message) {
+		if verb != 'w' {
+			continue
+		}
+		init.Wrapping = true
+		if i+1 < len(call.Args) {
+			arg := call.Args[i+1]
+			if target := errorTarget(info, arg); target != "" {
+				init.Wraps = append(init.Wraps, target)
+			}
+			if t := info.TypeOf(arg); t != nil {
+				init.WrapTypes = append(init.WrapTypes, t.String())
+			}
+		}
+	}
+	return init
+}
+
+// helperArg reports the call that call applies a Must-style helper to, as in
+// must(parseErr("x")): a helper is a function of a single argument, that
+// call, whose result is of the type of its first parameter, such as
+// func[T any](T, error) T.
+func helperArg(info *types.Info, call *ast.CallExpr) (*ast.CallExpr, bool) {
+	if len(call.Args) != 1 {
+		return nil, false
+	}
+	inner, ok := ast.Unparen(call.Args[0]).(*ast.CallExpr)
+	if !ok {
+		return nil, false
+	}
+	sig, ok := info.TypeOf(call.Fun).Underlying().(*types.Signature)
+	if !ok || sig.Params().Len() == 0 || sig.Results().Len() != 1 {
+		return nil, false
+	}
+	return inner, types.Identical(sig.Params().At(0).Type(), sig.Results().At(0).Type())
+}
+
+// grpcCode names the constant gRPC code that expr evaluates to, such as
+// "NotFound", or gives its value if the codes package names none. It returns
+// the empty string if the code is not a constant.
+func grpcCode(info *types.Info, expr ast.Expr) string {
+	tv := info.Types[expr]
+	named, ok := tv.Type.(*types.Named)
+	if tv.Value == nil || !ok || named.Obj().Pkg() == nil || named.Obj().Pkg().Path() != grpcCodes {
+		return ""
+	}
+	scope := named.Obj().Pkg().Scope()
+	for _, name := range scope.Names() {
+		c, ok := scope.Lookup(name).(*types.Const)
+		if ok && c.Exported() && types.Identical(c.Type(), named) && constant.Compare(c.Val(), token.EQL, tv.Value) {
+			return name
+		}
+	}
+	return tv.Value.ExactString()
+}
+
+// formatVerbs returns the verbs of a format string in the order in which they
+// consume arguments, with * for a width or precision taken from an argument.
+// Explicit argument indexes are not supported.
+func formatVerbs(format string) []rune {
+	var verbs []rune
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			continue
+		}
+		for i++; i < len(format) && strings.IndexByte("+-# 0123456789.*", format[i]) >= 0; i++ {
+			if format[i] == '*' {
+				verbs = append(verbs, '*')
+			}
+		}
+		if i == len(format) {
+			break
+		}
+		if format[i] == '%' {
+			continue
+		}
+		verb, size := utf8.DecodeRuneInString(format[i:])
+		verbs = append(verbs, verb)
+		i += size - 1
+	}
+	return verbs
+}
+
+// deprecation finds the "Deprecated:" paragraph of a doc comment, reporting
+// whether one is present and the reason it gives.
+func deprecation(doc string) (deprecated bool, reason string) {
+	for _, para := range strings.Split(doc, "\n\n") {
+		if rest, ok := strings.CutPrefix(para, "Deprecated:"); ok {
+			return true, strings.Join(strings.Fields(rest), " ")
+		}
+	}
+	return false, ""
+}
+
+func isTestFile(filename string) bool {
+	return strings.HasSuffix(filename, "_test.go")
+}
+
+// isVendored reports whether filename is in a vendor directory.
+func isVendored(filename string) bool {
+	return slices.Contains(strings.Split(filepath.ToSlash(filepath.Dir(filename)), "/"), "vendor")
+}
+
+// isStringError reports whether t is a string type of pkg whose Error method
+// returns the value itself, as in
+//
+//	type Error string
+//
+//	func (e Error) Error() string { return string(e) }
+//
+// so that the message of a constant of type t is its value.
+func isStringError(pkg *source, t types.Type) bool {
+	named, ok := t.(*types.Named)
+	if !ok || named.Obj().Pkg() == nil || named.Obj().Pkg().Path() != pkg.PkgPath {
+		return false
+	}
+	if b, ok := named.Underlying().(*types.Basic); !ok || b.Info()&types.IsString == 0 {
+		return false
+	}
+	fd, result := errorReturn(pkg, named)
+	if fd == nil || len(fd.Recv.List[0].Names) != 1 {
+		return false
+	}
+	conv, ok := ast.Unparen(result).(*ast.CallExpr)
+	if !ok || len(conv.Args) != 1 || !pkg.TypesInfo.Types[conv.Fun].IsType() {
+		return false
+	}
+	id, ok := ast.Unparen(conv.Args[0]).(*ast.Ident)
+	return ok && pkg.TypesInfo.Uses[id] == pkg.TypesInfo.Defs[fd.Recv.List[0].Names[0]]
+}
+
+// errorReturn finds the declaration in pkg of the Error method in the method
+// set of t and the expression it returns. It reports nil unless the method's
+// body is a single return statement.
+func errorReturn(pkg *source, t types.Type) (*ast.FuncDecl, ast.Expr) {
+	obj, _, _ := types.LookupFieldOrMethod(t, false, nil, "Error")
+	fn, ok := obj.(*types.Func)
+	if !ok {
+		return nil, nil
+	}
+	fd := methodDecl(pkg, fn)
+	if fd == nil || fd.Body == nil || len(fd.Body.List) != 1 {
+		return nil, nil
+	}
+	ret, ok := fd.Body.List[0].(*ast.ReturnStmt)
+	if !ok || len(ret.Results) != 1 {
+		return nil, nil
+	}
+	return fd, ret.Results[0]
+}
+
+// errorMessage returns the message of the error type t declared in pkg when
+// its Error method returns a constant string, or the format when it returns
+// fmt.Sprintf with a constant format, such as "open %v: %v". Otherwise, it
+// returns the empty string.
+func errorMessage(pkg *source, t types.Type) string {
+	_, result := errorReturn(pkg, types.NewPointer(t))
+	if result == nil {
+		return ""
+	}
+	if call, ok := ast.Unparen(result).(*ast.CallExpr); ok && len(call.Args) > 0 && isFunc(typeutil.StaticCallee(pkg.TypesInfo, call), "fmt", "Sprintf") {
+		result = call.Args[0]
+	}
+	tv := pkg.TypesInfo.Types[result]
+	if tv.Value == nil || tv.Value.Kind() != constant.String {
+		return ""
+	}
+	return constant.StringVal(tv.Value)
+}
+
+// receiverKind reports how a concrete type t implements error: "value" if t
+// itself does or "pointer" if only *t does. It returns the empty string when
+// neither does and for interface types.
+func receiverKind(t types.Type) string {
+	switch {
+	case types.IsInterface(t):
+		return ""
+	case isErrorType(t):
+		return "value"
+	case isErrorType(types.NewPointer(t)):
+		return "pointer"
+	}
+	return ""
+}
+
+// extractors extract the defs of each kind from a top-level declaration.
+var extractors = []func(searchTree) iter.Seq[Def]{
+	extractSentinels,
+	extractStructured,
+	extractConstructors,
+	extractAliases,
+	extractInterfaces,
+}
+
+// extractDefs yields the defs declared at the top level of pkgs in discovery
+// order, followed for each function, if local is set, by the sentinels and
+// error types declared in its body.
+func extractDefs(pkgs []*source, local bool) iter.Seq[Def] {
+	extract := extractors
+	if local {
+		extract = append(slices.Clip(extractors), extractLocal)
+	}
+	return func(yield func(Def) bool) {
+		for tree := range topLevelDecls(pkgs) {
+			for _, extract := range extract {
+				for def := range extract(tree) {
+					def.Test, def.Generated = isTestFile(def.Position.Filename), tree.Generated
+					if def.Vendored = isVendored(def.Position.Filename); def.Vendored {
+						def.VendorModule = tree.Pkg.Module
+					}
+					if !yield(def) {
+						return
+					}
+				}
+			}
+		}
+	}
+}
+
+// funcName names the function fd declares as a method expression would, such
+// as "Parse" or "(*Parser).Parse".
+func funcName(fd *ast.FuncDecl) string {
+	if fd.Recv == nil || len(fd.Recv.List) == 0 {
+		return fd.Name.Name
+	}
+	recv, star := fd.Recv.List[0].Type, false
+	if s, ok := recv.(*ast.StarExpr); ok {
+		recv, star = s.X, true
+	}
+	switch r := recv.(type) {
+	case *ast.IndexExpr:
+		recv = r.X
+	case *ast.IndexListExpr:
+		recv = r.X
+	}
+	name := types.ExprString(recv)
+	if star {
+		name = "(*" + name + ")"
+	}
+	return name + "." + fd.Name.Name
+}
+
+// extractLocal yields the sentinels and error types, including interfaces,
+// declared in the body of
+// the function that tree declares, including in its closures. Being
+// inaccessible outside the function, they are reported as unexported.
+func extractLocal(tree searchTree) iter.Seq[Def] {
+	return func(yield func(Def) bool) {
+		fd, ok := tree.Decl.(*ast.FuncDecl)
+		if !ok || fd.Body == nil {
+			return
+		}
+		var decls []ast.Decl
+		ast.Inspect(fd.Body, func(n ast.Node) bool {
+			if stmt, ok := n.(*ast.DeclStmt); ok {
+				decls = append(decls, stmt.Decl)
+			}
+			return true
+		})
+		name := funcName(fd)
+		for _, decl := range decls {
+			local := searchTree{decl, tree.File, tree.Info, tree.Pkg, tree.Generated}
+			for _, extract := range []func(searchTree) iter.Seq[Def]{extractSentinels, extractStructured, extractInterfaces} {
+				for def := range extract(local) {
+					def.ExportType, def.Func = ExportTypeUnexported, name
+					if !yield(def) {
+						return
+					}
+				}
+			}
+		}
+	}
+}
+
+// specValue returns the expression that initializes the ith name of spec:
+// its own value or the one call returning the values of all of its names. It
+// returns nil if the names have no values.
+func specValue(spec *ast.ValueSpec, i int) ast.Expr {
+	switch len(spec.Values) {
+	case len(spec.Names):
+		return spec.Values[i]
+	case 1:
+		return spec.Values[0]
+	}
+	return nil
+}
+
+func extractSentinels(tree searchTree) iter.Seq[Def] {
+	return func(yield func(Def) bool) {
+		genDecl, ok := tree.Decl.(*ast.GenDecl)
+		if !ok {
+			return
+		}
+		for _, s := range genDecl.Specs {
+			valueSpec, ok := s.(*ast.ValueSpec)
+			if !ok {
+				continue
+			}
+			for i, n := range valueSpec.Names {
+				obj := tree.Info.Defs[n]
+				if n.Name == "_" || obj == nil || !isErrorType(obj.Type()) {
+					continue
+				}
+				value := specValue(valueSpec, i)
+				var lateInit bool
+				if v, ok := obj.(*types.Var); ok && value == nil {
+					if assign := tree.Pkg.lateInits()[v]; assign != nil {
+						value, lateInit = assignedValue(tree.Info, assign, v), true
+					}
+				}
+				def := Def{
+					ErrorType:       ErrorTypeSentinel,
+					ExportType:      expType(n),
+					ImportPath:      tree.Pkg.PkgPath,
+					PackageName:     tree.Pkg.Name,
+					Name:            n.Name,
+					BackingTypeName: valueTypeName(obj.Type(), obj.Pkg()),
+					Position:        tree.Pkg.Fset.Position(n.Pos()),
+					Doc:             docText(genDecl, valueSpec.Doc),
+					InstanceOf:      instanceOf(obj.Type()),
+					LateInit:        lateInit,
+				}
+				init := initSentinel(tree.Info, value)
+				def.Initializer, def.Call, def.Message = init.Func, init.Call, init.Message
+				def.Wrapping, def.Wraps, def.WrapTypes = init.Wrapping, init.Wraps, init.WrapTypes
+				def.GRPCCode, def.Helper = init.GRPCCode, init.Helper
+				if c, ok := obj.(*types.Const); ok {
+					def.Const = true
+					if isStringError(tree.Pkg, c.Type()) {
+						def.Message = constant.StringVal(c.Val())
+					}
+				}
+				def.ShadowsStdlib = shadowedStdlib(def.ImportPath, def.Name)
+				def.Deprecated, def.Deprecation = deprecation(def.Doc)
+				def.Retryable = retryability(tree.Pkg, nil, def.Name, def.Doc, def.Wraps)
+				def.Stack = capturesStackCall(def.Initializer)
+				if !yield(def) {
+					return
+				}
+			}
+		}
+	}
+}
+
+func extractStructured(tree searchTree) iter.Seq[Def] {
+	return func(yield func(Def) bool) {
+		genDecl, ok := tree.Decl.(*ast.GenDecl)
+		if !ok {
+			return
+		}
+		for _, s := range genDecl.Specs {
+			typeSpec, ok := s.(*ast.TypeSpec)
+			if !ok || typeSpec.Assign.IsValid() {
+				continue
+			}
+			tn := tree.Info.Defs[typeSpec.Name].(*types.TypeName)
+			receiver := receiverKind(tn.Type())
+			if receiver == "" {
+				continue
+			}
+			unwrap, result := unwrapMethod(tn)
+			def := Def{
+				ErrorType:       ErrorTypeStructured,
+				ExportType:      expType(typeSpec.Name),
+				ImportPath:      tree.Pkg.PkgPath,
+				PackageName:     tree.Pkg.Name,
+				Name:            typeSpec.Name.Name,
+				BackingTypeName: genericName(tn.Type()),
+				Position:        tree.Pkg.Fset.Position(typeSpec.Name.Pos()),
+				Doc:             docText(genDecl, typeSpec.Doc),
+				Message:         errorMessage(tree.Pkg, tn.Type()),
+				ReceiverKind:    receiver,
+				Unwrap:          result,
+				Is:              hasMethod(tn, "Is", isSignature),
+				As:              hasMethod(tn, "As", asSignature),
+				Embeds:          embeddedErrors(tn.Type()),
+				Embedding:       embeddingChains(tn.Type()),
+				Wraps:           unwrapTargets(tree.Pkg, unwrap),
+				Fields:          exportedFields(tn.Type()),
+			}
+			def.Deprecated, def.Deprecation = deprecation(def.Doc)
+			def.Retryable = retryability(tree.Pkg, tn, def.Name, def.Doc, def.Wraps)
+			def.Stack = capturesStack(tn.Type())
+			if !yield(def) {
+				return
+			}
+		}
+	}
+}
+
+// interfaceMethods describes the methods of the interface t other than Error,
+// such as "Temporary() bool". It reports false if t is not an interface that
+// embeds error or is a constraint, which cannot be the type of an error.
+func interfaceMethods(t types.Type) ([]string, bool) {
+	iface, ok := t.Underlying().(*types.Interface)
+	if !ok || !iface.IsMethodSet() || !isErrorType(t) {
+		return nil, false
+	}
+	var methods []string
+	for i := range iface.NumMethods() {
+		m := iface.Method(i)
+		if m.Name() == "Error" {
+			continue
+		}
+		methods = append(methods, m.Name()+strings.TrimPrefix(types.TypeString(m.Signature(), nil), "func"))
+	}
+	return methods, true
+}
+
+func extractInterfaces(tree searchTree) iter.Seq[Def] {
+	return func(yield func(Def) bool) {
+		genDecl, ok := tree.Decl.(*ast.GenDecl)
+		if !ok {
+			return
+		}
+		for _, s := range genDecl.Specs {
+			typeSpec, ok := s.(*ast.TypeSpec)
+			if !ok || typeSpec.Assign.IsValid() {
+				continue
+			}
+			tn := tree.Info.Defs[typeSpec.Name].(*types.TypeName)
+			methods, ok := interfaceMethods(tn.Type())
+			if !ok {
+				continue
+			}
+			def := Def{
+				ErrorType:       ErrorTypeInterface,
+				ExportType:      expType(typeSpec.Name),
+				ImportPath:      tree.Pkg.PkgPath,
+				PackageName:     tree.Pkg.Name,
+				Name:            typeSpec.Name.Name,
+				BackingTypeName: genericName(tn.Type()),
+				Position:        tree.Pkg.Fset.Position(typeSpec.Name.Pos()),
+				Doc:             docText(genDecl, typeSpec.Doc),
+				Methods:         methods,
+			}
+			def.Deprecated, def.Deprecation = deprecation(def.Doc)
+			if !yield(def) {
+				return
+			}
+		}
+	}
+}
+
+func extractAliases(tree searchTree) iter.Seq[Def] {
+	return func(yield func(Def) bool) {
+		genDecl, ok := tree.Decl.(*ast.GenDecl)
+		if !ok {
+			return
+		}
+		for _, s := range genDecl.Specs {
+			typeSpec, ok := s.(*ast.TypeSpec)
+			if !ok || !typeSpec.Assign.IsValid() {
+				continue
+			}
+			tn, ok := tree.Info.Defs[typeSpec.Name].(*types.TypeName)
+			if !ok {
+				continue
+			}
+			t := types.Unalias(tn.Type())
+			if receiverKind(t) == "" && !isErrorType(t) {
+				continue
+			}
+			def := Def{
+				ErrorType:       ErrorTypeAlias,
+				ExportType:      expType(typeSpec.Name),
+				ImportPath:      tree.Pkg.PkgPath,
+				PackageName:     tree.Pkg.Name,
+				Name:            typeSpec.Name.Name,
+				BackingTypeName: t.String(),
+				Position:        tree.Pkg.Fset.Position(typeSpec.Name.Pos()),
+				Doc:             docText(genDecl, typeSpec.Doc),
+				ReceiverKind:    receiverKind(t),
+				AliasOf:         typeNode(t),
+			}
+			def.Deprecated, def.Deprecation = deprecation(def.Doc)
+			if !yield(def) {
+				return
+			}
+		}
+	}
+}
+
+// constructedType reports the concrete error type that fd, a function with a
+// single result, constructs: the type of the result itself or, if that is an
+// interface such as error, the one concrete type that every non-nil return
+// statement yields.
+func constructedType(info *types.Info, fd *ast.FuncDecl, result types.Type) types.Type {
+	if !types.IsInterface(result) {
+		return result
+	}
+	if fd.Body == nil {
+		return nil
+	}
+	var constructed types.Type
+	ambiguous := false
+	ast.Inspect(fd.Body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.ReturnStmt:
+			if len(n.Results) != 1 {
+				return true
+			}
+			t := info.TypeOf(n.Results[0])
+			switch {
+			case t == nil || types.Identical(t, types.Typ[types.UntypedNil]):
+			case types.IsInterface(t) || constructed != nil && !types.Identical(t, constructed):
+				ambiguous = true
+			default:
+				constructed = t
+			}
+		}
+		return true
+	})
+	if ambiguous {
+		return nil
+	}
+	return constructed
+}
+
+// constructorName reports whether name is that of a constructor by
+// convention: New or Make, or either followed by an upper-case letter, in
+// either case.
+func constructorName(name string) bool {
+	for _, prefix := range []string{"New", "new", "Make", "make"} {
+		rest, ok := strings.CutPrefix(name, prefix)
+		if r, _ := utf8.DecodeRuneInString(rest); ok && (rest == "" || unicode.IsUpper(r)) {
+			return true
+		}
+	}
+	return false
+}
+
+// returnsLiteral reports whether a return statement of fd yields a composite
+// literal of type t or, for a pointer type, the address of one.
+func returnsLiteral(info *types.Info, fd *ast.FuncDecl, t types.Type) bool {
+	if fd.Body == nil {
+		return false
+	}
+	found := false
+	ast.Inspect(fd.Body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.ReturnStmt:
+			if len(n.Results) != 1 {
+				return true
+			}
+			x := ast.Unparen(n.Results[0])
+			if u, ok := x.(*ast.UnaryExpr); ok && u.Op == token.AND {
+				x = ast.Unparen(u.X)
+			}
+			if _, ok := x.(*ast.CompositeLit); ok {
+				if rt := info.TypeOf(n.Results[0]); rt != nil && types.Identical(rt, t) {
+					found = true
+				}
+			}
+		}
+		return !found
+	})
+	return found
+}
+
+func extractConstructors(tree searchTree) iter.Seq[Def] {
+	return func(yield func(Def) bool) {
+		fd, ok := tree.Decl.(*ast.FuncDecl)
+		if !ok || fd.Recv != nil {
+			return
+		}
+		fn, ok := tree.Info.Defs[fd.Name].(*types.Func)
+		if !ok {
+			return
+		}
+		results := fn.Signature().Results()
+		if results.Len() != 1 || !isErrorType(results.At(0).Type()) {
+			return
+		}
+		t := constructedType(tree.Info, fd, results.At(0).Type())
+		if t == nil || !constructorName(fd.Name.Name) && !returnsLiteral(tree.Info, fd, t) {
+			return
+		}
+		def := Def{
+			ErrorType:       ErrorTypeConstructor,
+			ExportType:      expType(fd.Name),
+			ImportPath:      tree.Pkg.PkgPath,
+			PackageName:     tree.Pkg.Name,
+			Name:            fd.Name.Name,
+			BackingTypeName: valueTypeName(t, fn.Pkg()),
+			Position:        tree.Pkg.Fset.Position(fd.Name.Pos()),
+			Doc:             fd.Doc.Text(),
+			InstanceOf:      instanceOf(t),
+		}
+		def.Deprecated, def.Deprecation = deprecation(def.Doc)
+		yield(def)
+	}
+}
@@ -0,0 +1,77 @@
+//go:build ignore
+
+// gen_stdlib writes stdlib.go, the table of the standard library's exported
+// sentinels by name that ShadowsStdlib is computed from.
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/matttproud/errorfinder"
+)
+
+func main() {
+	cfg := errorfinder.Config{Kinds: []errorfinder.ErrorType{errorfinder.ErrorTypeSentinel}, ExportedOnly: true}
+	res, err := errorfinder.Find(context.Background(), cfg, "std")
+	if err != nil {
+		log.Fatal(err)
+	}
+	byName := make(map[string][]string)
+	for _, d := range res.Defs {
+		if hidden(d.ImportPath) {
+			continue
+		}
+		byName[d.Name] = append(byName[d.Name], d.ImportPath+"."+d.Name)
+	}
+	var buf bytes.Buffer
+	fmt.Fprintln(&buf, `// Code generated by "go run gen_stdlib.go"; DO NOT EDIT.`)
+	fmt.Fprintln(&buf)
+	fmt.Fprintln(&buf, "package errorfinder")
+	fmt.Fprintln(&buf)
+	fmt.Fprintln(&buf, "// stdlibSentinels maps the names of the standard library's exported")
+	fmt.Fprintln(&buf, "// sentinels to those declaring them, named by package path and name.")
+	fmt.Fprintln(&buf, "var stdlibSentinels = map[string][]string{")
+	names := make([]string, 0, len(byName))
+	for name := range byName {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		defs := byName[name]
+		slices.Sort(defs)
+		fmt.Fprintf(&buf, "\t%q: {", name)
+		for i, def := range defs {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			fmt.Fprintf(&buf, "%q", def)
+		}
+		fmt.Fprintln(&buf, "},")
+	}
+	fmt.Fprintln(&buf, "}")
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile("stdlib.go", src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// hidden reports whether the package at path cannot be imported by users of
+// the standard library.
+func hidden(path string) bool {
+	for _, elem := range strings.Split(path, "/") {
+		if elem == "internal" || elem == "vendor" {
+			return true
+		}
+	}
+	return false
+}
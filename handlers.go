@@ -0,0 +1,145 @@
+package errorfinder
+
+import (
+	"go/ast"
+	"go/types"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// handlerErrors reports for each error, named by package path and name, the
+// HTTP handlers declared in pkgs that may write it to their responses, by
+// their full names in sorted order. Handlers are the functions and methods,
+// such as ServeHTTP, with the signature of an http.HandlerFunc. A handler
+// writes the errors that it passes to http.Error, such as in
+// ErrNotFound.Error(), or alongside its http.ResponseWriter to another
+// function, and the errors that it or the functions of its package to which
+// it passes errors, such as those mapping errors to status codes, match with
+// errors.Is or ==.
+func handlerErrors(pkgs []*packages.Package) map[string][]string {
+	handlers := make(map[string][]string)
+	for _, pkg := range pkgs {
+		if pkg.TypesInfo == nil {
+			continue
+		}
+		decls := make(map[*types.Func]*ast.FuncDecl)
+		for _, file := range pkg.Syntax {
+			for _, decl := range file.Decls {
+				if fd, ok := decl.(*ast.FuncDecl); ok && fd.Body != nil {
+					if fn, ok := pkg.TypesInfo.Defs[fd.Name].(*types.Func); ok {
+						decls[fn] = fd
+					}
+				}
+			}
+		}
+		for fn := range decls {
+			if !isHandlerFunc(fn.Type().(*types.Signature)) {
+				continue
+			}
+			for _, name := range writtenErrors(pkg.TypesInfo, decls, fn) {
+				handlers[name] = append(handlers[name], fn.FullName())
+			}
+		}
+	}
+	for name, fns := range handlers {
+		slices.Sort(fns)
+		handlers[name] = slices.Compact(fns)
+	}
+	delete(handlers, "")
+	return handlers
+}
+
+// writtenErrors reports the errors that the handler fn may write to its
+// response, as described for handlerErrors, following the functions of the
+// package in decls to which errors are passed.
+func writtenErrors(info *types.Info, decls map[*types.Func]*ast.FuncDecl, fn *types.Func) []string {
+	var names []string
+	seen := make(map[*types.Func]bool)
+	queue := []*types.Func{fn}
+	for len(queue) > 0 {
+		fn := queue[len(queue)-1]
+		queue = queue[:len(queue)-1]
+		if seen[fn] {
+			continue
+		}
+		seen[fn] = true
+		ast.Inspect(decls[fn].Body, func(n ast.Node) bool {
+			for _, site := range isTargets(info, n) {
+				names = append(names, site.Target)
+			}
+			for _, site := range comparedErrors(info, n) {
+				names = append(names, site.Target)
+			}
+			call, ok := n.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			callee := typeutil.StaticCallee(info, call)
+			if isFunc(callee, "net/http", "Error") {
+				for _, arg := range call.Args {
+					ast.Inspect(arg, func(n ast.Node) bool {
+						if x, ok := n.(ast.Expr); ok {
+							if t := info.TypeOf(x); t != nil && isErrorType(t) {
+								names = append(names, errorTarget(info, x))
+							}
+						}
+						return true
+					})
+				}
+				return true
+			}
+			var errs []ast.Expr
+			writer := false
+			for _, arg := range call.Args {
+				switch t := info.TypeOf(arg); {
+				case t == nil:
+				case isHTTPType(t, "ResponseWriter"):
+					writer = true
+				case isErrorType(t):
+					errs = append(errs, arg)
+				}
+			}
+			if len(errs) == 0 {
+				return true
+			}
+			if writer {
+				for _, arg := range errs {
+					names = append(names, errorTarget(info, arg))
+				}
+			}
+			if _, ok := decls[callee]; ok {
+				queue = append(queue, callee)
+			}
+			return true
+		})
+	}
+	return names
+}
+
+// isHandlerFunc reports whether sig is that of an http.HandlerFunc.
+func isHandlerFunc(sig *types.Signature) bool {
+	params := sig.Params()
+	return params.Len() == 2 && sig.Results().Len() == 0 &&
+		isHTTPType(params.At(0).Type(), "ResponseWriter") &&
+		isHTTPType(params.At(1).Type(), "*Request")
+}
+
+// isHTTPType reports whether t is the net/http type of the given name, with a
+// leading "*" for a pointer to it.
+func isHTTPType(t types.Type, name string) bool {
+	if ptr, ok := t.(*types.Pointer); ok {
+		if name, ok = strings.CutPrefix(name, "*"); !ok {
+			return false
+		}
+		t = ptr.Elem()
+	}
+	named, ok := t.(*types.Named)
+	if !ok {
+		return false
+	}
+	obj := named.Obj()
+	return obj.Pkg() != nil && obj.Pkg().Path() == "net/http" && obj.Name() == name
+}
@@ -0,0 +1,70 @@
+package errorfinder
+
+import (
+	"go/types"
+	"slices"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// interfaceErrors reports for each error, named by package path and name, the
+// methods of the interfaces declared in pkgs that may return it, by their
+// full names in sorted order, such as "(example.com/p.Store).Get". A method
+// may return the errors that the methods implementing it in the types
+// declared in pkgs return, as found by transitiveReturns.
+func interfaceErrors(pkgs []*packages.Package) map[string][]string {
+	returns, callees := allFuncReturns(pkgs)
+	var ifaces, concrete []*types.TypeName
+	for _, pkg := range pkgs {
+		if pkg.TypesInfo == nil {
+			continue
+		}
+		scope := pkg.Types.Scope()
+		for _, name := range scope.Names() {
+			tn, ok := scope.Lookup(name).(*types.TypeName)
+			if !ok || tn.IsAlias() {
+				continue
+			}
+			if named, ok := tn.Type().(*types.Named); !ok || named.TypeParams().Len() > 0 {
+				continue // Such as unsafe.Pointer, which is not a named type.
+			}
+			if types.IsInterface(tn.Type()) {
+				ifaces = append(ifaces, tn)
+			} else {
+				concrete = append(concrete, tn)
+			}
+		}
+	}
+	methods := make(map[string][]string)
+	for _, iface := range ifaces {
+		it := iface.Type().Underlying().(*types.Interface)
+		for _, impl := range concrete {
+			t := types.Type(impl.Type())
+			if !types.Implements(t, it) {
+				if t = types.NewPointer(t); !types.Implements(t, it) {
+					continue
+				}
+			}
+			for i := range it.NumMethods() {
+				m := it.Method(i)
+				if !returnsError(m.Type().(*types.Signature)) {
+					continue
+				}
+				obj, _, _ := types.LookupFieldOrMethod(t, true, m.Pkg(), m.Name())
+				fn, ok := obj.(*types.Func)
+				if !ok {
+					continue
+				}
+				for _, name := range transitiveReturns([]*types.Func{fn}, returns, callees) {
+					methods[name] = append(methods[name], m.FullName())
+				}
+			}
+		}
+	}
+	for name, fns := range methods {
+		slices.Sort(fns)
+		methods[name] = slices.Compact(fns)
+	}
+	delete(methods, "")
+	return methods
+}
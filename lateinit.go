@@ -0,0 +1,80 @@
+package errorfinder
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+)
+
+// lateInits reports the assignments that initialize the package-level
+// variables of files declared without a value: for each, the first statement
+// at the top level of an init function's body that assigns to it.
+func lateInits(files []*ast.File, info *types.Info) map[*types.Var]*ast.AssignStmt {
+	inits := make(map[*types.Var]*ast.AssignStmt)
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok || genDecl.Tok != token.VAR {
+				continue
+			}
+			for _, spec := range genDecl.Specs {
+				spec := spec.(*ast.ValueSpec)
+				if len(spec.Values) > 0 {
+					continue
+				}
+				for _, n := range spec.Names {
+					if v, ok := info.Defs[n].(*types.Var); ok {
+						inits[v] = nil
+					}
+				}
+			}
+		}
+	}
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			fd, ok := decl.(*ast.FuncDecl)
+			if !ok || fd.Recv != nil || fd.Name.Name != "init" || fd.Body == nil {
+				continue
+			}
+			for _, stmt := range fd.Body.List {
+				assign, ok := stmt.(*ast.AssignStmt)
+				if !ok || assign.Tok != token.ASSIGN {
+					continue
+				}
+				for _, lhs := range assign.Lhs {
+					id, ok := ast.Unparen(lhs).(*ast.Ident)
+					if !ok {
+						continue
+					}
+					v, ok := info.Uses[id].(*types.Var)
+					if stmt, known := inits[v]; ok && known && stmt == nil {
+						inits[v] = assign
+					}
+				}
+			}
+		}
+	}
+	for v, stmt := range inits {
+		if stmt == nil {
+			delete(inits, v)
+		}
+	}
+	return inits
+}
+
+// assignedValue returns the expression that assign assigns to v: its own
+// value or the one call returning the values of all of its left-hand sides.
+func assignedValue(info *types.Info, assign *ast.AssignStmt, v *types.Var) ast.Expr {
+	for i, lhs := range assign.Lhs {
+		if id, ok := ast.Unparen(lhs).(*ast.Ident); !ok || info.Uses[id] != v {
+			continue
+		}
+		switch len(assign.Rhs) {
+		case len(assign.Lhs):
+			return assign.Rhs[i]
+		case 1:
+			return assign.Rhs[0]
+		}
+	}
+	return nil
+}
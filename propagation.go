@@ -0,0 +1,94 @@
+package errorfinder
+
+import (
+	"go/types"
+	"slices"
+
+	"golang.org/x/tools/go/callgraph"
+	"golang.org/x/tools/go/callgraph/cha"
+	"golang.org/x/tools/go/packages"
+	"golang.org/x/tools/go/ssa"
+	"golang.org/x/tools/go/ssa/ssautil"
+)
+
+// entryPoints maps the errors, named by package path and name, that may
+// propagate to the exported functions and exported methods of exported types
+// of pkgs to the full names of those entry points in sorted order. An error
+// propagates to an entry point returning an error if the entry point or a
+// function it may call, through calls to functions that return errors too,
+// returns it. The calls are those of the call graph found by class hierarchy
+// analysis, so calls of interface methods reach every implementation.
+func entryPoints(pkgs []*packages.Package) map[string][]string {
+	prog, _ := ssautil.Packages(pkgs, ssa.InstantiateGenerics)
+	prog.Build()
+	graph := cha.CallGraph(prog)
+	returns := make(map[*types.Func][]string)
+	for _, pkg := range pkgs {
+		if pkg.TypesInfo == nil || pkg.IllTyped {
+			continue
+		}
+		r, _ := funcReturns(pkg)
+		for fn, names := range r {
+			returns[fn] = append(returns[fn], names...)
+		}
+	}
+	scanned := make(map[*types.Package]bool, len(pkgs))
+	for _, pkg := range pkgs {
+		scanned[pkg.Types] = true
+	}
+	entries := make(map[string][]string)
+	for fn, node := range graph.Nodes {
+		if fn == nil {
+			continue // The root of the graph.
+		}
+		obj, ok := fn.Object().(*types.Func)
+		if !ok || fn.Synthetic != "" || !scanned[obj.Pkg()] || !isExportedFunc(obj) || !returnsError(fn.Signature) {
+			continue
+		}
+		for name := range propagated(node, returns) {
+			entries[name] = append(entries[name], obj.FullName())
+		}
+	}
+	for name, fns := range entries {
+		slices.Sort(fns)
+		entries[name] = slices.Compact(fns) // Instantiations share a name.
+	}
+	return entries
+}
+
+// propagated reports the errors that the functions reachable from node
+// through calls to functions returning errors return.
+func propagated(node *callgraph.Node, returns map[*types.Func][]string) map[string]bool {
+	errs := make(map[string]bool)
+	seen := map[*callgraph.Node]bool{node: true}
+	stack := []*callgraph.Node{node}
+	for len(stack) > 0 {
+		n := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if obj, ok := n.Func.Object().(*types.Func); ok {
+			for _, name := range returns[obj] {
+				errs[name] = true
+			}
+		}
+		for _, edge := range n.Out {
+			if callee := edge.Callee; !seen[callee] && returnsError(callee.Func.Signature) {
+				seen[callee] = true
+				stack = append(stack, callee)
+			}
+		}
+	}
+	delete(errs, "")
+	return errs
+}
+
+// returnsError reports whether a function of signature sig has a result that
+// is an error.
+func returnsError(sig *types.Signature) bool {
+	results := sig.Results()
+	for i := range results.Len() {
+		if isErrorType(results.At(i).Type()) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,212 @@
+package errorfinder
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"slices"
+
+	"golang.org/x/tools/go/packages"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// The reach of a def, as reported by Def.Reach.
+const (
+	reachExternal = "external"
+	reachInternal = "internal"
+)
+
+// externalErrors reports the errors, named by package path and name, that can
+// reach callers outside the packages of pkgs besides the exported ones: those
+// in the results of the exported functions and of the exported methods of
+// exported types, those that these functions return, directly, wrapped, or
+// as the result of a call to another function of the package returning
+// them, and those that exported sentinels wrap. Errors passed along in
+// variables are not followed, so the result is a best effort.
+func externalErrors(pkgs []*packages.Package) map[string]bool {
+	external := make(map[string]bool)
+	for _, pkg := range pkgs {
+		if pkg.TypesInfo == nil {
+			continue
+		}
+		returns, callees := funcReturns(pkg)
+		var exported []*types.Func
+		for _, file := range pkg.Syntax {
+			for _, decl := range file.Decls {
+				switch decl := decl.(type) {
+				case *ast.GenDecl:
+					if decl.Tok == token.VAR {
+						for _, name := range exportedWraps(pkg.TypesInfo, decl) {
+							external[name] = true
+						}
+					}
+				case *ast.FuncDecl:
+					fn, ok := pkg.TypesInfo.Defs[decl.Name].(*types.Func)
+					if !ok || !isExportedFunc(fn) {
+						continue
+					}
+					exported = append(exported, fn)
+					results := fn.Type().(*types.Signature).Results()
+					for i := range results.Len() {
+						if t := results.At(i).Type(); isErrorType(t) {
+							external[typeNode(t)] = true
+						}
+					}
+				}
+			}
+		}
+		for _, name := range transitiveReturns(exported, returns, callees) {
+			external[name] = true
+		}
+	}
+	delete(external, "")
+	return external
+}
+
+// returningFuncs reports for each error, named by package path and name, the
+// exported functions and methods of pkgs whose return statements return it,
+// directly or wrapped, by their full names in sorted order.
+func returningFuncs(pkgs []*packages.Package) map[string][]string {
+	funcs := make(map[string][]string)
+	for _, pkg := range pkgs {
+		if pkg.TypesInfo == nil {
+			continue
+		}
+		returns, _ := funcReturns(pkg)
+		for fn, names := range returns {
+			if !isExportedFunc(fn) {
+				continue
+			}
+			for _, name := range names {
+				funcs[name] = append(funcs[name], fn.FullName())
+			}
+		}
+	}
+	for name, fns := range funcs {
+		slices.Sort(fns)
+		funcs[name] = slices.Compact(fns)
+	}
+	return funcs
+}
+
+// transitiveReturns reports the errors that fns return, as recorded by
+// funcReturns, directly or through the functions whose results they return.
+func transitiveReturns(fns []*types.Func, returns map[*types.Func][]string, callees map[*types.Func][]*types.Func) []string {
+	var names []string
+	seen := make(map[*types.Func]bool)
+	for len(fns) > 0 {
+		fn := fns[len(fns)-1]
+		fns = fns[:len(fns)-1]
+		if seen[fn] {
+			continue
+		}
+		seen[fn] = true
+		names = append(names, returns[fn]...)
+		fns = append(fns, callees[fn]...)
+	}
+	return names
+}
+
+// allFuncReturns merges the results of funcReturns for each of pkgs.
+func allFuncReturns(pkgs []*packages.Package) (returns map[*types.Func][]string, callees map[*types.Func][]*types.Func) {
+	returns = make(map[*types.Func][]string)
+	callees = make(map[*types.Func][]*types.Func)
+	for _, pkg := range pkgs {
+		if pkg.TypesInfo == nil {
+			continue
+		}
+		r, c := funcReturns(pkg)
+		for fn, names := range r {
+			returns[fn] = append(returns[fn], names...)
+		}
+		for fn, fns := range c {
+			callees[fn] = append(callees[fn], fns...)
+		}
+	}
+	return returns, callees
+}
+
+// funcReturns records for each function declared in pkg the errors, named by
+// package path and name, that its return statements return, directly or
+// wrapped, and the functions of the package whose results they return.
+func funcReturns(pkg *packages.Package) (returns map[*types.Func][]string, callees map[*types.Func][]*types.Func) {
+	returns = make(map[*types.Func][]string)
+	callees = make(map[*types.Func][]*types.Func)
+	for _, file := range pkg.Syntax {
+		for _, decl := range file.Decls {
+			fd, ok := decl.(*ast.FuncDecl)
+			if !ok || fd.Body == nil {
+				continue
+			}
+			fn, ok := pkg.TypesInfo.Defs[fd.Name].(*types.Func)
+			if !ok {
+				continue
+			}
+			ast.Inspect(fd.Body, func(n ast.Node) bool {
+				switch n := n.(type) {
+				case *ast.FuncLit:
+					return false // Its returns are not the function's.
+				case *ast.ReturnStmt:
+					for _, site := range returnedErrors(pkg.TypesInfo, n) {
+						returns[fn] = append(returns[fn], site.Target)
+					}
+					for _, r := range n.Results {
+						for _, site := range wrappedErrors(pkg.TypesInfo, ast.Unparen(r)) {
+							returns[fn] = append(returns[fn], site.Target)
+						}
+						call, ok := ast.Unparen(r).(*ast.CallExpr)
+						if !ok {
+							continue
+						}
+						if callee := typeutil.StaticCallee(pkg.TypesInfo, call); callee != nil && callee.Pkg() == pkg.Types {
+							callees[fn] = append(callees[fn], callee)
+						}
+					}
+				}
+				return true
+			})
+		}
+	}
+	return returns, callees
+}
+
+// isExportedFunc reports whether fn is an exported function or an exported
+// method of an exported type.
+func isExportedFunc(fn *types.Func) bool {
+	if !fn.Exported() {
+		return false
+	}
+	recv := fn.Type().(*types.Signature).Recv()
+	if recv == nil {
+		return true
+	}
+	t := recv.Type()
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	named, ok := t.(*types.Named)
+	return ok && named.Obj().Exported()
+}
+
+// exportedWraps reports the errors that the exported sentinels declared by
+// decl are or wrap.
+func exportedWraps(info *types.Info, decl *ast.GenDecl) []string {
+	var names []string
+	for _, spec := range decl.Specs {
+		vs := spec.(*ast.ValueSpec)
+		for i, n := range vs.Names {
+			value := specValue(vs, i)
+			if !n.IsExported() || value == nil {
+				continue
+			}
+			names = append(names, errorTarget(info, value))
+			ast.Inspect(value, func(n ast.Node) bool {
+				for _, site := range wrappedErrors(info, n) {
+					names = append(names, site.Target)
+				}
+				return true
+			})
+		}
+	}
+	return names
+}
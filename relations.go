@@ -0,0 +1,612 @@
+package errorfinder
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// typeNode names t for the purposes of relating defs to one another. Named
+// types are identified by their package path and name, which matches the
+// naming of the defs themselves.
+func typeNode(t types.Type) string {
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	switch t := t.(type) {
+	case *types.Named:
+		return objectNode(t.Obj())
+	case *types.Alias:
+		return objectNode(t.Obj())
+	}
+	return types.TypeString(t, nil)
+}
+
+func objectNode(obj types.Object) string {
+	if obj.Pkg() == nil {
+		return obj.Name()
+	}
+	return obj.Pkg().Path() + "." + obj.Name()
+}
+
+// genericName renders the generic type t with the names of its type
+// parameters but not their constraints, such as "p.WrapError[T]". Other types
+// are rendered as usual.
+func genericName(t types.Type) string {
+	named, ok := t.(*types.Named)
+	if !ok || named.TypeParams().Len() == 0 {
+		return t.String()
+	}
+	names := make([]string, named.TypeParams().Len())
+	for i := range names {
+		names[i] = named.TypeParams().At(i).Obj().Name()
+	}
+	return objectNode(named.Obj()) + "[" + strings.Join(names, ", ") + "]"
+}
+
+// hasTypeParam reports whether t is or is composed of type parameters, as in
+// an instantiation within a generic function.
+func hasTypeParam(t types.Type) bool {
+	switch t := t.(type) {
+	case *types.TypeParam:
+		return true
+	case *types.Pointer:
+		return hasTypeParam(t.Elem())
+	case *types.Slice:
+		return hasTypeParam(t.Elem())
+	case *types.Array:
+		return hasTypeParam(t.Elem())
+	case *types.Chan:
+		return hasTypeParam(t.Elem())
+	case *types.Map:
+		return hasTypeParam(t.Key()) || hasTypeParam(t.Elem())
+	case *types.Named:
+		for i := range t.TypeArgs().Len() {
+			if hasTypeParam(t.TypeArgs().At(i)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// instantiations maps the generic error types instantiated in pkgs, named as
+// by objectNode, to their distinct instantiations in sorted order.
+// Instantiations with type parameters as arguments are omitted.
+func instantiations(pkgs []*packages.Package) map[string][]string {
+	instances := make(map[string][]string)
+	for _, pkg := range pkgs {
+		if pkg.TypesInfo == nil {
+			continue
+		}
+		for _, inst := range pkg.TypesInfo.Instances {
+			named, ok := inst.Type.(*types.Named)
+			if !ok || hasTypeParam(named) || receiverKind(named) == "" {
+				continue
+			}
+			key := objectNode(named.Origin().Obj())
+			if s := named.String(); !slices.Contains(instances[key], s) {
+				instances[key] = append(instances[key], s)
+			}
+		}
+	}
+	for _, s := range instances {
+		slices.Sort(s)
+	}
+	return instances
+}
+
+// A chainResolver follows what the defs of a program wrap, named as by
+// objectNode, through the packages that declare them.
+type chainResolver struct {
+	pkgs  map[string]*packages.Package // By import path.
+	srcs  map[string]*source           // Memoizes the sources of pkgs.
+	wraps map[string][]string          // Memoizes wrapped.
+}
+
+func newChainResolver(pkgs []*packages.Package) *chainResolver {
+	r := &chainResolver{pkgs: make(map[string]*packages.Package), srcs: make(map[string]*source), wraps: make(map[string][]string)}
+	packages.Visit(pkgs, nil, func(pkg *packages.Package) {
+		if !brokenPackage(pkg) && pkg.Types != nil {
+			r.pkgs[pkg.PkgPath] = pkg
+		}
+	})
+	return r
+}
+
+// wrapped reports what the error type or sentinel node wraps, as Def.Wraps
+// does.
+func (r *chainResolver) wrapped(node string) []string {
+	if wraps, ok := r.wraps[node]; ok {
+		return wraps
+	}
+	var wraps []string
+	if i := strings.LastIndex(node, "."); i > 0 && r.pkgs[node[:i]] != nil {
+		pkg, src := r.pkgs[node[:i]], r.srcs[node[:i]]
+		if src == nil {
+			src = packageSource(pkg)
+			r.srcs[node[:i]] = src
+		}
+		switch obj := pkg.Types.Scope().Lookup(node[i+1:]).(type) {
+		case *types.TypeName:
+			fn, _ := unwrapMethod(obj)
+			wraps = unwrapTargets(src, fn)
+		case *types.Var:
+			wraps = initSentinel(src.TypesInfo, sentinelValue(src, obj)).Wraps
+		}
+	}
+	r.wraps[node] = wraps
+	return wraps
+}
+
+// chains reports the chains of errors that node wraps, directly or through
+// what those errors wrap in turn, each starting with node, such as
+// "p.ConfigError > p.ParseError > p.ErrSyntax". A chain that returns to an
+// error already in it ends with that error.
+func (r *chainResolver) chains(node string) []string {
+	var chains []string
+	var path []string
+	var walk func(node string)
+	walk = func(node string) {
+		path = append(path, node)
+		defer func() { path = path[:len(path)-1] }()
+		wraps := r.wrapped(node)
+		if len(wraps) == 0 || slices.Contains(path[:len(path)-1], node) {
+			if len(path) > 1 {
+				chains = append(chains, strings.Join(path, " > "))
+			}
+			return
+		}
+		for _, w := range wraps {
+			walk(w)
+		}
+	}
+	walk(node)
+	return chains
+}
+
+// sentinelValue finds the expression that initializes the package-level
+// variable v declared in pkg, either its own or, if it is declared without a
+// value, the one assigned to it in an init function, or nil if there is none.
+func sentinelValue(pkg *source, v *types.Var) ast.Expr {
+	for _, file := range pkg.Syntax {
+		for _, decl := range file.Decls {
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok || genDecl.Tok != token.VAR {
+				continue
+			}
+			for _, s := range genDecl.Specs {
+				valueSpec := s.(*ast.ValueSpec)
+				for i, n := range valueSpec.Names {
+					if pkg.TypesInfo.Defs[n] != v {
+						continue
+					}
+					if value := specValue(valueSpec, i); value != nil {
+						return value
+					}
+					if assign := pkg.lateInits()[v]; assign != nil {
+						return assignedValue(pkg.TypesInfo, assign, v)
+					}
+					return nil
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// panicPayloads maps the errors that the functions of pkgs panic with, named
+// as by errorTarget, to the full names of those functions in sorted order.
+// A panic with a value of static type error, other than a package-level
+// variable, is attributed to "error".
+func panicPayloads(pkgs []*packages.Package) map[string][]string {
+	payloads := make(map[string][]string)
+	for _, pkg := range pkgs {
+		if pkg.TypesInfo == nil {
+			continue
+		}
+		for _, file := range pkg.Syntax {
+			for _, decl := range file.Decls {
+				fd, ok := decl.(*ast.FuncDecl)
+				if !ok || fd.Body == nil {
+					continue
+				}
+				fn, ok := pkg.TypesInfo.Defs[fd.Name].(*types.Func)
+				if !ok {
+					continue
+				}
+				ast.Inspect(fd.Body, func(n ast.Node) bool {
+					call, ok := n.(*ast.CallExpr)
+					if !ok || len(call.Args) != 1 || !isBuiltin(pkg.TypesInfo, call.Fun, "panic") {
+						return true
+					}
+					if t := pkg.TypesInfo.TypeOf(call.Args[0]); t == nil || !isErrorType(t) {
+						return true
+					}
+					target := errorTarget(pkg.TypesInfo, call.Args[0])
+					if name := fn.FullName(); !slices.Contains(payloads[target], name) {
+						payloads[target] = append(payloads[target], name)
+					}
+					return true
+				})
+			}
+		}
+	}
+	for _, names := range payloads {
+		slices.Sort(names)
+	}
+	return payloads
+}
+
+// reassignedVars reports the package-level variables, named by package path
+// and name, that the code of pkgs assigns to or takes the address of outside
+// of their declarations and the init function assignments initializing those
+// declared without a value.
+func reassignedVars(pkgs []*packages.Package) map[string]bool {
+	written := make(map[string]bool)
+	for _, pkg := range pkgs {
+		if pkg.TypesInfo == nil {
+			continue
+		}
+		inits := lateInits(pkg.Syntax, pkg.TypesInfo)
+		write := func(expr ast.Expr) {
+			var id *ast.Ident
+			switch e := ast.Unparen(expr).(type) {
+			case *ast.Ident:
+				id = e
+			case *ast.SelectorExpr:
+				id = e.Sel
+			}
+			if v, ok := pkg.TypesInfo.Uses[id].(*types.Var); ok && v.Pkg() != nil && v.Parent() == v.Pkg().Scope() {
+				written[objectNode(v)] = true
+			}
+		}
+		for _, file := range pkg.Syntax {
+			ast.Inspect(file, func(n ast.Node) bool {
+				switch n := n.(type) {
+				case *ast.AssignStmt:
+					for _, lhs := range n.Lhs {
+						if id, ok := lhs.(*ast.Ident); ok {
+							if v, ok := pkg.TypesInfo.Uses[id].(*types.Var); ok && inits[v] == n {
+								continue
+							}
+						}
+						write(lhs)
+					}
+				case *ast.RangeStmt:
+					if n.Tok == token.ASSIGN {
+						write(n.Key)
+						write(n.Value)
+					}
+				case *ast.UnaryExpr:
+					if n.Op == token.AND {
+						write(n.X)
+					}
+				}
+				return true
+			})
+		}
+	}
+	return written
+}
+
+// The references to a package-level object: how many identifiers refer to
+// it, the import paths of the packages other than its own declaring them,
+// in sorted order, and whether any are in _test.go files.
+type references struct {
+	Count     int
+	Consumers []string
+	Tested    bool
+}
+
+// findReferences reports the references of the identifiers of pkgs to each
+// package-level object, named by package path and name. Identifiers in files
+// that several packages share, such as the test variants of packages, count
+// once.
+func findReferences(pkgs []*packages.Package) map[string]*references {
+	refs := make(map[string]*references)
+	seen := make(map[token.Position]bool)
+	for _, pkg := range pkgs {
+		if pkg.TypesInfo == nil {
+			continue
+		}
+		for id, obj := range pkg.TypesInfo.Uses {
+			if obj.Pkg() == nil || obj.Parent() != obj.Pkg().Scope() {
+				continue
+			}
+			pos := pkg.Fset.Position(id.Pos())
+			if seen[pos] {
+				continue
+			}
+			seen[pos] = true
+			r := refs[objectNode(obj)]
+			if r == nil {
+				r = new(references)
+				refs[objectNode(obj)] = r
+			}
+			r.Count++
+			r.Tested = r.Tested || isTestFile(pos.Filename)
+			if path := pkg.PkgPath; path != obj.Pkg().Path() && !slices.Contains(r.Consumers, path) {
+				r.Consumers = append(r.Consumers, path)
+			}
+		}
+	}
+	for _, r := range refs {
+		slices.Sort(r.Consumers)
+	}
+	return refs
+}
+
+// isBuiltin reports whether expr refers to the built-in function name.
+func isBuiltin(info *types.Info, expr ast.Expr, name string) bool {
+	id, ok := ast.Unparen(expr).(*ast.Ident)
+	if !ok {
+		return false
+	}
+	b, ok := info.Uses[id].(*types.Builtin)
+	return ok && b.Name() == name
+}
+
+// instanceOf reports the named type of a sentinel whose declared type is
+// something more specific than error.
+func instanceOf(t types.Type) string {
+	if types.IsInterface(t) || isAnonymous(t) {
+		return ""
+	}
+	return typeNode(t)
+}
+
+// isAnonymous reports whether t, or the type it points to, is a type literal,
+// such as struct{ error }, rather than a named type.
+func isAnonymous(t types.Type) bool {
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	switch t.(type) {
+	case *types.Named, *types.Alias, *types.Basic, *types.TypeParam:
+		return false
+	}
+	return true
+}
+
+// errorCode finds the field of the structured error type t that holds its
+// code, the first of its fields with one of names, and reports the field's
+// name and type. When the type is a named one, it also lists the constants of
+// that type declared in its package, such as "CodeNotFound=1".
+func errorCode(t types.Type, names []string) (field, typ string, codes []string) {
+	st, ok := t.Underlying().(*types.Struct)
+	if !ok {
+		return "", "", nil
+	}
+	for i := range st.NumFields() {
+		f := st.Field(i)
+		if !slices.Contains(names, f.Name()) {
+			continue
+		}
+		if named, ok := types.Unalias(f.Type()).(*types.Named); ok && named.Obj().Pkg() != nil {
+			scope := named.Obj().Pkg().Scope()
+			for _, name := range scope.Names() {
+				if c, ok := scope.Lookup(name).(*types.Const); ok && types.Identical(c.Type(), named) {
+					codes = append(codes, name+"="+c.Val().ExactString())
+				}
+			}
+		}
+		return f.Name(), f.Type().String(), codes
+	}
+	return "", "", nil
+}
+
+//go:generate go run gen_stdlib.go
+
+// shadowedStdlib reports the standard library's exported sentinels, other
+// than the sentinel named by path and name itself, that share its name.
+func shadowedStdlib(path, name string) []string {
+	var shadowed []string
+	for _, std := range stdlibSentinels[name] {
+		if std != path+"."+name {
+			shadowed = append(shadowed, std)
+		}
+	}
+	return shadowed
+}
+
+// valueTypeName renders t, the type of a value declared in pkg. Named types
+// are qualified by package path as usual, but a type literal is rendered as
+// it reads in source, with other packages named by their name, such as
+// "struct{error; Mode fs.FileMode}".
+func valueTypeName(t types.Type, pkg *types.Package) string {
+	if !isAnonymous(t) {
+		return t.String()
+	}
+	return types.TypeString(t, func(p *types.Package) string {
+		if p == pkg {
+			return ""
+		}
+		return p.Name()
+	})
+}
+
+// embedsError reports whether t, the type of an embedded field, is an error
+// or a type whose pointer is.
+func embedsError(t types.Type) bool {
+	return isErrorType(t) || isErrorType(types.NewPointer(t))
+}
+
+// embeddedErrors reports the embedded fields of t's underlying struct that are
+// themselves errors.
+func embeddedErrors(t types.Type) []string {
+	st, ok := t.Underlying().(*types.Struct)
+	if !ok {
+		return nil
+	}
+	var embeds []string
+	for i := range st.NumFields() {
+		f := st.Field(i)
+		if !f.Embedded() || !embedsError(f.Type()) {
+			continue
+		}
+		embeds = append(embeds, typeNode(f.Type()))
+	}
+	return embeds
+}
+
+// exportedFields describes the exported fields of t's underlying struct, each
+// as its name and type, such as "Op string".
+func exportedFields(t types.Type) []string {
+	st, ok := t.Underlying().(*types.Struct)
+	if !ok {
+		return nil
+	}
+	var fields []string
+	for i := range st.NumFields() {
+		if f := st.Field(i); f.Exported() {
+			fields = append(fields, f.Name()+" "+types.TypeString(f.Type(), nil))
+		}
+	}
+	return fields
+}
+
+// embeddingChains reports the errors embedded in t's underlying struct,
+// directly or through other embedded structs, each as the chain of embedded
+// types leading to it, such as "p.Base > p.CodeError".
+func embeddingChains(t types.Type) []string {
+	var chains []string
+	path := make(map[types.Type]bool) // Guards against embedding cycles.
+	var walk func(t types.Type, prefix string)
+	walk = func(t types.Type, prefix string) {
+		if ptr, ok := t.(*types.Pointer); ok {
+			t = ptr.Elem()
+		}
+		st, ok := t.Underlying().(*types.Struct)
+		if !ok || path[t] {
+			return
+		}
+		path[t] = true
+		defer delete(path, t)
+		for i := range st.NumFields() {
+			f := st.Field(i)
+			if !f.Embedded() {
+				continue
+			}
+			chain := prefix + typeNode(f.Type())
+			if embedsError(f.Type()) {
+				chains = append(chains, chain)
+			}
+			walk(f.Type(), chain+" > ")
+		}
+	}
+	walk(t, "")
+	return chains
+}
+
+// methodDecl finds the declaration of method fn in pkg.
+func methodDecl(pkg *source, fn *types.Func) *ast.FuncDecl {
+	for _, file := range pkg.Syntax {
+		for _, decl := range file.Decls {
+			fd, ok := decl.(*ast.FuncDecl)
+			if ok && fd.Recv != nil && pkg.TypesInfo.Defs[fd.Name] == fn {
+				return fd
+			}
+		}
+	}
+	return nil
+}
+
+// unwrapMethod finds the Unwrap method of the named type, if it has either
+// signature that errors.Unwrap and errors.Is recognize, and reports its result
+// type: "error" or "[]error".
+func unwrapMethod(tn *types.TypeName) (*types.Func, string) {
+	obj, _, _ := types.LookupFieldOrMethod(types.NewPointer(tn.Type()), true, tn.Pkg(), "Unwrap")
+	fn, ok := obj.(*types.Func)
+	if !ok || fn.Signature().Params().Len() != 0 || fn.Signature().Results().Len() != 1 {
+		return nil, ""
+	}
+	errorType := types.Universe.Lookup("error").Type()
+	switch t := fn.Signature().Results().At(0).Type(); {
+	case types.Identical(t, errorType):
+		return fn, "error"
+	case types.Identical(t, types.NewSlice(errorType)):
+		return fn, "[]error"
+	}
+	return nil, ""
+}
+
+var (
+	isSignature = newSignature(types.Universe.Lookup("error").Type(), types.Typ[types.Bool])
+	asSignature = newSignature(types.Universe.Lookup("any").Type(), types.Typ[types.Bool])
+)
+
+func newSignature(param, result types.Type) *types.Signature {
+	params := types.NewTuple(types.NewParam(token.NoPos, nil, "", param))
+	results := types.NewTuple(types.NewParam(token.NoPos, nil, "", result))
+	return types.NewSignatureType(nil, nil, nil, params, results, false)
+}
+
+// hasMethod reports whether the named type, or a pointer to it, has a method
+// with the given name and signature.
+func hasMethod(tn *types.TypeName, name string, sig *types.Signature) bool {
+	obj, _, _ := types.LookupFieldOrMethod(types.NewPointer(tn.Type()), true, tn.Pkg(), name)
+	fn, ok := obj.(*types.Func)
+	return ok && types.Identical(fn.Signature(), sig)
+}
+
+// unwrapTargets reports what fn, an Unwrap method declared in pkg, returns
+// when that can be determined from its return statements: the static type of
+// a returned field or expression, or the sentinel returned by name.
+func unwrapTargets(pkg *source, fn *types.Func) []string {
+	if fn == nil {
+		return nil
+	}
+	fd := methodDecl(pkg, fn)
+	if fd == nil || fd.Body == nil {
+		return nil
+	}
+	var targets []string
+	add := func(target string) {
+		if !slices.Contains(targets, target) {
+			targets = append(targets, target)
+		}
+	}
+	ast.Inspect(fd.Body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.ReturnStmt:
+			for _, res := range n.Results {
+				if target := errorTarget(pkg.TypesInfo, res); target != "" {
+					add(target)
+				}
+			}
+		}
+		return true
+	})
+	return targets
+}
+
+// errorTarget names the error that expr evaluates to for the purposes of
+// relating defs: the package-level variable it refers to by name, or else its
+// static type. It returns the empty string for nil.
+func errorTarget(info *types.Info, expr ast.Expr) string {
+	var id *ast.Ident
+	switch e := ast.Unparen(expr).(type) {
+	case *ast.Ident:
+		id = e
+	case *ast.SelectorExpr:
+		id = e.Sel
+	}
+	if v, ok := info.Uses[id].(*types.Var); ok && v.Pkg() != nil && v.Parent() == v.Pkg().Scope() {
+		return objectNode(v)
+	}
+	t := info.TypeOf(expr)
+	if t == nil || types.Identical(t, types.Typ[types.UntypedNil]) {
+		return ""
+	}
+	if sl, ok := t.(*types.Slice); ok {
+		t = sl.Elem()
+	}
+	return typeNode(t)
+}
@@ -0,0 +1,88 @@
+package errorfinder
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"strings"
+)
+
+// The retryability of a def, as reported by Def.Retryable.
+const (
+	retryYes     = "yes"
+	retryNo      = "no"
+	retryUnknown = "unknown"
+)
+
+var predicateSignature = types.NewSignatureType(nil, nil, nil, nil, types.NewTuple(types.NewParam(token.NoPos, nil, "", types.Typ[types.Bool])), false)
+
+// The words of the names of errors that suggest whether they are worth
+// retrying, such as in ErrUnavailable or NotFoundError.
+var (
+	retryableWords = []string{"Timeout", "Temporary", "Unavailable", "Busy", "Retry", "TryAgain", "Throttled", "RateLimit", "Overloaded"}
+	permanentWords = []string{"Invalid", "NotFound", "Permission", "Unauthorized", "Unauthenticated", "Forbidden", "Exists", "Malformed", "Unsupported", "Unimplemented"}
+)
+
+// retryability classifies whether an error is worth retrying, judging by the
+// first of these to tell: a doc comment stating whether it is retryable; the
+// constant result of a Temporary or Timeout method of tn, the error's type,
+// if any; its wrapping context.DeadlineExceeded or context.Canceled; and the
+// words of its name.
+func retryability(pkg *source, tn *types.TypeName, name, doc string, wraps []string) string {
+	switch doc := strings.ToLower(strings.Join(strings.Fields(doc), " ")); {
+	case strings.Contains(doc, "not retryable"), strings.Contains(doc, "non-retryable"), strings.Contains(doc, "do not retry"):
+		return retryNo
+	case strings.Contains(doc, "retryable"), strings.Contains(doc, "safe to retry"):
+		return retryYes
+	}
+	if tn != nil {
+		for _, method := range []string{"Temporary", "Timeout"} {
+			obj, _, _ := types.LookupFieldOrMethod(types.NewPointer(tn.Type()), true, tn.Pkg(), method)
+			fn, ok := obj.(*types.Func)
+			if !ok || !types.Identical(fn.Signature(), predicateSignature) {
+				continue
+			}
+			if v, ok := constantResult(pkg, fn); ok {
+				if constant.BoolVal(v) {
+					return retryYes
+				}
+				return retryNo
+			}
+		}
+	}
+	for _, w := range wraps {
+		switch w {
+		case "context.DeadlineExceeded":
+			return retryYes
+		case "context.Canceled":
+			return retryNo
+		}
+	}
+	for _, word := range retryableWords {
+		if strings.Contains(name, word) {
+			return retryYes
+		}
+	}
+	for _, word := range permanentWords {
+		if strings.Contains(name, word) {
+			return retryNo
+		}
+	}
+	return retryUnknown
+}
+
+// constantResult reports the constant that fn, a method declared in pkg,
+// returns, if its body is a single return statement of one.
+func constantResult(pkg *source, fn *types.Func) (constant.Value, bool) {
+	fd := methodDecl(pkg, fn)
+	if fd == nil || fd.Body == nil || len(fd.Body.List) != 1 {
+		return nil, false
+	}
+	ret, ok := fd.Body.List[0].(*ast.ReturnStmt)
+	if !ok || len(ret.Results) != 1 {
+		return nil, false
+	}
+	v := pkg.TypesInfo.Types[ret.Results[0]].Value
+	return v, v != nil && v.Kind() == constant.Bool
+}
@@ -0,0 +1,74 @@
+package errorfinder
+
+import (
+	"go/types"
+	"slices"
+	"strings"
+)
+
+// stackPackages are the packages whose constructors capture the stack of the
+// goroutine creating the error, as github.com/pkg/errors.WithStack does, by
+// the names of those constructors.
+var stackPackages = map[string][]string{
+	"github.com/pkg/errors":          {"New", "Errorf", "WithStack", "Wrap", "Wrapf"},
+	"github.com/go-errors/errors":    {"New", "Errorf", "Wrap", "WrapPrefix"},
+	"github.com/cockroachdb/errors":  {"New", "Newf", "Errorf", "WithStack", "Wrap", "Wrapf"},
+	"github.com/palantir/stacktrace": {"NewError", "Propagate"},
+	"gitlab.com/tozd/go/errors":      {"New", "Errorf", "WithStack", "Wrap", "Wrapf"},
+	"github.com/rotisserie/eris":     {"New", "Errorf", "Wrap", "Wrapf"},
+	"github.com/ztrue/tracerr":       {"New", "Errorf", "Wrap"},
+	"github.com/juju/errors":         {"New", "Errorf", "Trace", "Annotate", "Annotatef"},
+	"github.com/friendsofgo/errors":  {"New", "Errorf", "WithStack", "Wrap", "Wrapf"},
+}
+
+// capturesStackCall reports whether fn, a sentinel's initializer named by its
+// full name, such as "github.com/pkg/errors.New", captures a stack trace.
+func capturesStackCall(fn string) bool {
+	i := strings.LastIndex(fn, ".")
+	return i >= 0 && slices.Contains(stackPackages[fn[:i]], fn[i+1:])
+}
+
+// capturesStack reports whether t, a structured error's type, carries a
+// stack trace: whether it has a StackTrace method or its underlying struct
+// has a field, directly or through other structs it holds, of a type that
+// records one, such as runtime.Frames or the []uintptr that runtime.Callers
+// fills.
+func capturesStack(t types.Type) bool {
+	if obj, _, _ := types.LookupFieldOrMethod(types.NewPointer(t), true, nil, "StackTrace"); obj != nil {
+		if _, ok := obj.(*types.Func); ok {
+			return true
+		}
+	}
+	seen := make(map[types.Type]bool) // Guards against recursive types.
+	var walk func(t types.Type) bool
+	walk = func(t types.Type) bool {
+		switch u := t.(type) {
+		case *types.Pointer:
+			return walk(u.Elem())
+		case *types.Slice:
+			return types.Identical(u.Elem(), types.Typ[types.Uintptr]) || walk(u.Elem())
+		case *types.Array:
+			return types.Identical(u.Elem(), types.Typ[types.Uintptr]) || walk(u.Elem())
+		}
+		if n, ok := t.(*types.Named); ok && n.Obj().Pkg() != nil {
+			switch path, name := n.Obj().Pkg().Path(), n.Obj().Name(); {
+			case path == "runtime" && (name == "Frame" || name == "Frames" || name == "StackRecord"):
+				return true
+			case path != "runtime" && stackPackages[path] != nil && (name == "StackTrace" || name == "stack" || name == "Stack"):
+				return true
+			}
+		}
+		st, ok := t.Underlying().(*types.Struct)
+		if !ok || seen[t] {
+			return false
+		}
+		seen[t] = true
+		for i := range st.NumFields() {
+			if walk(st.Field(i).Type()) {
+				return true
+			}
+		}
+		return false
+	}
+	return walk(t)
+}
@@ -0,0 +1,242 @@
+// Code generated by "go run gen_stdlib.go"; DO NOT EDIT.
+
+package errorfinder
+
+// stdlibSentinels maps the names of the standard library's exported
+// sentinels to those declaring them, named by package path and name.
+var stdlibSentinels = map[string][]string{
+	"Canceled":                 {"context.Canceled"},
+	"DeadlineExceeded":         {"context.DeadlineExceeded"},
+	"E2BIG":                    {"syscall.E2BIG"},
+	"EACCES":                   {"syscall.EACCES"},
+	"EADDRINUSE":               {"syscall.EADDRINUSE"},
+	"EADDRNOTAVAIL":            {"syscall.EADDRNOTAVAIL"},
+	"EADV":                     {"syscall.EADV"},
+	"EAFNOSUPPORT":             {"syscall.EAFNOSUPPORT"},
+	"EAGAIN":                   {"syscall.EAGAIN"},
+	"EALREADY":                 {"syscall.EALREADY"},
+	"EBADE":                    {"syscall.EBADE"},
+	"EBADF":                    {"syscall.EBADF"},
+	"EBADFD":                   {"syscall.EBADFD"},
+	"EBADMSG":                  {"syscall.EBADMSG"},
+	"EBADR":                    {"syscall.EBADR"},
+	"EBADRQC":                  {"syscall.EBADRQC"},
+	"EBADSLT":                  {"syscall.EBADSLT"},
+	"EBFONT":                   {"syscall.EBFONT"},
+	"EBUSY":                    {"syscall.EBUSY"},
+	"ECANCELED":                {"syscall.ECANCELED"},
+	"ECHILD":                   {"syscall.ECHILD"},
+	"ECHRNG":                   {"syscall.ECHRNG"},
+	"ECOMM":                    {"syscall.ECOMM"},
+	"ECONNABORTED":             {"syscall.ECONNABORTED"},
+	"ECONNREFUSED":             {"syscall.ECONNREFUSED"},
+	"ECONNRESET":               {"syscall.ECONNRESET"},
+	"EDEADLK":                  {"syscall.EDEADLK"},
+	"EDEADLOCK":                {"syscall.EDEADLOCK"},
+	"EDESTADDRREQ":             {"syscall.EDESTADDRREQ"},
+	"EDOM":                     {"syscall.EDOM"},
+	"EDOTDOT":                  {"syscall.EDOTDOT"},
+	"EDQUOT":                   {"syscall.EDQUOT"},
+	"EEXIST":                   {"syscall.EEXIST"},
+	"EFAULT":                   {"syscall.EFAULT"},
+	"EFBIG":                    {"syscall.EFBIG"},
+	"EHOSTDOWN":                {"syscall.EHOSTDOWN"},
+	"EHOSTUNREACH":             {"syscall.EHOSTUNREACH"},
+	"EIDRM":                    {"syscall.EIDRM"},
+	"EILSEQ":                   {"syscall.EILSEQ"},
+	"EINPROGRESS":              {"syscall.EINPROGRESS"},
+	"EINTR":                    {"syscall.EINTR"},
+	"EINVAL":                   {"syscall.EINVAL"},
+	"EIO":                      {"syscall.EIO"},
+	"EISCONN":                  {"syscall.EISCONN"},
+	"EISDIR":                   {"syscall.EISDIR"},
+	"EISNAM":                   {"syscall.EISNAM"},
+	"EKEYEXPIRED":              {"syscall.EKEYEXPIRED"},
+	"EKEYREJECTED":             {"syscall.EKEYREJECTED"},
+	"EKEYREVOKED":              {"syscall.EKEYREVOKED"},
+	"EL2HLT":                   {"syscall.EL2HLT"},
+	"EL2NSYNC":                 {"syscall.EL2NSYNC"},
+	"EL3HLT":                   {"syscall.EL3HLT"},
+	"EL3RST":                   {"syscall.EL3RST"},
+	"ELIBACC":                  {"syscall.ELIBACC"},
+	"ELIBBAD":                  {"syscall.ELIBBAD"},
+	"ELIBEXEC":                 {"syscall.ELIBEXEC"},
+	"ELIBMAX":                  {"syscall.ELIBMAX"},
+	"ELIBSCN":                  {"syscall.ELIBSCN"},
+	"ELNRNG":                   {"syscall.ELNRNG"},
+	"ELOOP":                    {"syscall.ELOOP"},
+	"EMEDIUMTYPE":              {"syscall.EMEDIUMTYPE"},
+	"EMFILE":                   {"syscall.EMFILE"},
+	"EMLINK":                   {"syscall.EMLINK"},
+	"EMSGSIZE":                 {"syscall.EMSGSIZE"},
+	"EMULTIHOP":                {"syscall.EMULTIHOP"},
+	"ENAMETOOLONG":             {"syscall.ENAMETOOLONG"},
+	"ENAVAIL":                  {"syscall.ENAVAIL"},
+	"ENETDOWN":                 {"syscall.ENETDOWN"},
+	"ENETRESET":                {"syscall.ENETRESET"},
+	"ENETUNREACH":              {"syscall.ENETUNREACH"},
+	"ENFILE":                   {"syscall.ENFILE"},
+	"ENOANO":                   {"syscall.ENOANO"},
+	"ENOBUFS":                  {"syscall.ENOBUFS"},
+	"ENOCSI":                   {"syscall.ENOCSI"},
+	"ENODATA":                  {"syscall.ENODATA"},
+	"ENODEV":                   {"syscall.ENODEV"},
+	"ENOENT":                   {"syscall.ENOENT"},
+	"ENOEXEC":                  {"syscall.ENOEXEC"},
+	"ENOKEY":                   {"syscall.ENOKEY"},
+	"ENOLCK":                   {"syscall.ENOLCK"},
+	"ENOLINK":                  {"syscall.ENOLINK"},
+	"ENOMEDIUM":                {"syscall.ENOMEDIUM"},
+	"ENOMEM":                   {"syscall.ENOMEM"},
+	"ENOMSG":                   {"syscall.ENOMSG"},
+	"ENONET":                   {"syscall.ENONET"},
+	"ENOPKG":                   {"syscall.ENOPKG"},
+	"ENOPROTOOPT":              {"syscall.ENOPROTOOPT"},
+	"ENOSPC":                   {"syscall.ENOSPC"},
+	"ENOSR":                    {"syscall.ENOSR"},
+	"ENOSTR":                   {"syscall.ENOSTR"},
+	"ENOSYS":                   {"syscall.ENOSYS"},
+	"ENOTBLK":                  {"syscall.ENOTBLK"},
+	"ENOTCONN":                 {"syscall.ENOTCONN"},
+	"ENOTDIR":                  {"syscall.ENOTDIR"},
+	"ENOTEMPTY":                {"syscall.ENOTEMPTY"},
+	"ENOTNAM":                  {"syscall.ENOTNAM"},
+	"ENOTRECOVERABLE":          {"syscall.ENOTRECOVERABLE"},
+	"ENOTSOCK":                 {"syscall.ENOTSOCK"},
+	"ENOTSUP":                  {"syscall.ENOTSUP"},
+	"ENOTTY":                   {"syscall.ENOTTY"},
+	"ENOTUNIQ":                 {"syscall.ENOTUNIQ"},
+	"ENXIO":                    {"syscall.ENXIO"},
+	"EOF":                      {"io.EOF"},
+	"EOPNOTSUPP":               {"syscall.EOPNOTSUPP"},
+	"EOVERFLOW":                {"syscall.EOVERFLOW"},
+	"EOWNERDEAD":               {"syscall.EOWNERDEAD"},
+	"EPERM":                    {"syscall.EPERM"},
+	"EPFNOSUPPORT":             {"syscall.EPFNOSUPPORT"},
+	"EPIPE":                    {"syscall.EPIPE"},
+	"EPROTO":                   {"syscall.EPROTO"},
+	"EPROTONOSUPPORT":          {"syscall.EPROTONOSUPPORT"},
+	"EPROTOTYPE":               {"syscall.EPROTOTYPE"},
+	"ERANGE":                   {"syscall.ERANGE"},
+	"EREMCHG":                  {"syscall.EREMCHG"},
+	"EREMOTE":                  {"syscall.EREMOTE"},
+	"EREMOTEIO":                {"syscall.EREMOTEIO"},
+	"ERESTART":                 {"syscall.ERESTART"},
+	"ERFKILL":                  {"syscall.ERFKILL"},
+	"EROFS":                    {"syscall.EROFS"},
+	"ESHUTDOWN":                {"syscall.ESHUTDOWN"},
+	"ESOCKTNOSUPPORT":          {"syscall.ESOCKTNOSUPPORT"},
+	"ESPIPE":                   {"syscall.ESPIPE"},
+	"ESRCH":                    {"syscall.ESRCH"},
+	"ESRMNT":                   {"syscall.ESRMNT"},
+	"ESTALE":                   {"syscall.ESTALE"},
+	"ESTRPIPE":                 {"syscall.ESTRPIPE"},
+	"ETIME":                    {"syscall.ETIME"},
+	"ETIMEDOUT":                {"syscall.ETIMEDOUT"},
+	"ETOOMANYREFS":             {"syscall.ETOOMANYREFS"},
+	"ETXTBSY":                  {"syscall.ETXTBSY"},
+	"EUCLEAN":                  {"syscall.EUCLEAN"},
+	"EUNATCH":                  {"syscall.EUNATCH"},
+	"EUSERS":                   {"syscall.EUSERS"},
+	"EWOULDBLOCK":              {"syscall.EWOULDBLOCK"},
+	"EXDEV":                    {"syscall.EXDEV"},
+	"EXFULL":                   {"syscall.EXFULL"},
+	"ErrAbortHandler":          {"net/http.ErrAbortHandler"},
+	"ErrAdvanceTooFar":         {"bufio.ErrAdvanceTooFar"},
+	"ErrAlgorithm":             {"archive/zip.ErrAlgorithm"},
+	"ErrBadConn":               {"database/sql/driver.ErrBadConn"},
+	"ErrBadPattern":            {"path.ErrBadPattern", "path/filepath.ErrBadPattern"},
+	"ErrBadReadCount":          {"bufio.ErrBadReadCount"},
+	"ErrBareQuote":             {"encoding/csv.ErrBareQuote"},
+	"ErrBodyNotAllowed":        {"net/http.ErrBodyNotAllowed"},
+	"ErrBodyReadAfterClose":    {"net/http.ErrBodyReadAfterClose"},
+	"ErrBufferFull":            {"bufio.ErrBufferFull"},
+	"ErrChecksum":              {"archive/zip.ErrChecksum", "compress/gzip.ErrChecksum", "compress/zlib.ErrChecksum"},
+	"ErrClosed":                {"io/fs.ErrClosed", "net.ErrClosed", "net/http/httputil.ErrClosed", "os.ErrClosed"},
+	"ErrClosedPipe":            {"io.ErrClosedPipe"},
+	"ErrConnClosed":            {"net/http/fcgi.ErrConnClosed"},
+	"ErrConnDone":              {"database/sql.ErrConnDone"},
+	"ErrContentLength":         {"net/http.ErrContentLength"},
+	"ErrDeadlineExceeded":      {"os.ErrDeadlineExceeded"},
+	"ErrDecryption":            {"crypto/rsa.ErrDecryption"},
+	"ErrDictionary":            {"compress/zlib.ErrDictionary"},
+	"ErrDot":                   {"os/exec.ErrDot"},
+	"ErrDuplicateName":         {"encoding/json/jsontext.ErrDuplicateName"},
+	"ErrExist":                 {"io/fs.ErrExist", "os.ErrExist"},
+	"ErrFieldCount":            {"encoding/csv.ErrFieldCount"},
+	"ErrFieldTooLong":          {"archive/tar.ErrFieldTooLong"},
+	"ErrFinalToken":            {"bufio.ErrFinalToken"},
+	"ErrFormat":                {"archive/zip.ErrFormat", "image.ErrFormat"},
+	"ErrHandlerTimeout":        {"net/http.ErrHandlerTimeout"},
+	"ErrHeader":                {"archive/tar.ErrHeader", "compress/gzip.ErrHeader", "compress/zlib.ErrHeader"},
+	"ErrHeaderNotPresent":      {"net/mail.ErrHeaderNotPresent"},
+	"ErrHeaderTooLong":         {"net/http.ErrHeaderTooLong"},
+	"ErrHelp":                  {"flag.ErrHelp"},
+	"ErrHijacked":              {"net/http.ErrHijacked"},
+	"ErrInsecurePath":          {"archive/tar.ErrInsecurePath", "archive/zip.ErrInsecurePath"},
+	"ErrInvalid":               {"io/fs.ErrInvalid", "os.ErrInvalid"},
+	"ErrInvalidMediaParameter": {"mime.ErrInvalidMediaParameter"},
+	"ErrInvalidPublicKey":      {"crypto/dsa.ErrInvalidPublicKey"},
+	"ErrInvalidUnreadByte":     {"bufio.ErrInvalidUnreadByte"},
+	"ErrInvalidUnreadRune":     {"bufio.ErrInvalidUnreadRune"},
+	"ErrLength":                {"encoding/hex.ErrLength"},
+	"ErrLineTooLong":           {"net/http.ErrLineTooLong", "net/http/httputil.ErrLineTooLong"},
+	"ErrMessageTooLarge":       {"mime/multipart.ErrMessageTooLarge"},
+	"ErrMessageTooLong":        {"crypto/rsa.ErrMessageTooLong"},
+	"ErrMissingBoundary":       {"net/http.ErrMissingBoundary"},
+	"ErrMissingContentLength":  {"net/http.ErrMissingContentLength"},
+	"ErrMissingFile":           {"net/http.ErrMissingFile"},
+	"ErrNegativeAdvance":       {"bufio.ErrNegativeAdvance"},
+	"ErrNegativeCount":         {"bufio.ErrNegativeCount"},
+	"ErrNoCookie":              {"net/http.ErrNoCookie"},
+	"ErrNoDeadline":            {"os.ErrNoDeadline"},
+	"ErrNoHandle":              {"os.ErrNoHandle"},
+	"ErrNoLocation":            {"net/http.ErrNoLocation"},
+	"ErrNoProgress":            {"io.ErrNoProgress"},
+	"ErrNoRows":                {"database/sql.ErrNoRows"},
+	"ErrNoSymbols":             {"debug/elf.ErrNoSymbols", "debug/plan9obj.ErrNoSymbols"},
+	"ErrNonStringName":         {"encoding/json/jsontext.ErrNonStringName"},
+	"ErrNotExist":              {"io/fs.ErrNotExist", "os.ErrNotExist"},
+	"ErrNotFat":                {"debug/macho.ErrNotFat"},
+	"ErrNotFound":              {"os/exec.ErrNotFound"},
+	"ErrNotMultipart":          {"net/http.ErrNotMultipart"},
+	"ErrNotSupported":          {"net/http.ErrNotSupported"},
+	"ErrPermission":            {"io/fs.ErrPermission", "os.ErrPermission"},
+	"ErrPersistEOF":            {"net/http/httputil.ErrPersistEOF"},
+	"ErrPipeline":              {"net/http/httputil.ErrPipeline"},
+	"ErrProcessDone":           {"os.ErrProcessDone"},
+	"ErrQuote":                 {"encoding/csv.ErrQuote"},
+	"ErrRange":                 {"strconv.ErrRange"},
+	"ErrRemoveArgument":        {"database/sql/driver.ErrRemoveArgument"},
+	"ErrRequestAborted":        {"net/http/fcgi.ErrRequestAborted"},
+	"ErrSchemeMismatch":        {"net/http.ErrSchemeMismatch"},
+	"ErrServerClosed":          {"net/http.ErrServerClosed"},
+	"ErrShortBody":             {"net/http.ErrShortBody"},
+	"ErrShortBuffer":           {"io.ErrShortBuffer"},
+	"ErrShortWrite":            {"io.ErrShortWrite"},
+	"ErrShutdown":              {"net/rpc.ErrShutdown"},
+	"ErrSkip":                  {"database/sql/driver.ErrSkip"},
+	"ErrSkipAltProtocol":       {"net/http.ErrSkipAltProtocol"},
+	"ErrSyntax":                {"strconv.ErrSyntax"},
+	"ErrTimeout":               {"testing/iotest.ErrTimeout"},
+	"ErrTooLarge":              {"bytes.ErrTooLarge"},
+	"ErrTooLong":               {"bufio.ErrTooLong"},
+	"ErrTrailingComma":         {"encoding/csv.ErrTrailingComma"},
+	"ErrTxDone":                {"database/sql.ErrTxDone"},
+	"ErrUnexpectedEOF":         {"io.ErrUnexpectedEOF"},
+	"ErrUnexpectedTrailer":     {"net/http.ErrUnexpectedTrailer"},
+	"ErrUnknownName":           {"encoding/json/v2.ErrUnknownName"},
+	"ErrUnknownPC":             {"debug/dwarf.ErrUnknownPC"},
+	"ErrUnsupported":           {"errors.ErrUnsupported"},
+	"ErrUnsupportedAlgorithm":  {"crypto/x509.ErrUnsupportedAlgorithm"},
+	"ErrUseLastResponse":       {"net/http.ErrUseLastResponse"},
+	"ErrVerification":          {"crypto/rsa.ErrVerification"},
+	"ErrWaitDelay":             {"os/exec.ErrWaitDelay"},
+	"ErrWriteAfterClose":       {"archive/tar.ErrWriteAfterClose"},
+	"ErrWriteAfterFlush":       {"net/http.ErrWriteAfterFlush"},
+	"ErrWriteToConnected":      {"net.ErrWriteToConnected"},
+	"ErrWriteTooLong":          {"archive/tar.ErrWriteTooLong"},
+	"IncorrectPasswordError":   {"crypto/x509.IncorrectPasswordError"},
+	"SkipAll":                  {"io/fs.SkipAll", "path/filepath.SkipAll"},
+	"SkipDir":                  {"io/fs.SkipDir", "path/filepath.SkipDir"},
+}
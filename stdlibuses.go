@@ -0,0 +1,48 @@
+package errorfinder
+
+import (
+	"context"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// stdlibKinds are the usage analyses that find the standard library errors
+// the scanned packages depend on.
+var stdlibKinds = []UseKind{UseReturns, UseIs, UseAs, UseCompares}
+
+// stdlibUses maps the sentinels and error types of the standard library,
+// named by package path and name, to the places where pkgs return them or
+// compare errors against them. std holds the import paths of the standard
+// library's packages.
+func stdlibUses(pkgs []*packages.Package, std map[string]bool) map[string][]Use {
+	uses := findUses(pkgs, stdlibKinds, nil)
+	for name := range uses {
+		if i := strings.LastIndex(name, "."); i < 0 || !std[name[:i]] {
+			delete(uses, name)
+		}
+	}
+	return uses
+}
+
+// stdPackages lists the import paths of the standard library's packages, as
+// go list std does, with the finder's build tool. Unlike guessing from the
+// paths themselves, this tells them from those of modules whose paths, such
+// as "myapp/internal", have no dot in their first element.
+func (f *Finder) stdPackages(ctx context.Context) (map[string]bool, error) {
+	cfg := &packages.Config{
+		Context: ctx,
+		Mode:    packages.NeedName,
+		Dir:     f.Config.Dir,
+		Env:     f.Config.Env,
+	}
+	pkgs, err := packages.Load(cfg, "std")
+	if err != nil {
+		return nil, &LoadError{Patterns: []string{"std"}, Err: err}
+	}
+	std := make(map[string]bool, len(pkgs))
+	for _, pkg := range pkgs {
+		std[pkg.PkgPath] = true
+	}
+	return std, nil
+}
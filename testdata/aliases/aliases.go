@@ -0,0 +1,17 @@
+package aliases
+
+import (
+	"io/fs"
+
+	"github.com/matttproud/errorfinder/testdata/taxonomy"
+)
+
+// ParseError is re-exported from taxonomy during a migration.
+//
+// Deprecated: Use [taxonomy.ParseError].
+type ParseError = taxonomy.ParseError
+
+type PathError = fs.PathError
+
+// Code is an alias but not of an error type.
+type Code = int
@@ -0,0 +1,41 @@
+package anonymous
+
+import (
+	"errors"
+	"io/fs"
+)
+
+// ErrEmbedded is an anonymous struct that promotes the Error method of the
+// error it embeds.
+var ErrEmbedded = struct{ error }{errors.New("embedded")}
+
+type code int
+
+// ErrCoded pairs an error with a code and the mode of the file involved.
+var ErrCoded = struct {
+	error
+	Code code
+	Mode fs.FileMode
+}{errors.New("coded"), 7, 0o600}
+
+// ErrTemporary is declared as an interface literal.
+var ErrTemporary interface {
+	error
+	Temporary() bool
+} = temporary{}
+
+type temporary struct{}
+
+func (temporary) Error() string   { return "temporary" }
+func (temporary) Temporary() bool { return true }
+
+// ErrPointer points to an anonymous struct.
+var ErrPointer = &struct{ error }{errors.New("pointer")}
+
+// An errorFunc is an error whose message is computed on demand.
+type errorFunc func() string
+
+func (f errorFunc) Error() string { return f() }
+
+// ErrLazy is a function value that implements error.
+var ErrLazy = errorFunc(func() string { return "lazy" })
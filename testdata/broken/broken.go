@@ -0,0 +1,7 @@
+package broken
+
+import "errors"
+
+var ErrBroken = errors.New("broken")
+
+var mismatched int = "not an int"
@@ -0,0 +1,9 @@
+package a
+
+import (
+	"fmt"
+
+	"github.com/matttproud/errorfinder/testdata/brokendep/b"
+)
+
+var ErrA = fmt.Errorf("a: %w", b.ErrB)
@@ -0,0 +1,7 @@
+package b
+
+import "errors"
+
+var ErrB = errors.New("b")
+
+var mismatched int = "not an int"
@@ -0,0 +1,26 @@
+package cgo
+
+/*
+#include <errno.h>
+
+static int fail(void) { return EINVAL; }
+*/
+import "C"
+
+import "errors"
+
+// ErrInvalid is declared in a file that uses cgo.
+var ErrInvalid = errors.New("invalid argument")
+
+// ErrnoError wraps an errno returned by C code.
+type ErrnoError struct{ Errno int }
+
+func (e ErrnoError) Error() string { return "errno" }
+
+// Fail calls into C.
+func Fail() error {
+	if n := C.fail(); n != 0 {
+		return ErrnoError{int(n)}
+	}
+	return nil
+}
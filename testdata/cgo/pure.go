@@ -0,0 +1,6 @@
+package cgo
+
+import "errors"
+
+// ErrPure is declared in a file that does not use cgo.
+var ErrPure = errors.New("pure")
@@ -0,0 +1,35 @@
+package chains
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+var ErrSyntax = errors.New("syntax error")
+
+type ParseError struct{ Line int }
+
+func (e *ParseError) Error() string { return "parse error" }
+func (e *ParseError) Unwrap() error { return ErrSyntax }
+
+type ConfigError struct{ Err *ParseError }
+
+func (e ConfigError) Error() string { return "config error" }
+func (e ConfigError) Unwrap() error { return e.Err }
+
+var (
+	ErrConfig = fmt.Errorf("chains: %w", ConfigError{})
+	ErrEOF    = fmt.Errorf("chains: %w", io.EOF)
+)
+
+// PingError and PongError unwrap to each other.
+type PingError struct{ Pong *PongError }
+
+func (e PingError) Error() string { return "ping" }
+func (e PingError) Unwrap() error { return e.Pong }
+
+type PongError struct{ Ping *PingError }
+
+func (e PongError) Error() string { return "pong" }
+func (e PongError) Unwrap() error { return e.Ping }
@@ -0,0 +1,32 @@
+package codes
+
+// A Code classifies an APIError.
+type Code int
+
+const (
+	CodeUnknown Code = iota
+	CodeNotFound
+	CodePermission
+)
+
+const retries = 3
+
+type APIError struct {
+	Code Code
+	Msg  string
+}
+
+func (e APIError) Error() string { return e.Msg }
+
+type HTTPError struct{ Status int }
+
+func (e *HTTPError) Error() string { return "http error" }
+
+// A Kind classifies a KindError.
+type Kind string
+
+const KindIO Kind = "io"
+
+type KindError struct{ Kind Kind }
+
+func (e KindError) Error() string { return string(e.Kind) }
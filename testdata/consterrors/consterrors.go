@@ -0,0 +1,28 @@
+package consterrors
+
+// Error is an error that can be declared as a constant.
+type Error string
+
+func (e Error) Error() string { return string(e) }
+
+// Errors of the store.
+const (
+	// ErrNotFound indicates a missing key.
+	ErrNotFound       = Error("not found")
+	ErrExists   Error = "already exists"
+
+	errClosed = Error("store: " + "closed")
+)
+
+// Kind is an error code rather than a message.
+type Kind int
+
+func (k Kind) Error() string { return "kind " + string(rune('0'+k)) }
+
+const (
+	KindInternal Kind = iota
+	KindInvalid
+)
+
+// maxKeys is a constant but not an error.
+const maxKeys = 1 << 10
@@ -0,0 +1,66 @@
+package constructors
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+// ConfigError reports a problem with a configuration file.
+type ConfigError struct {
+	Path string
+}
+
+func (e *ConfigError) Error() string { return "bad config: " + e.Path }
+
+// Clone is a method and so not a constructor.
+func (e *ConfigError) Clone() *ConfigError { return &ConfigError{Path: e.Path} }
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string { return "timeout" }
+
+// NewConfigError returns a ConfigError for path.
+func NewConfigError(path string) *ConfigError { return &ConfigError{Path: path} }
+
+// newTimeout hides the concrete type of the error it returns.
+func newTimeout(ok bool) error {
+	if ok {
+		return nil
+	}
+	return timeoutError{}
+}
+
+// pick returns errors of more than one type.
+func pick(config bool) error {
+	if config {
+		return &ConfigError{}
+	}
+	return timeoutError{}
+}
+
+// annotate returns an error created by another function.
+func annotate(err error) error { return fmt.Errorf("annotated: %w", err) }
+
+// Open returns more than an error.
+func Open(path string) (*os.File, error) { return nil, errors.ErrUnsupported }
+
+// ValidationError reports an invalid field.
+type ValidationError struct {
+	Field string
+}
+
+func (e *ValidationError) Error() string { return "invalid " + e.Field }
+
+var errNegative = &ValidationError{Field: "x"}
+
+// Check returns an error it did not construct and so is not a constructor.
+func Check(x int) *ValidationError {
+	if x < 0 {
+		return errNegative
+	}
+	return nil
+}
+
+// invalid is a constructor by the composite literal it returns.
+func invalid(field string) *ValidationError { return &ValidationError{Field: field} }
@@ -0,0 +1,11 @@
+package api
+
+import "errors"
+
+var (
+	ErrShared   = errors.New("shared")
+	ErrService  = errors.New("service")
+	ErrUnshared = errors.New("unshared")
+)
+
+func Fail() error { return ErrUnshared }
@@ -0,0 +1,10 @@
+package cli
+
+import "github.com/matttproud/errorfinder/testdata/consumers/api"
+
+func Exit(err error) int {
+	if err == api.ErrShared {
+		return 2
+	}
+	return 1
+}
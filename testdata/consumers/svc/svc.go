@@ -0,0 +1,11 @@
+package svc
+
+import (
+	"errors"
+
+	"github.com/matttproud/errorfinder/testdata/consumers/api"
+)
+
+func Handle(err error) bool {
+	return errors.Is(err, api.ErrShared) || errors.Is(err, api.ErrService)
+}
@@ -0,0 +1,53 @@
+package dead
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrReturned = errors.New("returned")
+	ErrWrapped  = errors.New("wrapped")
+	ErrBase     = fmt.Errorf("base: %w", ErrWrapped)
+	ErrJoined   = errors.New("joined")
+	ErrCompared = errors.New("compared")
+	ErrSwitched = errors.New("switched")
+	ErrIs       = errors.New("is")
+	ErrDead     = errors.New("dead")
+	errHidden   = errors.New("hidden")
+)
+
+type ReturnedError struct{}
+
+func (ReturnedError) Error() string { return "returned" }
+
+type AsError struct{}
+
+func (*AsError) Error() string { return "as" }
+
+type DeadError struct{}
+
+func (DeadError) Error() string { return "dead" }
+
+func Fail(n int) error {
+	switch n {
+	case 0:
+		return ErrReturned
+	case 1:
+		return ErrBase
+	case 2:
+		return errors.Join(ErrJoined, nil)
+	case 3:
+		return ReturnedError{}
+	}
+	return nil
+}
+
+func Match(err error) bool {
+	switch err {
+	case ErrSwitched:
+		return true
+	}
+	var ae *AsError
+	return err == ErrCompared || errors.Is(err, ErrIs) || errors.As(err, &ae)
+}
@@ -0,0 +1,23 @@
+package discarded
+
+import "errors"
+
+var ErrClosed = errors.New("closed")
+
+type FlushError struct{}
+
+func (*FlushError) Error() string { return "flush failed" }
+
+func Close() error { return ErrClosed }
+
+func flush() error { return &FlushError{} }
+
+func Sync() (int, error) { return 0, flush() }
+
+func Shutdown() {
+	_ = Close()
+	n, _ := Sync()
+	_ = n
+}
+
+var _ = Close()
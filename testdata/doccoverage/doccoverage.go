@@ -0,0 +1,33 @@
+package doccoverage
+
+import "errors"
+
+var (
+	ErrNotFound = errors.New("not found")
+	ErrInvalid  = errors.New("invalid")
+)
+
+type QuotaError struct{}
+
+func (*QuotaError) Error() string { return "quota exceeded" }
+
+// Get returns ErrNotFound when there is no value for key.
+func Get(key string) error {
+	if key == "" {
+		return ErrInvalid
+	}
+	return ErrNotFound
+}
+
+// Put fails with a [*QuotaError] when the store is full.
+func Put(full bool) error {
+	if full {
+		return &QuotaError{}
+	}
+	return ErrInvalid
+}
+
+// Delete removes key, reporting ErrNotFoundOrGone-style errors as nil.
+func Delete(key string) error { return ErrNotFound }
+
+func lookup(key string) error { return ErrNotFound }
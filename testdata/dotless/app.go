@@ -0,0 +1,14 @@
+package app
+
+import (
+	"io"
+
+	"myapp/internal/errs"
+)
+
+func Read(err error) error {
+	if err == io.EOF {
+		return errs.ErrLocal
+	}
+	return err
+}
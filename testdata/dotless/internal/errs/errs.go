@@ -0,0 +1,5 @@
+package errs
+
+import "errors"
+
+var ErrLocal = errors.New("local")
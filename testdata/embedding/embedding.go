@@ -0,0 +1,27 @@
+package embedding
+
+import "strconv"
+
+// CodeError carries a numeric code.
+type CodeError struct{ Code int }
+
+func (e *CodeError) Error() string { return "code " + strconv.Itoa(e.Code) }
+
+// Base adds an operation to a CodeError.
+type Base struct {
+	CodeError
+	Op string
+}
+
+// OpError composes a Base with the error that caused it.
+type OpError struct {
+	Base
+	error
+	Retries int
+}
+
+// List embeds itself through a pointer.
+type List struct {
+	*List
+	error
+}
@@ -0,0 +1,7 @@
+// Package generated mixes hand-written and generated errors.
+package generated
+
+import "errors"
+
+// ErrHandWritten is declared in a file without the marker.
+var ErrHandWritten = errors.New("hand written")
@@ -0,0 +1,42 @@
+package generic
+
+import "strconv"
+
+// WrapError annotates an error with a value of any type.
+type WrapError[T any] struct {
+	Value T
+	Err   error
+}
+
+func (e WrapError[T]) Error() string { return e.Err.Error() }
+
+func (e WrapError[T]) Unwrap() error { return e.Err }
+
+// RangeError reports a value outside of [Min, Max].
+type RangeError[N interface{ ~int | ~float64 }] struct{ Value, Min, Max N }
+
+func (e *RangeError[N]) Error() string { return "out of range" }
+
+// ErrNegative is an instance of a generic error type.
+var ErrNegative = &RangeError[int]{Min: 0, Max: 100}
+
+func ParseInt(s string) (int, error) {
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, WrapError[string]{Value: s, Err: err}
+	}
+	if n < 0 {
+		return 0, ErrNegative
+	}
+	return n, nil
+}
+
+func wrapAll[T any](vals []T, err error) []error {
+	var errs []error
+	for _, v := range vals {
+		errs = append(errs, WrapError[T]{v, err})
+	}
+	return errs
+}
+
+var _ = wrapAll([]float64{1}, nil)
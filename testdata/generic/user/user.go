@@ -0,0 +1,5 @@
+package user
+
+import "github.com/matttproud/errorfinder/testdata/generic"
+
+var ErrBadName error = generic.WrapError[[]byte]{Value: []byte("name")}
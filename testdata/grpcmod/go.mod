@@ -0,0 +1,5 @@
+module example.com/svc
+
+go 1.23
+
+require google.golang.org/grpc v1.60.0
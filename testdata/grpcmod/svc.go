@@ -0,0 +1,19 @@
+package svc
+
+import (
+	"errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	ErrNotFound = status.Error(codes.NotFound, "svc: not found")
+	ErrDenied   = status.Errorf(codes.PermissionDenied, "svc: %s denied", "access")
+	ErrNumbered = status.Error(codes.Code(99), "svc: numbered")
+	ErrPlain    = errors.New("svc: plain")
+)
+
+const unavailable = codes.Code(14)
+
+var ErrUnavailable = status.Error(unavailable, "svc: unavailable")
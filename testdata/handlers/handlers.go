@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+var (
+	ErrNotFound  = errors.New("not found")
+	ErrForbidden = errors.New("forbidden")
+	ErrInternal  = errors.New("internal")
+	ErrUnused    = errors.New("unused")
+)
+
+type QuotaError struct{ Limit int }
+
+func (*QuotaError) Error() string { return "quota exceeded" }
+
+func status(err error) int {
+	switch {
+	case errors.Is(err, ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, ErrForbidden):
+		return http.StatusForbidden
+	}
+	return http.StatusInternalServerError
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	http.Error(w, err.Error(), status(err))
+}
+
+func writeJSON(w http.ResponseWriter, err error) {
+	json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+}
+
+func lookup(r *http.Request) error {
+	if r.URL.Path == "" {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func GetItem(w http.ResponseWriter, r *http.Request) {
+	if err := lookup(r); err != nil {
+		writeError(w, err)
+	}
+}
+
+func Quota(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, &QuotaError{Limit: 10})
+}
+
+type Uploader struct{}
+
+func (Uploader) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	http.Error(w, ErrInternal.Error(), http.StatusInternalServerError)
+}
+
+func Unrelated(err error) bool { return errors.Is(err, ErrUnused) }
@@ -0,0 +1,33 @@
+package helpers
+
+import (
+	"errors"
+	"fmt"
+)
+
+func must(err error) error {
+	if err == nil {
+		panic("nil error")
+	}
+	return err
+}
+
+func Must[T any](v T, err error) T {
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+func parseErr(msg string) (error, error) { return errors.New(msg), nil }
+
+func newErr(msg string) error { return errors.New(msg) }
+
+var (
+	ErrMust    = must(errors.New("must"))
+	ErrGeneric = Must(parseErr("generic"))
+	ErrWrapped = must(fmt.Errorf("wrapped: %w", ErrMust))
+	ErrNested  = must(must(errors.New("nested")))
+	ErrPlain   = errors.New("plain")
+	ErrDirect  = newErr("direct")
+)
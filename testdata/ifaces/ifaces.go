@@ -0,0 +1,32 @@
+package ifaces
+
+import "errors"
+
+var (
+	ErrNotFound = errors.New("not found")
+	ErrReadOnly = errors.New("read only")
+)
+
+type TimeoutError struct{}
+
+func (*TimeoutError) Error() string { return "timeout" }
+
+type Store interface {
+	Get(key string) (string, error)
+	Put(key, value string) error
+	Len() int
+}
+
+type memory struct{}
+
+func (memory) Get(key string) (string, error) { return "", ErrNotFound }
+func (memory) Put(key, value string) error    { return ErrReadOnly }
+func (memory) Len() int                       { return 0 }
+
+type remote struct{}
+
+func (*remote) Get(key string) (string, error) { return "", timeout() }
+func (*remote) Put(key, value string) error    { return nil }
+func (*remote) Len() int                       { return 0 }
+
+func timeout() error { return &TimeoutError{} }
@@ -0,0 +1,39 @@
+// Package illtyped uses errors in code that does not type-check, leaving
+// expressions without types.
+package illtyped
+
+import (
+	"errors"
+	"log"
+)
+
+var ErrIll = errors.New("ill")
+
+func As(err error) bool {
+	return errors.As(err, &undefined)
+}
+
+func Asserts(err error) bool {
+	_, ok := missing.(*undefinedType)
+	switch missing.(type) {
+	case *undefinedType:
+	}
+	return ok
+}
+
+func Keys(err error) string {
+	m := undefinedMap{ErrIll: "ill"}
+	log.Print(err)
+	return m[ErrIll] + undefined[ErrIll]
+}
+
+func Is(err error) bool {
+	switch undefined {
+	case ErrIll:
+	}
+	return errors.Is(err, ErrIll) || err == undefined
+}
+
+func Returns() (error, int) {
+	return undefined, "not an int"
+}
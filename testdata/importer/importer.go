@@ -0,0 +1,5 @@
+package importer
+
+import "github.com/matttproud/errorfinder/testdata/taxonomy"
+
+func Parse() error { return taxonomy.ErrSyntax }
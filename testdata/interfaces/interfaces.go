@@ -0,0 +1,29 @@
+package interfaces
+
+import "time"
+
+// TemporaryError is an error that may go away on retry.
+type TemporaryError interface {
+	error
+	Temporary() bool
+}
+
+// TimeoutError is a temporary error with a deadline.
+type TimeoutError interface {
+	TemporaryError
+	Deadline() (time.Time, bool)
+}
+
+type coder interface {
+	error
+	Code(detail string) int
+}
+
+// Stringer does not embed error.
+type Stringer interface{ String() string }
+
+// Number is a constraint rather than an error contract.
+type Number interface {
+	error
+	~int
+}
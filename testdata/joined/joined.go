@@ -0,0 +1,20 @@
+package joined
+
+import (
+	"errors"
+	"io"
+)
+
+var (
+	ErrA = errors.New("a")
+	ErrB = errors.New("b")
+)
+
+// ErrBoth matches both ErrA and ErrB.
+var ErrBoth = errors.Join(ErrA, ErrB)
+
+var ErrWithEOF = errors.Join(ErrA, io.EOF)
+
+var causes = []error{ErrA, ErrB}
+
+var ErrSpread = errors.Join(causes...)
@@ -0,0 +1,26 @@
+package keys
+
+import "errors"
+
+var (
+	ErrNotFound = errors.New("not found")
+	ErrGone     = errors.New("gone")
+	ErrBusy     = errors.New("busy")
+)
+
+var statuses = map[error]int{
+	ErrNotFound: 404,
+	ErrGone:     410,
+}
+
+func Status(err error) int { return statuses[err] }
+
+func Count(counts map[error]int) { counts[ErrBusy]++ }
+
+func Retryable(err error) bool {
+	switch err {
+	case ErrGone:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,25 @@
+package lateinit
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrLate    error
+	ErrFormat  error
+	ErrTwice   error
+	ErrNever   error
+	ErrEarly   = errors.New("early")
+	ErrA, ErrB error
+)
+
+func init() {
+	ErrLate = errors.New("late")
+	ErrFormat = fmt.Errorf("format: %w", ErrEarly)
+	ErrTwice = errors.New("first")
+	ErrTwice = errors.New("second")
+	ErrA, ErrB = errors.New("a"), errors.New("b")
+}
+
+func init() { ErrEarly = errors.New("replaced") }
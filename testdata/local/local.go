@@ -0,0 +1,30 @@
+package local
+
+import "errors"
+
+// ErrTop is declared at the top level.
+var ErrTop = errors.New("top")
+
+type Parser struct{}
+
+func (p *Parser) Parse() error {
+	// errEOF is local to Parse.
+	var errEOF = errors.New("eof")
+	return errEOF
+}
+
+func Walk(visit func(string) error) error {
+	type skipError struct{ error }
+	return visit("root")
+}
+
+func init() {
+	_ = func() {
+		const errNested = constError("nested")
+		_ = errNested
+	}
+}
+
+type constError string
+
+func (e constError) Error() string { return string(e) }
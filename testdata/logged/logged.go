@@ -0,0 +1,33 @@
+package logged
+
+import (
+	"errors"
+	"log"
+	"log/slog"
+)
+
+var ErrStale = errors.New("stale")
+
+type FetchError struct{ URL string }
+
+func (e *FetchError) Error() string { return "fetch " + e.URL }
+
+func Refresh() {
+	log.Printf("refresh: %v", ErrStale)
+}
+
+func Fetch(url string) error {
+	err := &FetchError{url}
+	slog.Error("fetch failed", "err", err)
+	return err
+}
+
+func Poll(url string) {
+	if err := (&FetchError{url}); url == "" {
+		slog.Warn("poll failed", "err", err)
+	}
+}
+
+func Report(err *FetchError) { audit(err) }
+
+func audit(err error) {}
@@ -0,0 +1,30 @@
+package messages
+
+import "fmt"
+
+type NotFoundError struct{}
+
+func (NotFoundError) Error() string { return "not found" }
+
+type PathError struct {
+	Path string
+	Err  error
+}
+
+func (e *PathError) Error() string { return fmt.Sprintf("open %s: %v", e.Path, e.Err) }
+
+type DynamicError struct{ msg string }
+
+func (e DynamicError) Error() string { return e.msg }
+
+type CodeError struct{ Code int }
+
+func (e CodeError) Error() string {
+	if e.Code == 0 {
+		return "unknown"
+	}
+	return fmt.Sprint("code ", e.Code)
+}
+
+// A WrappedNotFound reports the message of the NotFoundError it embeds.
+type WrappedNotFound struct{ NotFoundError }
@@ -0,0 +1,21 @@
+package multiname
+
+import (
+	"errors"
+	"strconv"
+)
+
+var ErrA, notAnError, ErrB = errors.New("a"), 1, errors.New("b")
+
+// Default and ErrParse are initialized by one call returning two results.
+var Default, ErrParse = strconv.Atoi("x")
+
+var ErrTyped, errNil error = errors.New("typed"), nil
+
+type codeError string
+
+func (e codeError) Error() string { return string(e) }
+
+const ErrCode, limit = codeError("code"), 3
+
+var _ error = ErrA
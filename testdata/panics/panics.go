@@ -0,0 +1,38 @@
+package panics
+
+import "errors"
+
+var ErrFatal = errors.New("fatal")
+
+type InvariantError struct{ Msg string }
+
+func (e InvariantError) Error() string { return e.Msg }
+
+type UnusedError struct{}
+
+func (UnusedError) Error() string { return "unused" }
+
+// Must panics with whatever error it is given, whose type is not known.
+func Must(err error) {
+	if err != nil {
+		panic(err)
+	}
+}
+
+func Check(ok bool) {
+	if !ok {
+		panic(InvariantError{"check"})
+	}
+}
+
+type Parser struct{}
+
+func (p *Parser) Parse() { panic(&InvariantError{"parse"}) }
+
+func Fail() { panic(ErrFatal) }
+
+func Later() {
+	defer func() { panic(ErrFatal) }()
+}
+
+func notAnError() { panic("not an error") }
@@ -0,0 +1,58 @@
+package propagation
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrNotFound = errors.New("not found")
+	ErrInvalid  = errors.New("invalid")
+	ErrUnused   = errors.New("unused")
+)
+
+type TimeoutError struct{}
+
+func (*TimeoutError) Error() string { return "timeout" }
+
+type Backend interface{ Fetch(key string) error }
+
+type disk struct{}
+
+func (disk) Fetch(key string) error { return ErrNotFound }
+
+type remote struct{}
+
+func (remote) Fetch(key string) error { return &TimeoutError{} }
+
+type Client struct{ b Backend }
+
+func NewClient(local bool) *Client {
+	if local {
+		return &Client{disk{}}
+	}
+	return &Client{remote{}}
+}
+
+func (c *Client) Get(key string) error {
+	if err := validate(key); err != nil {
+		return err
+	}
+	return c.b.Fetch(key)
+}
+
+func validate(key string) error {
+	if key == "" {
+		return ErrInvalid
+	}
+	return nil
+}
+
+func Check(key string) error { return validate(key) }
+
+func Describe(key string) string {
+	_ = validate(key)
+	return key
+}
+
+func Wrap() error { return fmt.Errorf("wrap: %w", ErrNotFound) }
@@ -0,0 +1,24 @@
+// Package myerrors is a package's own error constructors.
+package myerrors
+
+// A Code classifies errors.
+type Code int
+
+const CodeTimeout Code = 1
+
+// Error is an error with a code.
+type Error struct {
+	Code Code
+	Msg  string
+}
+
+func (e *Error) Error() string { return e.Msg }
+
+// New returns an error with the given code and message.
+func New(code Code, msg string) error { return &Error{code, msg} }
+
+// Registry creates errors with a shared prefix.
+type Registry struct{ Prefix string }
+
+// New returns an error with the registry's prefix.
+func (r *Registry) New(msg string) error { return &Error{Msg: r.Prefix + msg} }
@@ -0,0 +1,17 @@
+package provenance
+
+import (
+	"errors"
+
+	"github.com/matttproud/errorfinder/testdata/provenance/myerrors"
+)
+
+var registry = &myerrors.Registry{Prefix: "provenance: "}
+
+var (
+	ErrTimeout  = myerrors.New(myerrors.CodeTimeout, "timed out")
+	ErrRegistry = registry.New("registered")
+	ErrPlain    = errors.New("plain")
+	ErrLiteral  = &myerrors.Error{Msg: "literal"}
+	ErrDynamic  = func() error { return errors.New("dynamic") }()
+)
@@ -0,0 +1,52 @@
+package reach
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrPublic   = errors.New("public")
+	errReturned = errors.New("returned")
+	errWrapped  = errors.New("wrapped")
+	errIndirect = errors.New("indirect")
+	errChained  = errors.New("chained")
+	ErrChain    = fmt.Errorf("chain: %w", errChained)
+	errInternal = errors.New("internal")
+	errClosure  = errors.New("closure")
+)
+
+type queryError struct{}
+
+func (*queryError) Error() string { return "query" }
+
+type hiddenError struct{}
+
+func (hiddenError) Error() string { return "hidden" }
+
+type Store struct{}
+
+func (s *Store) Get() error { return errReturned }
+
+func Put() error { return fmt.Errorf("put: %w", errWrapped) }
+
+func Delete() error { return check() }
+
+func check() error { return errIndirect }
+
+func Query() *queryError { return nil }
+
+func lookup(ok bool) error {
+	if !ok {
+		return hiddenError{}
+	}
+	return errInternal
+}
+
+func Walk(f func() error) {}
+
+func Run() { Walk(func() error { return errClosure }) }
+
+type store struct{}
+
+func (store) Get() error { return errInternal }
@@ -0,0 +1,5 @@
+package other
+
+import "github.com/matttproud/errorfinder/testdata/reassigned"
+
+func Clear() { reassigned.ErrRemote = nil }
@@ -0,0 +1,36 @@
+package reassigned
+
+import "errors"
+
+var (
+	ErrStable   = errors.New("stable")
+	ErrReplaced = errors.New("replaced")
+	ErrInit     = errors.New("init")
+	ErrTarget   = errors.New("target")
+	ErrRanged   = errors.New("ranged")
+	ErrTuple    = errors.New("tuple")
+	ErrRemote   = errors.New("remote")
+	ErrShadowed = errors.New("shadowed")
+)
+
+func init() { ErrInit = errors.New("reinit") }
+
+func Reset() { ErrReplaced = errors.New("other") }
+
+func Target(err error) bool { return errors.As(err, &ErrTarget) }
+
+func Range(errs []error) {
+	for _, ErrRanged = range errs {
+	}
+}
+
+func pair() (error, bool) { return nil, false }
+
+func Tuple() { ErrTuple, _ = pair() }
+
+func Shadow() error {
+	ErrShadowed := errors.New("local")
+	return ErrShadowed
+}
+
+func Compare(err error) bool { return err == ErrStable }
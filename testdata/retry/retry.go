@@ -0,0 +1,37 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrUnavailable = errors.New("unavailable")
+	ErrInvalid     = errors.New("invalid")
+	ErrDeadline    = fmt.Errorf("deadline: %w", context.DeadlineExceeded)
+	ErrCanceled    = fmt.Errorf("canceled: %w", context.Canceled)
+	ErrOdd         = errors.New("odd")
+
+	// ErrConflict reports a lost update. It is retryable.
+	ErrConflict = errors.New("conflict")
+
+	// ErrBusyForever reports a permanently busy resource. It is not
+	// retryable.
+	ErrBusyForever = errors.New("busy")
+)
+
+type TemporaryError struct{}
+
+func (*TemporaryError) Error() string   { return "temporary" }
+func (*TemporaryError) Temporary() bool { return true }
+
+type PermanentError struct{}
+
+func (PermanentError) Error() string   { return "permanent" }
+func (PermanentError) Temporary() bool { return false }
+
+type QueryError struct{ Timeouts int }
+
+func (e *QueryError) Error() string { return "query" }
+func (e *QueryError) Timeout() bool { return e.Timeouts > 0 }
@@ -0,0 +1,10 @@
+package shadows
+
+import "errors"
+
+var (
+	EOF         = errors.New("end of input")
+	ErrClosed   = errors.New("closed")
+	ErrNotFound = errors.New("not found")
+	ErrOwn      = errors.New("own")
+)
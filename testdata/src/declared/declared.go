@@ -0,0 +1,15 @@
+package declared // want package:`errors\(ErrExported, errUnexported, Error, ErrInstance\)`
+
+import "errors"
+
+var ErrExported = errors.New("exported") // want `sentinel error ErrExported of type error`
+
+var errUnexported = errors.New("unexported") // want `sentinel error errUnexported of type error`
+
+var notAnError = 1
+
+type Error struct{} // want `structured error Error of type declared.Error`
+
+func (Error) Error() string { return "error" }
+
+var ErrInstance = &Error{} // want `sentinel error ErrInstance of type \*declared.Error`
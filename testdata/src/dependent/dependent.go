@@ -0,0 +1,5 @@
+package dependent
+
+import "declared" // want `declared declares 4 errors`
+
+func F() error { return declared.ErrExported }
@@ -0,0 +1,5 @@
+package quiet // want package:`errors\(ErrQuiet\)`
+
+import "errors"
+
+var ErrQuiet = errors.New("quiet")
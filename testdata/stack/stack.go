@@ -0,0 +1,35 @@
+package stack
+
+import (
+	"errors"
+	"runtime"
+)
+
+var ErrPlain = errors.New("plain")
+
+type FramesError struct{ Frames *runtime.Frames }
+
+func (*FramesError) Error() string { return "frames" }
+
+type CallersError struct{ pcs [32]uintptr }
+
+func (*CallersError) Error() string { return "callers" }
+
+type TracedError struct{}
+
+func (TracedError) Error() string               { return "traced" }
+func (TracedError) StackTrace() []runtime.Frame { return nil }
+
+type trace struct{ pcs []uintptr }
+
+type EmbeddedError struct{ trace }
+
+func (EmbeddedError) Error() string { return "embedded" }
+
+type PlainError struct{ Op string }
+
+func (PlainError) Error() string { return "plain" }
+
+type ListError struct{ Next *ListError }
+
+func (*ListError) Error() string { return "list" }
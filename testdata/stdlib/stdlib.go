@@ -0,0 +1,32 @@
+package stdlib
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"net"
+	"os"
+)
+
+var ErrLocal = errors.New("local")
+
+func Read(r io.Reader) error {
+	if _, err := r.Read(nil); err == io.EOF {
+		return nil
+	}
+	return io.ErrUnexpectedEOF
+}
+
+func Exists(err error) bool { return !errors.Is(err, fs.ErrNotExist) }
+
+func Timeout(err error) bool {
+	var ne net.Error
+	return errors.As(err, &ne) && ne.Timeout()
+}
+
+func Open(name string) error {
+	if name == "" {
+		return ErrLocal
+	}
+	return &os.PathError{Op: "open", Path: name}
+}
@@ -0,0 +1,5 @@
+package tagged
+
+import "errors"
+
+var ErrUntagged = errors.New("untagged")
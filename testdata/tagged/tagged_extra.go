@@ -0,0 +1,7 @@
+//go:build extra
+
+package tagged
+
+import "errors"
+
+var ErrTagged = errors.New("tagged")
@@ -0,0 +1,42 @@
+package taxonomy
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrSyntax indicates malformed input.
+var ErrSyntax = errors.New("syntax error")
+
+// ParseError records where parsing failed.
+type ParseError struct {
+	Line int
+	Err  error
+}
+
+func (e ParseError) Error() string { return "parse error" }
+
+func (e ParseError) Unwrap() error { return e.Err }
+
+type ConfigError struct {
+	ParseError
+	Path string
+}
+
+func (e ConfigError) Unwrap() error { return e.ParseError }
+
+type syntaxError struct{}
+
+func (syntaxError) Error() string { return "syntax error" }
+
+func (syntaxError) Unwrap() error { return ErrSyntax }
+
+var DefaultConfigError = &ConfigError{Path: "/etc/config"}
+
+const prefix = "taxonomy: "
+
+// ErrUnsupported indicates an unsupported operation.
+//
+// Deprecated: Use
+// [errors.ErrUnsupported] instead.
+var ErrUnsupported = fmt.Errorf(prefix+"unsupported %w", errors.ErrUnsupported)
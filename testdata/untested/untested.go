@@ -0,0 +1,22 @@
+package untested
+
+import "errors"
+
+var (
+	ErrCovered   = errors.New("covered")
+	ErrUncovered = errors.New("uncovered")
+)
+
+type UncoveredError struct{}
+
+func (UncoveredError) Error() string { return "uncovered" }
+
+func Open(name string) error {
+	switch name {
+	case "":
+		return ErrCovered
+	case "-":
+		return UncoveredError{}
+	}
+	return ErrUncovered
+}
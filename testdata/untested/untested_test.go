@@ -0,0 +1,14 @@
+package untested
+
+import (
+	"errors"
+	"testing"
+)
+
+var ErrTestOnly = errors.New("test only")
+
+func TestOpen(t *testing.T) {
+	if err := Open(""); !errors.Is(err, ErrCovered) {
+		t.Errorf("Open(%q) = %v, want %v", "", err, ErrCovered)
+	}
+}
@@ -0,0 +1,38 @@
+package unwrap
+
+import "os"
+
+// PathError wraps the error of an operation on a file.
+type PathError struct {
+	Path string
+	Err  *os.PathError
+}
+
+func (e PathError) Error() string { return e.Path + ": " + e.Err.Error() }
+
+func (e *PathError) Unwrap() error { return e.Err }
+
+// MultiError wraps several errors.
+type MultiError struct{ Errs []error }
+
+func (e MultiError) Error() string { return "multiple errors" }
+
+func (e MultiError) Unwrap() []error { return e.Errs }
+
+// CodeError has an Unwrap method that errors.Unwrap ignores.
+type CodeError int
+
+func (e CodeError) Error() string { return "code error" }
+
+func (e CodeError) Unwrap() int { return int(e) }
+
+// Is matches other codes.
+func (e CodeError) Is(target error) bool {
+	_, ok := target.(CodeError)
+	return ok
+}
+
+func (e *MultiError) As(target any) bool { return false }
+
+// Is has the wrong signature for errors.Is.
+func (e PathError) Is(target *os.PathError) bool { return e.Err == target }
@@ -0,0 +1,94 @@
+package uses
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrNotFound = errors.New("not found")
+	ErrUnused   = errors.New("unused")
+)
+
+type QueryError struct{ Query string }
+
+func (e *QueryError) Error() string { return "bad query " + e.Query }
+
+func Find(key string) (string, error) {
+	if key == "" {
+		return "", ErrNotFound
+	}
+	return key, nil
+}
+
+func Query(q string) error {
+	if q == "" {
+		return &QueryError{q}
+	}
+	return fmt.Errorf("query %q: %w", q, ErrNotFound)
+}
+
+type Store struct{}
+
+func (s *Store) Get() error {
+	check := func() error { return ErrNotFound }
+	return check()
+}
+
+func IsNotFound(err error) bool { return errors.Is(err, ErrNotFound) }
+
+func Retry(err error) bool {
+	switch {
+	case errors.Is(err, ErrUnused), errors.Is(err, ErrNotFound):
+		return true
+	}
+	return false
+}
+
+type TimeoutError struct{}
+
+func (TimeoutError) Error() string { return "timeout" }
+
+func AsQuery(err error) bool {
+	var qe *QueryError
+	return errors.As(err, &qe)
+}
+
+func AsQueryValue(err error) bool {
+	var qe QueryError
+	return errors.As(err, &qe)
+}
+
+func AsTimeout(err error) bool {
+	var te *TimeoutError
+	return errors.As(err, &te)
+}
+
+func IsMissing(err error) bool { return err == ErrNotFound }
+
+func Classify(err error) string {
+	switch err {
+	case ErrNotFound:
+		return "missing"
+	case ErrUnused:
+		return "unused"
+	}
+	return "other"
+}
+
+func IsQuery(err error) bool {
+	_, ok := err.(*QueryError)
+	return ok
+}
+
+func Kind(err error) string {
+	switch err.(type) {
+	case *QueryError:
+		return "query"
+	case TimeoutError:
+		return "timeout"
+	case nil:
+		return "none"
+	}
+	return "other"
+}
@@ -0,0 +1,10 @@
+package app
+
+import (
+	"errors"
+
+	"example.com/dep/errs"
+)
+
+// ErrApp is declared by the main module and wraps a vendored error.
+var ErrApp = errors.Join(errs.ErrVendored)
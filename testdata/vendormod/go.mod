@@ -0,0 +1,5 @@
+module example.com/app
+
+go 1.23
+
+require example.com/dep v1.2.0
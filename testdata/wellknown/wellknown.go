@@ -0,0 +1,28 @@
+package wellknown
+
+import "net"
+
+type NetError struct{}
+
+func (*NetError) Error() string   { return "net" }
+func (*NetError) Timeout() bool   { return true }
+func (*NetError) Temporary() bool { return true }
+
+type TimeoutError struct{}
+
+func (TimeoutError) Error() string { return "timeout" }
+func (TimeoutError) Timeout() bool { return true }
+
+type WrapError struct{ Err error }
+
+func (e *WrapError) Error() string { return "wrap: " + e.Err.Error() }
+func (e *WrapError) Unwrap() error { return e.Err }
+
+type PlainError struct{}
+
+func (PlainError) Error() string { return "plain" }
+
+func IsNet(err error) bool {
+	_, ok := err.(net.Error)
+	return ok
+}
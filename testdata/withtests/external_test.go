@@ -0,0 +1,6 @@
+package withtests_test
+
+import "errors"
+
+// ErrExternalTest is declared in an external test package.
+var ErrExternalTest = errors.New("external test")
@@ -0,0 +1,6 @@
+package withtests
+
+import "errors"
+
+// ErrLibrary is declared in a library file.
+var ErrLibrary = errors.New("library")
@@ -0,0 +1,5 @@
+package withtests
+
+import "errors"
+
+var errInternalTest = errors.New("internal test")
@@ -0,0 +1,20 @@
+package wrapping
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+)
+
+var ErrBase = errors.New("base")
+
+var (
+	ErrChain   = fmt.Errorf("chain: %w", ErrBase)
+	ErrPath    = fmt.Errorf("path: %w", &fs.PathError{Op: "open"})
+	ErrBoth    = fmt.Errorf("%w and %w", ErrBase, io.EOF)
+	ErrMixed   = fmt.Errorf("%d: %w", 42, ErrBase)
+	ErrPlain   = fmt.Errorf("plain: %v", ErrBase)
+	ErrNil     = fmt.Errorf("nil: %w", nil)
+	ErrMissing = fmt.Errorf("missing: %w")
+)
@@ -0,0 +1,490 @@
+package errorfinder
+
+import (
+	"encoding/json"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"slices"
+
+	"golang.org/x/tools/go/packages"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// A UseKind names a usage analysis, which finds one kind of use of errors in
+// the scanned packages.
+type UseKind string
+
+// The usage analyses.
+const (
+	UseReturns UseKind = "returns" // Return statements returning the error.
+	UseIs      UseKind = "is"      // Calls to errors.Is with the error as the target.
+	UseAs      UseKind = "as"      // Calls to errors.As with a target of the error's type.
+
+	// Comparisons with == or != and switch cases, which, unlike errors.Is,
+	// fail to match the error once it is wrapped.
+	UseCompares UseKind = "compares"
+
+	// Type assertions and type switch cases asserting the error's type, which,
+	// unlike errors.As, fail to match the error once it is wrapped.
+	UseAsserts UseKind = "asserts"
+
+	// Calls to loggers with the error after which the enclosing block does
+	// not return it, which may swallow the failure. See Config.Loggers.
+	UseLogs UseKind = "logs"
+
+	// Assignments to _ of the error results of calls to functions of the
+	// scanned packages that return the error, directly or through the
+	// functions whose results they return.
+	UseDiscards UseKind = "discards"
+
+	// Sentinels used as map keys or switch case values, which require them to
+	// stay comparable and canonical.
+	UseKeys UseKind = "keys"
+)
+
+// UseKinds lists the usage analyses in the order they are documented.
+var UseKinds = []UseKind{UseReturns, UseIs, UseAs, UseCompares, UseAsserts, UseLogs, UseDiscards, UseKeys}
+
+// useWraps is the usage analysis run only to find dead errors: calls to
+// fmt.Errorf wrapping the error with %w or to errors.Join joining it.
+const useWraps UseKind = "wraps"
+
+// liveKinds are the usage analyses whose uses keep an error from being dead.
+var liveKinds = []UseKind{UseReturns, useWraps, UseIs, UseAs, UseCompares, UseAsserts, UseKeys}
+
+// A Use is a place in the scanned packages where code uses a def.
+type Use struct {
+	Kind     UseKind
+	Func     string // The full name of the enclosing function, if any, such as "(*example.com/p.Parser).Parse".
+	Position token.Position
+
+	// Problem describes a likely mistake in the use, if any, such as an
+	// errors.As target that is a value where only a pointer implements
+	// error.
+	Problem string
+}
+
+func (u Use) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Kind     UseKind `json:"kind"`
+		Func     string  `json:"func,omitempty"`
+		Position string  `json:"position"`
+		Problem  string  `json:"problem,omitempty"`
+	}{u.Kind, u.Func, u.Position.String(), u.Problem})
+}
+
+// String describes the use, such as
+// "returns in example.com/p.Find at p.go:12:3", followed by its problem, if
+// any, in parentheses.
+func (u Use) String() string {
+	s := string(u.Kind)
+	if u.Func != "" {
+		s += " in " + u.Func
+	}
+	s += " at " + u.Position.String()
+	if u.Problem != "" {
+		s += " (" + u.Problem + ")"
+	}
+	return s
+}
+
+// A useSite is a use found in a node: the error used, named by package path
+// and name, where, and the problem with the use, if any.
+type useSite struct {
+	Target  string
+	Pos     token.Pos
+	Problem string
+}
+
+// useAnalyses report the uses of errors in a node, by usage analysis. The
+// analyses for UseLogs and UseDiscards depend on the loggers configured and
+// on the errors that functions return, so findUses makes them with
+// loggedErrors and discardedErrors.
+var useAnalyses = map[UseKind]func(info *types.Info, n ast.Node) []useSite{
+	UseReturns:  returnedErrors,
+	UseIs:       isTargets,
+	UseAs:       asTargets,
+	UseCompares: comparedErrors,
+	UseAsserts:  assertedErrors,
+	UseKeys:     keyedSentinels,
+
+	useWraps: wrappedErrors,
+}
+
+// findUses maps the errors, named by package path and name, that the code of
+// pkgs uses to the uses found by the given analyses in discovery order, with
+// loggers, named as for Config.Loggers, for UseLogs. A use in a function
+// literal is attributed to the function declaring it.
+func findUses(pkgs []*packages.Package, kinds []UseKind, loggers []string) map[string][]Use {
+	uses := make(map[string][]Use)
+	if len(kinds) == 0 {
+		return uses
+	}
+	analyses := make(map[UseKind]func(*types.Info, ast.Node) []useSite)
+	for _, kind := range kinds {
+		analyses[kind] = useAnalyses[kind]
+	}
+	if _, ok := analyses[UseLogs]; ok {
+		analyses[UseLogs] = loggedErrors(loggers)
+	}
+	if _, ok := analyses[UseDiscards]; ok {
+		analyses[UseDiscards] = discardedErrors(allFuncReturns(pkgs))
+	}
+	for _, pkg := range pkgs {
+		if brokenPackage(pkg) {
+			continue
+		}
+		for _, file := range pkg.Syntax {
+			for _, decl := range file.Decls {
+				var fn string
+				if fd, ok := decl.(*ast.FuncDecl); ok {
+					if obj, ok := pkg.TypesInfo.Defs[fd.Name].(*types.Func); ok {
+						fn = obj.FullName()
+					}
+				}
+				ast.Inspect(decl, func(n ast.Node) bool {
+					for _, kind := range kinds {
+						for _, site := range analyses[kind](pkg.TypesInfo, n) {
+							if site.Target == "" {
+								continue
+							}
+							uses[site.Target] = append(uses[site.Target], Use{kind, fn, pkg.Fset.Position(site.Pos), site.Problem})
+						}
+					}
+					return true
+				})
+			}
+		}
+	}
+	return uses
+}
+
+// returnedErrors reports the results of a return statement that are errors.
+func returnedErrors(info *types.Info, n ast.Node) []useSite {
+	ret, ok := n.(*ast.ReturnStmt)
+	if !ok {
+		return nil
+	}
+	var sites []useSite
+	for _, r := range ret.Results {
+		if t := info.TypeOf(r); t != nil && isErrorType(t) {
+			sites = append(sites, useSite{Target: errorTarget(info, r), Pos: r.Pos()})
+		}
+	}
+	return sites
+}
+
+// isTargets reports the target of a call to errors.Is.
+func isTargets(info *types.Info, n ast.Node) []useSite {
+	call, ok := n.(*ast.CallExpr)
+	if !ok || len(call.Args) != 2 || !isFunc(typeutil.StaticCallee(info, call), "errors", "Is") {
+		return nil
+	}
+	return []useSite{{Target: errorTarget(info, call.Args[1]), Pos: call.Args[1].Pos()}}
+}
+
+// asTargets reports the type of the target of a call to errors.As, noting a
+// target that is a value of a type whose pointer alone implements error,
+// which makes errors.As panic, or a pointer to a type whose values implement
+// error, which matches only the errors created as pointers.
+func asTargets(info *types.Info, n ast.Node) []useSite {
+	call, ok := n.(*ast.CallExpr)
+	if !ok || len(call.Args) != 2 || !isFunc(typeutil.StaticCallee(info, call), "errors", "As") {
+		return nil
+	}
+	t := info.TypeOf(call.Args[1])
+	if t == nil {
+		return nil
+	}
+	ptr, ok := t.Underlying().(*types.Pointer)
+	if !ok {
+		return nil
+	}
+	name := func(t types.Type) string { return types.TypeString(t, (*types.Package).Name) }
+	site := useSite{Target: typeNode(ptr.Elem()), Pos: call.Args[1].Pos()}
+	switch t := ptr.Elem(); {
+	case types.IsInterface(t):
+	case !isErrorType(t):
+		if !isErrorType(types.NewPointer(t)) {
+			return nil
+		}
+		site.Problem = "target is " + name(t) + " but only *" + name(t) + " implements error"
+	default:
+		if elem, ok := t.(*types.Pointer); ok && isErrorType(elem.Elem()) {
+			site.Problem = "target is " + name(t) + " but " + name(elem.Elem()) + " values implement error"
+		}
+	}
+	return []useSite{site}
+}
+
+// wrappedErrors reports the errors that a call to fmt.Errorf wraps with %w
+// verbs or that a call to errors.Join joins.
+func wrappedErrors(info *types.Info, n ast.Node) []useSite {
+	call, ok := n.(*ast.CallExpr)
+	if !ok {
+		return nil
+	}
+	var wrapped []ast.Expr
+	switch fn := typeutil.StaticCallee(info, call); {
+	case isFunc(fn, "errors", "Join"):
+		if !call.Ellipsis.IsValid() {
+			wrapped = call.Args
+		}
+	case isFunc(fn, "fmt", "Errorf") && len(call.Args) > 0:
+		tv := info.Types[call.Args[0]]
+		if tv.Value == nil || tv.Value.Kind() != constant.String {
+			return nil
+		}
+		for i, verb := range formatVerbs(constant.StringVal(tv.Value)) {
+			if verb == 'w' && i+1 < len(call.Args) {
+				wrapped = append(wrapped, call.Args[i+1])
+			}
+		}
+	}
+	sites := make([]useSite, len(wrapped))
+	for i, arg := range wrapped {
+		sites[i] = useSite{Target: errorTarget(info, arg), Pos: arg.Pos()}
+	}
+	return sites
+}
+
+// comparedErrors reports the errors that are operands of == or != or the
+// values of the cases of a switch statement, noting that the comparison
+// misses wrapped errors.
+func comparedErrors(info *types.Info, n ast.Node) []useSite {
+	var operands []ast.Expr
+	var problem string
+	switch n := n.(type) {
+	case *ast.BinaryExpr:
+		if n.Op == token.EQL || n.Op == token.NEQ {
+			operands = []ast.Expr{n.X, n.Y}
+			problem = "compared with " + n.Op.String() + " rather than errors.Is"
+		}
+	case *ast.SwitchStmt:
+		if n.Tag == nil {
+			return nil
+		}
+		for _, stmt := range n.Body.List {
+			operands = append(operands, stmt.(*ast.CaseClause).List...)
+		}
+		problem = "switch case rather than errors.Is"
+	}
+	var sites []useSite
+	for _, x := range operands {
+		if t := info.TypeOf(x); t != nil && isErrorType(t) {
+			sites = append(sites, useSite{Target: errorTarget(info, x), Pos: x.Pos(), Problem: problem})
+		}
+	}
+	return sites
+}
+
+// assertedErrors reports the types that type assertions on errors and the
+// cases of type switches on errors assert, noting that the assertion misses
+// wrapped errors.
+func assertedErrors(info *types.Info, n ast.Node) []useSite {
+	var asserted []ast.Expr
+	var problem string
+	switch n := n.(type) {
+	case *ast.TypeAssertExpr:
+		if t := info.TypeOf(n.X); n.Type == nil || t == nil || !isErrorType(t) {
+			return nil // The type switch's guard is handled below.
+		}
+		asserted = []ast.Expr{n.Type}
+		problem = "type assertion rather than errors.As"
+	case *ast.TypeSwitchStmt:
+		var guard ast.Expr
+		switch s := n.Assign.(type) {
+		case *ast.AssignStmt:
+			guard = s.Rhs[0]
+		case *ast.ExprStmt:
+			guard = s.X
+		}
+		if t := info.TypeOf(guard.(*ast.TypeAssertExpr).X); t == nil || !isErrorType(t) {
+			return nil
+		}
+		for _, stmt := range n.Body.List {
+			asserted = append(asserted, stmt.(*ast.CaseClause).List...)
+		}
+		problem = "type switch case rather than errors.As"
+	}
+	var sites []useSite
+	for _, x := range asserted {
+		if t := info.TypeOf(x); t != nil && !types.Identical(t, types.Typ[types.UntypedNil]) && isErrorType(t) {
+			sites = append(sites, useSite{Target: typeNode(t), Pos: x.Pos(), Problem: problem})
+		}
+	}
+	return sites
+}
+
+// loggedErrors makes the analysis reporting the errors passed to the given
+// loggers in a block's statements that the rest of the block does not return,
+// directly or wrapped.
+func loggedErrors(loggers []string) func(info *types.Info, n ast.Node) []useSite {
+	return func(info *types.Info, n ast.Node) []useSite {
+		block, ok := n.(*ast.BlockStmt)
+		if !ok {
+			return nil
+		}
+		var sites []useSite
+		for i, stmt := range block.List {
+			es, ok := stmt.(*ast.ExprStmt)
+			if !ok {
+				continue
+			}
+			call, ok := ast.Unparen(es.X).(*ast.CallExpr)
+			if !ok {
+				continue
+			}
+			if fn := typeutil.StaticCallee(info, call); fn == nil || !slices.Contains(loggers, fn.FullName()) {
+				continue
+			}
+			for _, arg := range call.Args {
+				if t := info.TypeOf(arg); t == nil || !isErrorType(t) || returnsLater(info, block.List[i+1:], arg) {
+					continue
+				}
+				sites = append(sites, useSite{Target: errorTarget(info, arg), Pos: arg.Pos(), Problem: "logged but not returned"})
+			}
+		}
+		return sites
+	}
+}
+
+// returnsLater reports whether any of stmts returns the variable that x names,
+// if any, outside of function literals.
+func returnsLater(info *types.Info, stmts []ast.Stmt, x ast.Expr) bool {
+	var id *ast.Ident
+	switch x := ast.Unparen(x).(type) {
+	case *ast.Ident:
+		id = x
+	case *ast.SelectorExpr:
+		id = x.Sel
+	}
+	obj := info.Uses[id]
+	if obj == nil {
+		return false
+	}
+	returned := false
+	for _, stmt := range stmts {
+		ast.Inspect(stmt, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.FuncLit:
+				return false
+			case *ast.ReturnStmt:
+				for _, r := range n.Results {
+					ast.Inspect(r, func(n ast.Node) bool {
+						if id, ok := n.(*ast.Ident); ok && info.Uses[id] == obj {
+							returned = true
+						}
+						return !returned
+					})
+				}
+			}
+			return !returned
+		})
+	}
+	return returned
+}
+
+// discardedErrors makes the analysis reporting the errors that the calls
+// whose error results are assigned to _ may return, as found by
+// transitiveReturns.
+func discardedErrors(returns map[*types.Func][]string, callees map[*types.Func][]*types.Func) func(info *types.Info, n ast.Node) []useSite {
+	return func(info *types.Info, n ast.Node) []useSite {
+		var lhs, rhs []ast.Expr
+		switch n := n.(type) {
+		case *ast.AssignStmt:
+			lhs, rhs = n.Lhs, n.Rhs
+		case *ast.ValueSpec:
+			for _, name := range n.Names {
+				lhs = append(lhs, name)
+			}
+			rhs = n.Values
+		}
+		var sites []useSite
+		discard := func(value ast.Expr, result int) {
+			call, ok := ast.Unparen(value).(*ast.CallExpr)
+			if !ok {
+				return
+			}
+			fn := typeutil.StaticCallee(info, call)
+			if fn == nil {
+				return
+			}
+			results := fn.Type().(*types.Signature).Results()
+			if result >= results.Len() || !isErrorType(results.At(result).Type()) {
+				return
+			}
+			names := transitiveReturns([]*types.Func{fn}, returns, callees)
+			slices.Sort(names)
+			for _, name := range slices.Compact(names) {
+				sites = append(sites, useSite{Target: name, Pos: call.Pos(), Problem: "error from " + fn.FullName() + " discarded"})
+			}
+		}
+		for i, x := range lhs {
+			if id, ok := x.(*ast.Ident); !ok || id.Name != "_" {
+				continue
+			}
+			switch {
+			case len(lhs) == len(rhs):
+				discard(rhs[i], 0)
+			case len(rhs) == 1:
+				discard(rhs[0], i)
+			}
+		}
+		return sites
+	}
+}
+
+// keyedSentinels reports the sentinels that are keys of map literals, indexes
+// of maps, or the values of the cases of a switch statement.
+func keyedSentinels(info *types.Info, n ast.Node) []useSite {
+	var keys []ast.Expr
+	switch n := n.(type) {
+	case *ast.CompositeLit:
+		if !isMap(info.TypeOf(n)) {
+			return nil
+		}
+		for _, elt := range n.Elts {
+			if kv, ok := elt.(*ast.KeyValueExpr); ok {
+				keys = append(keys, kv.Key)
+			}
+		}
+	case *ast.IndexExpr:
+		if isMap(info.TypeOf(n.X)) {
+			keys = []ast.Expr{n.Index}
+		}
+	case *ast.SwitchStmt:
+		if n.Tag == nil {
+			return nil
+		}
+		for _, stmt := range n.Body.List {
+			keys = append(keys, stmt.(*ast.CaseClause).List...)
+		}
+	}
+	var sites []useSite
+	for _, x := range keys {
+		var id *ast.Ident
+		switch x := ast.Unparen(x).(type) {
+		case *ast.Ident:
+			id = x
+		case *ast.SelectorExpr:
+			id = x.Sel
+		}
+		if v, ok := info.Uses[id].(*types.Var); ok && v.Pkg() != nil && v.Parent() == v.Pkg().Scope() && isErrorType(v.Type()) {
+			sites = append(sites, useSite{Target: objectNode(v), Pos: x.Pos()})
+		}
+	}
+	return sites
+}
+
+// isMap reports whether t, which is nil for an expression without a type, is
+// a map type.
+func isMap(t types.Type) bool {
+	if t == nil {
+		return false
+	}
+	_, ok := t.Underlying().(*types.Map)
+	return ok
+}
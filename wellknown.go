@@ -0,0 +1,85 @@
+package errorfinder
+
+import (
+	"go/token"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// A knownInterface is a well-known interface that structured errors may
+// satisfy, by the name configured.
+type knownInterface struct {
+	Name  string
+	Iface *types.Interface
+}
+
+// resolveInterfaces resolves names, as for Config.KnownInterfaces, against
+// pkgs and their dependencies, omitting those that name no interface or an
+// interface declared in a package that was not loaded.
+func resolveInterfaces(pkgs []*packages.Package, names []string) []knownInterface {
+	loaded := make(map[string]*types.Package)
+	packages.Visit(pkgs, nil, func(pkg *packages.Package) {
+		if pkg.Types != nil {
+			loaded[pkg.PkgPath] = pkg.Types
+		}
+	})
+	var ifaces []knownInterface
+	for _, name := range names {
+		var t types.Type
+		if i := strings.LastIndex(name, "."); !strings.HasPrefix(name, "interface") && i >= 0 {
+			if pkg := loaded[name[:i]]; pkg != nil {
+				if tn, ok := pkg.Scope().Lookup(name[i+1:]).(*types.TypeName); ok {
+					t = tn.Type()
+				}
+			}
+		} else if tv, err := types.Eval(token.NewFileSet(), nil, token.NoPos, name); err == nil && tv.IsType() {
+			t = tv.Type
+		}
+		if t == nil {
+			continue
+		}
+		if iface, ok := t.Underlying().(*types.Interface); ok {
+			ifaces = append(ifaces, knownInterface{name, iface})
+		}
+	}
+	return ifaces
+}
+
+// satisfiedInterfaces reports for each error type declared in pkgs, named by
+// package path and name, the names of ifaces that it or its pointer type
+// satisfies, in the order of ifaces.
+func satisfiedInterfaces(pkgs []*packages.Package, ifaces []knownInterface) map[string][]string {
+	satisfied := make(map[string][]string)
+	for _, pkg := range pkgs {
+		if pkg.Types == nil {
+			continue
+		}
+		scope := pkg.Types.Scope()
+		for _, name := range scope.Names() {
+			tn, ok := scope.Lookup(name).(*types.TypeName)
+			if !ok || tn.IsAlias() || types.IsInterface(tn.Type()) {
+				continue
+			}
+			if named, ok := tn.Type().(*types.Named); !ok || named.TypeParams().Len() > 0 {
+				continue // Such as unsafe.Pointer, which is not a named type.
+			}
+			t, ptr := tn.Type(), types.NewPointer(tn.Type())
+			if !isErrorType(t) && !isErrorType(ptr) {
+				continue
+			}
+			key := objectNode(tn)
+			if _, ok := satisfied[key]; ok {
+				continue // Declared by another variant of the package.
+			}
+			satisfied[key] = nil
+			for _, known := range ifaces {
+				if types.Implements(t, known.Iface) || types.Implements(ptr, known.Iface) {
+					satisfied[key] = append(satisfied[key], known.Name)
+				}
+			}
+		}
+	}
+	return satisfied
+}